@@ -10,7 +10,7 @@ var (
 	BuildFlagHelp         = "Displays more information about the build command"
 	BuildSimple           = "Skipping build step. Build isn't applied to this type\n"
 	BuildStatic           = "Skipping build step. Build isn't applied to the type 'static'\n"
-	BuildNotNecessary     = "Skipping build step. There were no changes detected in your project"
+	BuildNotNecessary     = "Skipping build step. There were no changes detected in your project since the last build; the post-build hook still runs. Use the flag --force to build it anyway\n"
 	FlagTemplate          = "The Edge Application's preset; Inform this flag if you wish to change the project's preset during build"
 	FlagMode              = "The Edge Application's mode; Inform this flag if you wish to change the project's mode during build"
 	FlagWorker            = "Indicates that the constructed code inserts its own worker expression, such as addEventListener(\"fetch\") or similar, without the need to inject a provider"
@@ -18,4 +18,6 @@ var (
 	FlagEntry             = "Code entrypoint; (default: ./main.js)"
 	IsFirewall            = "Indicates whether the function to be run is intended for the Edge Firewall"
 	ProjectConfFlag       = "Relative path to where your custom azion.json and args.json files are stored"
+	FlagVulcanVersion     = "The exact version, such as 2.5.0, or the semver range, such as ^2.5.0, of the azion build tool the project is built with; Inform this flag if you wish to pin it in azion.json"
+	FlagForce             = "Builds the project even when its sources, lockfile, flags and Vulcan version haven't changed since the last build"
 )
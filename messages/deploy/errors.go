@@ -15,4 +15,13 @@ var (
 	ErrorCreateDomain      = errors.New("Failed to create the Domain: %s. Check your settings and try again. If the error persists, contact Azion support")
 	ErrorUpdateDomain      = errors.New("Failed to update the Domain: %s. Check your settings and try again. If the error persists, contact Azion support")
 	ErrorInvalidToken      = errors.New("The configured token is invalid. You must create a new token and configure it to use with the CLI.")
+	ErrorUploadFailed      = errors.New("Failed to upload %d of %d static files. Check the files listed above and run the deploy again")
+	ErrorUploadCanceled    = errors.New("The upload of the static files was canceled. Run the deploy again to upload them")
+	ErrorConcurrency       = errors.New("The value of --concurrency must be at least 1")
+	ErrorKeepVersions      = errors.New("The value of --keep-versions can't be negative")
+	ErrorSnapshot          = errors.New("Failed to save the snapshot of this deploy: %s. Verify if the project's configuration directory is writable and try again")
+	ErrorCreateEnvironment = errors.New("Failed to create the configuration of the environment %s: %s. Verify if the project's configuration directory is writable and try again")
+	ErrorReadJournal       = errors.New("Failed to read the deploy journal: %s. Verify if the journal.json file in the project's configuration directory has a valid JSON format")
+	ErrorWriteJournal      = errors.New("Failed to record the resource created by the deploy in the journal.json file: %s. Verify the write permissions of the project's configuration directory and try again")
+	ErrorRollbackOnFailure = errors.New("%s. Removing the resources created by the deploy also failed: %s. The remaining resources are listed in the journal.json file of the project's configuration directory and are reused by the next deploy")
 )
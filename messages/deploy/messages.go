@@ -18,6 +18,7 @@ var (
 	DeployOutputEdgeApplicationUpdate    = "Updated Edge Application %v with ID %v\n"
 	DeployOutputDomainCreate             = "Created Domain %v with ID %v\n"
 	DeployOutputDomainUpdate             = "Updated Domain %v with ID %v\n"
+	DeployOutputVersionRecorded          = "Recorded deploy version %s. Use 'azion rollback --version %[1]s' to return to it\n"
 	EdgeApplicationDeployPathFlag        = "Path to where your static files are stored"
 	EdgeApplicationDeployProjectConfFlag = "Relative path to where your custom azion.json and args.json files are stored"
 	EdgeApplicationDeploySync            = "Synchronizes the local azion.json file with remote resources"
@@ -34,6 +35,10 @@ var (
 	DeployPropagation                    = "Your application is being deployed to all Azion Edge Locations and it might take a few minutes.\n"
 	UploadStart                          = "Uploading static files\n"
 	UploadSuccessful                     = "\nUpload completed successfully!\n"
+	UploadFailedReport                   = "\nThe following files failed to upload:\n"
+	UploadFailedFile                     = "  - %s: %v\n"
+	UploadSkipped                        = "Static files are unchanged since the last deploy. Reusing the objects stored under prefix %s\n"
+	UploadChangedOnly                    = "Uploading %d static files under a new prefix. Only %d of them are new or changed since prefix %s, but the storage API can't copy the others\n"
 	BucketInUse                          = "This bucket's name is already in use, please try another one\n"
 	AppInUse                             = "This Edge Application's name is already in use, please try another one\n"
 	DomainInUse                          = "This domain's name is already in use, please try another one\n"
@@ -48,8 +53,33 @@ var (
   - Maximum TTL for Edge Application Cache Settings (in seconds): 7200
 
 Do you wish to create a Cache Settings configuration with the above specifications? (y/N)`
-	SkipUpload           = "Your project does not contain a '.edge/storage' folder. Skipping upload of static files"
-	NameInUseBucket      = "Bucket name is already in use. Trying to create bucket with the following name: %s\n"
-	NameInUseApplication = "Edge Application name is already in use. Trying to create Edge Application with the following name: %s\n"
-	NameInUseDomain      = "Domain name is already in use. Trying to create Domain with the following name: %s\n"
+	SkipUpload            = "Your project does not contain a '.edge/storage' folder. Skipping upload of static files"
+	NameInUseBucket       = "Bucket name is already in use. Trying to create bucket with the following name: %s\n"
+	NameInUseApplication  = "Edge Application name is already in use. Trying to create Edge Application with the following name: %s\n"
+	NameInUseDomain       = "Domain name is already in use. Trying to create Domain with the following name: %s\n"
+	NameInUseInstance     = "Function instance name is already in use. Trying to create function instance with the following name: %s\n"
+	DeployFlagDryRun      = "If sent, prints the execution plan of the deploy without creating, updating or deleting any resource"
+	DeployFlagConcurrency = "Number of static files uploaded in parallel"
+	PlanUpload            = "upload"
+	PlanReuse             = "reuse"
+	PlanPurge             = "purge"
+	PlanFilesCount        = "%d files"
+	PlanFilesChanged      = "%d files to upload, %d of them new or changed since prefix %s; the storage API can't copy the others"
+
+	DeployFlagRollbackOnFailure = "If sent, the resources created by a deploy that fails are removed instead of kept for the next deploy to resume from"
+	AskRollbackOnFailure        = "Do you want to remove the resources created by this deploy? (y/N)"
+	JournalResuming             = "Resuming the deploy interrupted at version %s. Reusing the %d resources it created\n"
+	JournalFailed               = "\nThe deploy failed after creating %d resources:\n"
+	JournalEntry                = "  - %s %s (%s)\n"
+	JournalEntryNoID            = "  - %s %s\n"
+	JournalResumePlan           = "These resources were kept and recorded in the journal.json file of the project's configuration directory. Fix the error below and run the deploy again: it resumes from this point and reuses them instead of creating new ones\n"
+	JournalRemoved              = "Removed %s %s\n"
+	JournalRolledBack           = "The resources created by the failed deploy were removed\n"
+	DeployFlagEnvironment       = "Name of the environment to deploy, such as staging or production. Each environment has its own remote resources, .edge/.env.<environment> file and manifest overrides"
+	EnvironmentCreated          = "Created environment %s in %s\n"
+	DeployFlagKeepVersions      = "Number of deploys whose static files are kept in the bucket; the files and the local snapshots of older deploys are deleted after a successful deploy"
+	DeployOutputPrunedVersion   = "Deleted the static files of version %s (%d objects)\n"
+	DeployFlagPrune             = "If sent as false, the resources no longer declared in manifest.json are kept instead of deleted"
+	AskDeleteResources          = "Do you want to delete these %d resources? (y/N)"
+	DeployOutputPruneFailed     = "The deploy succeeded, but the static files of old versions could not be deleted: %s. Run 'azion storage prune' to delete them\n"
 )
@@ -0,0 +1,15 @@
+package deployments
+
+var (
+	Usage            = "deployments"
+	ShortDescription = "Manages the deploy history of the project"
+	LongDescription  = "Manages the history of deploys recorded for the project, which can be restored with 'azion rollback'"
+	FlagHelp         = "Displays more information about the deployments command"
+
+	ListUsage            = "list"
+	ListShortDescription = "Lists the deploys recorded for the project"
+	ListLongDescription  = "Lists the deploys recorded for the project, with their version ID, timestamp, git commit and function code hash"
+	ListFlagHelp         = "Displays more information about the deployments list command"
+	FlagConfigDir        = "Relative path to where your custom azion.json file is stored"
+	FlagEnvironment      = "Name of the environment whose deploys are listed, such as staging or production"
+)
@@ -6,4 +6,5 @@ var (
 	DevShortDescription = "Starts a local development server for the current application"
 	DevLongDescription  = "Starts a local development server for the current application, so it's possible to preview and test it locally before the deployment"
 	IsFirewall          = "Indicates whether the function to be run is intended for the Edge Firewall"
+	FlagConfigDir       = "Relative path to where your custom azion.json and args.json files are stored"
 )
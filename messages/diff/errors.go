@@ -0,0 +1,8 @@
+package diff
+
+import "errors"
+
+var (
+	ErrorNotDeployed = errors.New("The project has no edge application deployed to compare with. Run 'azion deploy' or 'azion export' first")
+	ErrorDrift       = errors.New("Found %d differences between manifest.json and the remote configuration")
+)
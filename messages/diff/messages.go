@@ -0,0 +1,13 @@
+package diff
+
+var (
+	Usage            = "diff"
+	ShortDescription = "Detects drift between manifest.json and the remote configuration"
+	LongDescription  = "Compares every field manifest.json declares for the origins, cache settings and rules tracked in azion.json with their remote configuration, showing the changes made outside of the manifest, such as in the console"
+	FlagHelp         = "Displays more information about the diff command"
+	FlagConfigDir    = "Relative path to where your custom azion.json file is stored"
+	FlagEnvironment  = "Name of the environment to compare, such as staging or production"
+	FlagExitCode     = "Exits with status 1 when drift is found"
+	FlagUntracked    = "Also lists the remote resources that are not declared in manifest.json"
+	NoDrift          = "No drift found between manifest.json and edge application %d\n"
+)
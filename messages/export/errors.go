@@ -0,0 +1,10 @@
+package export
+
+import "errors"
+
+var (
+	ErrorConvertApplicationID = errors.New("Invalid --application-id flag provided. The value must be an integer. Run the command 'azion export --help' for more information")
+	ErrorMissingApplicationID = errors.New("Provide the ID of the edge application to export with the flag --application-id")
+	ErrorWriteManifest        = errors.New("Failed to write the manifest to %s: %s")
+	ErrorWriteAzionJson       = errors.New("Failed to write azion.json: %s")
+)
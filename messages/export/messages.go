@@ -0,0 +1,17 @@
+package export
+
+var (
+	Usage              = "export"
+	ShortDescription   = "Generates the manifest.json of an existing edge application"
+	LongDescription    = "Reads the origins, cache settings and rules of an existing edge application and writes them to manifest.json, along with an azion.json that makes the next 'azion deploy' update these resources instead of creating new ones"
+	FlagHelp           = "Displays more information about the export command"
+	FlagApplicationID  = "Unique identifier of the edge application to export"
+	FlagConfigDir      = "Relative path to where your custom azion.json file is stored"
+	FlagPath           = "Path to the manifest file written"
+	AskApplicationID   = "Enter the ID of the edge application to export:"
+	AskOverwrite       = "The file %s already exists. Do you want to overwrite it? (y/N)"
+	ExportedManifest   = "Exported %d origins, %d cache settings and %d rules of edge application %d to %s\n"
+	ExportedAzionJson  = "The resources are now tracked in %s; the next 'azion deploy' updates them\n"
+	ExportedInstance   = "Edge Function Instance %d run by the rules is tracked as the instance of the project\n"
+	ExportNotOverwrite = "Export canceled; %s was left untouched\n"
+)
@@ -0,0 +1,9 @@
+package hooks
+
+import "errors"
+
+var (
+	ErrorReadingConfig = errors.New("Failed to read the hooks declared in %s: %s. Verify if the file format is JSON or fix its content according to the JSON format specification at https://www.json.org/json-en.html")
+	ErrorLoadingEnv    = errors.New("Failed to load the env file '%s' of the %s hook: %s. Verify if the path declared in config.json is correct")
+	ErrorHookFailed    = errors.New("The %s hook failed: %s. Fix the command declared in %s or remove it and try again")
+)
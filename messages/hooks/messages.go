@@ -0,0 +1,7 @@
+package hooks
+
+var (
+	HookRunning  = "Running %s hook\n"
+	HookCommand  = "$ %s\n"
+	HookFinished = "The %s hook finished successfully\n"
+)
@@ -4,7 +4,7 @@ const (
 	USAGE               = "init"
 	SHORT_DESCRIPTION   = "Initializes an Edge Application from a starter template"
 	LONG_DESCRIPTION    = "Defines primary parameters based on a given name and application preset to start an Edge Application"
-	EXAMPLE             = "$ azion init\n$ azion init --help\n$ azion init --name testproject"
+	EXAMPLE             = "$ azion init\n$ azion init --help\n$ azion init --name testproject\n$ azion init --name testproject --preset vue --template vue --non-interactive"
 	FLAG_NAME           = "The Edge Application's name"
 	FLAG_PRESET         = "The Preset's name"
 	FLAG_TEMPLATE       = "The Template's name"
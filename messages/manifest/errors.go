@@ -13,4 +13,31 @@ var (
 	ErrorUpdateOrigin           = errors.New("Failed to update the origin")
 	ErrorUpdateCache            = errors.New("Failed to update the cache setting")
 	ErrorUpdateRule             = errors.New("Failed to update the rule in Rules Engine")
+	ErrorCreateDeviceGroup      = errors.New("Failed to create the device group")
+	ErrorUpdateDeviceGroup      = errors.New("Failed to update the device group")
+	ErrorDeviceGroupNotFound    = errors.New("Could not find the device group %s. Declare it in the device_groups section of the manifest")
+	ErrorCreateFunction         = errors.New("Failed to create the edge function")
+	ErrorUpdateFunction         = errors.New("Failed to update the edge function")
+	ErrorCreateInstance         = errors.New("Failed to create the edge function instance")
+	ErrorUpdateInstance         = errors.New("Failed to update the edge function instance")
+	ErrorReadFunctionFile       = errors.New("Failed to read the file %s of the edge function %s: %s. Verify the path and try again")
+	ErrorParseFunctionArgs      = errors.New("Failed to parse the arguments in %s. Verify if the file format is JSON and try again")
+	ErrorInstanceNotFound       = errors.New("Could not find the edge function instance %s. Declare it in the functions section of the manifest")
+	ErrorCreateDomain           = errors.New("Failed to create the domain")
+	ErrorUpdateDomain           = errors.New("Failed to update the domain")
+	ErrorReadEnvFile            = errors.New("Failed to read the variables in %s: %s. Verify if the file format is dotenv and try again")
+	ErrorVariableValue          = errors.New("Could not find the value of the variable %s in %s or in the environment. Set it and try again")
+	ErrorListVariables          = errors.New("Failed to list the variables")
+	ErrorCreateVariable         = errors.New("Failed to create the variable")
+	ErrorUpdateVariable         = errors.New("Failed to update the variable")
+	ErrorInvalidManifest        = errors.New("The manifest has %d problems. Fix them and try again:\n%s")
+	ErrorExportApplication      = errors.New("Failed to read the edge application %d: %s. Verify the ID and try again")
+	ErrorExportResources        = errors.New("Failed to read the %s resources of the edge application: %s")
+	ErrorReadOverrides          = errors.New("Failed to read the manifest overrides in %s: %s. Verify if its content matches its format and try again")
+
+	ErrorDecodeManifest           = errors.New("Failed to read the manifest %s: %s. Verify if its content matches its format and try again")
+	ErrorInterpolateManifest      = errors.New("Failed to replace the environment variables of the manifest %s: %s")
+	ErrorMissingManifestVariables = errors.New("%s not set. Set them in the environment or in the .env file, give them a default with ${VAR:-default} or write $${VAR} to keep them as they are")
+	ErrorReorderRules             = errors.New("Failed to reorder the rules in Rules Engine")
+	ErrorPatchManifest            = errors.New("Failed to add the pulled definitions to the manifest %s: %s. Add them by hand or move the manifest aside and pull again")
 )
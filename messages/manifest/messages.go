@@ -10,4 +10,80 @@ const (
 	ManifestUpdateOrigin = "Origin %s with key %s successfully updated\n"
 	ReadingManifest      = "Reading manifest.json file\n"
 	CreatingManifest     = "Creating resources found in manifest.json file\n"
+	PlanNotFoundRemotely = "Tracked in azion.json but not found remotely"
+	PlanNotReferenced    = "Not declared in manifest.json or not referenced by any rule"
+	PlanFunctionPolicy   = "Cache policy required by run_function rules"
+	PlanPhase            = "Phase %s"
+	DriftNotDeclared     = "Not declared in manifest.json"
+	DriftPulled          = "Added to manifest.json from its remote configuration"
+	ApplyingOverrides    = "Applying the manifest overrides found in %s\n"
+	PlanPreventDestroy   = "Protected by lifecycle.prevent_destroy"
+	PlanPruneDisabled    = "Not deleted since --prune=false was sent"
+	PlanNotConfirmed     = "Deletion not confirmed. Deploy with --yes to delete it"
+	DeletingResources    = "The following resources are no longer declared in manifest.json or referenced by any rule and will be deleted:\n"
+	DeleteCandidates     = "The following resources are no longer declared in manifest.json or referenced by any rule:\n"
+	DeletingResource     = "  - %s %s (%s)\n"
+	ManifestKeptResource = "Kept %s %s (%s): %s\n"
+
+	ManifestCreateDeviceGroup = "Device group %s with id %d successfully created\n"
+	ManifestUpdateDeviceGroup = "Device group %s with id %d successfully updated\n"
+	ManifestDeleteDeviceGroup = "Device group %s with id %d successfully deleted\n"
+
+	ManifestCreateFunction         = "Edge Function %s with id %d successfully created\n"
+	ManifestUpdateFunction         = "Edge Function %s with id %d successfully updated\n"
+	ManifestDeleteFunction         = "Edge Function %s with id %d successfully deleted\n"
+	ManifestCreateFunctionInstance = "Edge Function instance %s with id %d successfully created\n"
+	ManifestUpdateFunctionInstance = "Edge Function instance %s with id %d successfully updated\n"
+	ManifestDeleteFunctionInstance = "Edge Function instance %s with id %d successfully deleted\n"
+
+	ManifestCreateDomain = "Domain %s with id %d successfully created\n"
+	ManifestUpdateDomain = "Domain %s with id %d successfully updated\n"
+	ManifestDeleteDomain = "Domain %s with id %d successfully deleted\n"
+
+	ManifestReorderRule = "Rule Engine %s of the %s phase successfully moved from position %d to %d\n"
+	PlanRuleOrder       = "Phase %s, position %d -> %d"
+
+	ManifestCreateVariable = "Variable %s successfully created with value %s\n"
+	ManifestUpdateVariable = "Variable %s successfully updated: %s\n"
+	ManifestDeleteVariable = "Variable %s with uuid %s successfully deleted\n"
+	PlanVariableValue      = "Value %s"
+	PlanVariableDiff       = "%s -> %s"
+
+	ValidationProblem           = "line %d, column %d: %s: %s"
+	ValidationProblemPath       = "%s: %s"
+	ValidationTrailingData      = "unexpected data after the end of the manifest"
+	ValidationUnknownKey        = "unknown key '%s'"
+	ValidationWrongType         = "expected %s, found %s"
+	ValidationInvalidValue      = "invalid value '%s', expected one of: %s"
+	ValidationMissingName       = "cache settings must have a name"
+	ValidationMissingGroupName  = "device groups must have a name"
+	ValidationMissingFuncName   = "functions must have a name"
+	ValidationMissingFuncPath   = "functions must have the path of their code"
+	ValidationMissingInstName   = "function instances must have a name"
+	ValidationMissingDomainName = "domains must have a name"
+	ValidationMissingCnames     = "domains with cname_access_only must have at least one CNAME"
+	ValidationMissingVarKey     = "variables must have a key"
+	ValidationDuplicateName     = "%s named '%s' is declared more than once"
+	ValidationDanglingReference = "%s names '%s', which is not declared in the manifest"
+
+	PatchNotAnObject = "the manifest is not an object"
+	PatchNotAList    = "%s is not written as a list of entries the pulled ones can follow"
+	PatchLostEntries = "the patched manifest doesn't declare the pulled definitions along with the ones it had"
+)
+
+var (
+	Usage            = "manifest"
+	ShortDescription = "Manages the manifest.json file of the project"
+	LongDescription  = "Manages the manifest.json file that describes the origins, cache settings and rules deployed with the project"
+	FlagHelp         = "Displays more information about the manifest command"
+
+	ValidateUsage            = "validate"
+	ValidateShortDescription = "Validates the manifest.json file of the project"
+	ValidateLongDescription  = "Validates the manifest.json file of the project without calling the API: unknown keys, invalid values, duplicate names and rules naming origins or cache settings that are not declared"
+	ValidateFlagHelp         = "Displays more information about the manifest validate command"
+	FlagPath                 = "Path to the manifest file to validate. Defaults to the manifest.json, manifest.yaml or manifest.toml file of the project"
+	FlagEnv                  = "Relative path to the .env file whose variables are replaced in the manifest"
+	FlagConfigDir            = "Relative path to where your custom azion.json file is stored"
+	FlagEnvironment          = "Name of the environment whose manifest overrides are validated along with the manifest"
+	ManifestValid            = "%s is valid\n"
 )
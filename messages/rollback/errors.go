@@ -0,0 +1,15 @@
+package rollback
+
+import "errors"
+
+var (
+	ErrorMissingVersion   = errors.New("Provide the version to roll back to with the flag --version. Run 'azion deployments list' to see the recorded versions")
+	ErrorVersionNotFound  = errors.New("The version %s wasn't found in the deploy history. Run 'azion deployments list' to see the recorded versions")
+	ErrorVersionPruned    = errors.New("The static files of version %s were deleted by --keep-versions, so it can't be restored. Run 'azion deployments list' to see the versions that can be restored")
+	ErrorSnapshotNotFound = errors.New("Failed to read the snapshot of version %s: %s. The files under the deployments directory may have been removed")
+	ErrorSnapshotMismatch = errors.New("The snapshot of version %s doesn't match the code recorded for that deploy. The files under the deployments directory may have been modified")
+	ErrorNotDeployed      = errors.New("The project has no Edge Function deployed. Run 'azion deploy' before rolling back")
+	ErrorParseArgs        = errors.New("Failed to parse the args of the snapshot. Verify if the file's content has a valid JSON format")
+	ErrorUpdateFunction   = errors.New("Failed to update the Edge Function: %s. Check your settings and try again. If the error persists, contact Azion support")
+	ErrorUpdateInstance   = errors.New("Failed to update the Edge Function Instance: %s. Check your settings and try again. If the error persists, contact Azion support")
+)
@@ -0,0 +1,16 @@
+package rollback
+
+var (
+	Usage              = "rollback"
+	ShortDescription   = "Rolls back the project to a previous deploy"
+	LongDescription    = "Points the Edge Function, its instance and the storage prefix back to a previously deployed version and purges the domain's cache"
+	FlagHelp           = "Displays more information about the rollback command"
+	FlagVersion        = "Version ID of the deploy to roll back to. Run 'azion deployments list' to see the recorded versions"
+	FlagConfigDir      = "Relative path to where your custom azion.json and args.json files are stored"
+	FlagEnvironment    = "Name of the environment to roll back, such as staging or production"
+	RollbackFunction   = "Restored the code and args of Edge Function %d\n"
+	RollbackInstance   = "Restored the args of Edge Function Instance %d\n"
+	RollbackOrigin     = "Pointed Origin '%s' to prefix %s\n"
+	RollbackPurge      = "The url cache has been purged: '%s'\n"
+	RollbackSuccessful = "Rolled back to version %s\n"
+)
@@ -1,21 +1,22 @@
 package root
 
 var (
-	RootUsage       = "azion <command> <subcommand> [flags]"
-	RootDescription = "The Azion Command Line Interface is a unified tool to manage your Azion projects and resources"
-	RootHelpFlag    = "Displays more information about the Azion CLI"
-	RootDoNotUpdate = "Do not receive update notification"
-	RootLogDebug    = "Displays log at a debug level"
-	RootLogLevel    = "Set the logging level, \"debug\", \"info\", or \"error\"."
-	RootFlagOut     = "Exports the output to the given <file_path/file_name.ext>"
-	RootFlagFormat  = "Changes the output format passing the json value to the flag"
-	RootFlagNoColor = "Disables colored output, ensuring plain text format."
-	RootLogSilent   = "Silences log completely; mostly used for automation purposes"
-	RootTokenFlag   = "Saves a given Personal Token locally to authorize CLI commands"
-	RootConfigFlag  = "Sets the Azion configuration folder for the current command only, without changing persistent settings."
-	RootYesFlag     = "Answers all yes/no interactions automatically with yes"
-	TokenSavedIn    = "Token saved in %s\n"
-	TokenUsedIn     = "This token will be used by default with all commands"
+	RootUsage              = "azion <command> <subcommand> [flags]"
+	RootDescription        = "The Azion Command Line Interface is a unified tool to manage your Azion projects and resources"
+	RootHelpFlag           = "Displays more information about the Azion CLI"
+	RootDoNotUpdate        = "Do not receive update notification"
+	RootLogDebug           = "Displays log at a debug level"
+	RootLogLevel           = "Set the logging level, \"debug\", \"info\", or \"error\"."
+	RootFlagOut            = "Exports the output to the given <file_path/file_name.ext>"
+	RootFlagFormat         = "Changes the output format passing the json value to the flag"
+	RootFlagNoColor        = "Disables colored output, ensuring plain text format."
+	RootLogSilent          = "Silences log completely; mostly used for automation purposes"
+	RootTokenFlag          = "Saves a given Personal Token locally to authorize CLI commands"
+	RootConfigFlag         = "Sets the Azion configuration folder for the current command only, without changing persistent settings."
+	RootYesFlag            = "Answers all yes/no interactions automatically with yes"
+	RootFlagNonInteractive = "Never prompts: questions are answered with their defaults, or the command fails naming the flag or the question it needs answered. Enabled automatically when stdin isn't a terminal"
+	TokenSavedIn           = "Token saved in %s\n"
+	TokenUsedIn            = "This token will be used by default with all commands"
 
 	// update messages
 	NewVersion        = "There is a new version of Azion CLI available\n"
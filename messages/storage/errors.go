@@ -0,0 +1,13 @@
+package storage
+
+import "errors"
+
+var (
+	ErrorMissingBucket   = errors.New("The flag --bucket is required. Run 'azion list edge-storage bucket' to see your buckets and try again")
+	ErrorMissingRule     = errors.New("Send --keep, --older-than or both to choose which deploy prefixes are deleted")
+	ErrorInvalidKeep     = errors.New("The value of --keep can't be negative")
+	ErrorProtectedPrefix = errors.New("Could not find the prefix currently in use by the project. Run the command from the project's directory, send --config-dir or --function-id and try again")
+	ErrorListObjects     = errors.New("Failed to list the objects of bucket %s: %s")
+	ErrorDeleteObject    = errors.New("Failed to delete object %s: %s. The prefix was partially deleted; run the command again to finish it")
+	ErrorGetFunction     = errors.New("Failed to read the edge function %d: %s")
+)
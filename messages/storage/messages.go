@@ -0,0 +1,27 @@
+package storage
+
+var (
+	Usage            = "storage"
+	ShortDescription = "Manages the static files stored by the deploys of the project"
+	LongDescription  = "Manages the static files the deploys of the project upload to Edge Storage, each of them under its own prefix"
+	FlagHelp         = "Displays more information about the storage command"
+
+	PruneUsage            = "prune"
+	PruneShortDescription = "Deletes the static files of old deploys from a bucket"
+	PruneLongDescription  = "Deletes the objects stored under the prefixes of old deploys, keeping the newest ones and the prefix currently served by the edge function"
+	PruneFlagHelp         = "Displays more information about the storage prune command"
+	FlagBucket            = "Name of the bucket to prune"
+	FlagKeep              = "Number of the newest deploy prefixes to keep"
+	FlagOlderThan         = "Only delete the prefixes of deploys older than this duration, such as 720h"
+	FlagDryRun            = "If sent, lists the prefixes that would be deleted without deleting any object"
+	FlagConfigDir         = "Relative path to where your custom azion.json file is stored; the prefix it references is never deleted"
+	FlagFunctionID        = "ID of an edge function whose storage prefix is never deleted"
+
+	PruneDeleted    = "Deleted %d objects under prefix %s\n"
+	PruneSummary    = "Pruned %d deploy prefixes from bucket %s\n"
+	PruneNothing    = "No deploy prefix of bucket %s needs to be deleted\n"
+	ActionDelete    = "delete"
+	ActionKeep      = "keep"
+	ActionInUse     = "in use"
+	ActionTooRecent = "too recent"
+)
@@ -1,5 +1,7 @@
 package sync
 
 var (
-	ERRORSYNC = "Failed to synchronize local resources with remote resources: %s"
+	ERRORSYNC         = "Failed to synchronize local resources with remote resources: %s"
+	ERRORPULLMANIFEST = "Failed to read the manifest %s to pull into: %s"
+	ERRORPULLWRITE    = "Failed to write the pulled definitions to %s: %s"
 )
@@ -1,14 +1,20 @@
 package sync
 
 const (
-	USAGE             = "sync"
-	SHORTDESCRIPTION  = "Synchronizes the local azion.json file with remote resources"
-	LONGDESCRIPTION   = "Synchronizes your local file containing your existing application resources configuration with remote resources"
-	SYNCMESSAGERULE   = "Adding out of sync rule '%s' to your azion.json file\n"
-	SYNCMESSAGECACHE  = "Adding out of sync cache '%s' to your azion.json file\n"
-	SYNCMESSAGEORIGIN = "Adding out of sync origin '%s' to your azion.json file\n"
-	SYNCMESSAGEENV    = "Adding out of sync variable '%s' to your azion account\n"
-	HELPFLAG          = "Displays more information about the sync command"
-	CONFDIRFLAG       = "Relative path to where your custom azion.json and args.json files are stored"
-	ENVFLAG           = "Relative path to where your custom .env file is stored"
+	USAGE                  = "sync"
+	SHORTDESCRIPTION       = "Synchronizes the local azion.json file with remote resources"
+	LONGDESCRIPTION        = "Synchronizes your local file containing your existing application resources configuration with remote resources"
+	SYNCMESSAGERULE        = "Adding out of sync rule '%s' of the %s phase to your azion.json file\n"
+	SYNCMESSAGERULEPHASE   = "Recording the %s phase of rule '%s' in your azion.json file\n"
+	SYNCMESSAGECACHE       = "Adding out of sync cache '%s' to your azion.json file\n"
+	SYNCMESSAGEORIGIN      = "Adding out of sync origin '%s' to your azion.json file\n"
+	SYNCMESSAGEENV         = "Adding out of sync variable '%s' to your azion account\n"
+	SYNCMESSAGEPULLED      = "Adding the remote definition of %s '%s' (ID %s) to your %s file\n"
+	SYNCMESSAGECONFLICT    = "Conflict in %s '%s': %s is %s in your manifest and %s remotely. Keeping the local definition\n"
+	SYNCMESSAGEMISSING     = "Conflict in %s '%s' (ID %s): %s\n"
+	SYNCMESSAGEPULLNOTHING = "Your manifest already declares every remote resource\n"
+	HELPFLAG               = "Displays more information about the sync command"
+	CONFDIRFLAG            = "Relative path to where your custom azion.json and args.json files are stored"
+	PULLFLAG               = "Writes the definitions of the remote origins, cache settings and rules into the manifest, keeping what it already declares as written, and reports the ones that differ from it"
+	ENVFLAG                = "Relative path to where your custom .env file is stored"
 )
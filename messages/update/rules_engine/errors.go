@@ -13,5 +13,11 @@ var (
 	ErrorStructCriteriaNil    = errors.New("You must inform a criteria")
 	ErrorStructBehaviorsNil   = errors.New("You must inform a behavior")
 	ErrorConvertApplicationID = errors.New("The application ID you provided is invalid. The value must be an integer. You may run the 'azion list edge-application' command to check your application ID")
+	ErrorReorder              = errors.New("Failed to reorder the rules in Rules Engine: %s. Check your settings and try again. If the error persists, contact Azion support")
+	ErrorReorderPhase         = errors.New("Only the rules of the request and response phases can be reordered. Use '--phase request' or '--phase response'")
+	ErrorReorderCanceled      = errors.New("The rules were not reordered")
+	ErrorReorderOrder         = errors.New("Inform the IDs of the rules to run first with '--order'. The '--order' flag is required when reordering")
+	ErrorReorderUnknown       = errors.New("The rules %s given with '--order' aren't rules of the %s phase that can be reordered. The default rule always runs first. You can run the 'azion list rules-engine' command to check the IDs of the rules")
+	ErrorReorderDuplicate     = errors.New("The rule %d is given more than once with '--order'. Inform each rule only once")
 	ErrorConvertRulesID       = errors.New("The Rules Engine ID you provided is invalid. The value must be an integer. You can run the 'azion list rules-engine' command to check your ID.")
 )
@@ -12,9 +12,18 @@ var (
 	FlagRulesEngineID = "Unique identifier for a rule in Rules Engine. The '--rule-id' flag is required"
 	FlagFile          = "Path to a JSON file containing the attributes of the rule that will be updated; you can use - for reading from stdin"
 	FlagHelp          = "Displays more information about the Rules Engine command"
+	FlagReorder       = "Reorders the rules of a phase instead of updating a rule. The '--application-id', '--phase' and '--order' flags are required"
+	FlagOrder         = "IDs of the rules to run first when reordering, in the order they should run; the other rules keep their relative order after them"
+	FlagDryRun        = "Lists the moves '--reorder' would make without applying them"
+
+	ReorderPreview       = "Rule %s (ID %d) will move from position %d to %d\n"
+	OutputReorderSuccess = "Reordered %d rules of the %s phase"
+	OutputReorderNothing = "The rules of the %s phase are already in order"
+	AskReorder           = "Do you want to apply the new order? (y/N)"
 
 	AskInputApplicationID = "Enter the ID of the Edge Application the Rules Engine will be connected to:"
 	AskInputRulesID       = "Enter the ID of the Rules Engine you wish to update:"
 	AskInputPhase         = "Enter the phase of your Rules Engine (request/response):"
 	AskInputPathFile      = "Enter the path of the json to update the Rules Engine:"
+	AskInputOrder         = "Enter the IDs of the rules to run first, in the order they should run (comma separated):"
 )
@@ -11,5 +11,9 @@ var (
 	ErrorMissingFieldUpdateVariables     = errors.New("Required flags are missing. You must provide the --key, --value, and --secret flags as arguments, or the --file flag informing the path to import the file. Run the command 'azion variables <subcommand> --help' to display more information and try again")
 	ErrorSecretFlag                      = errors.New("Invalid --secret flag provided. The value must be 'true' or 'false'. Run the command 'azion variables <subcommand> --help' to display more information and try again")
 	ErrorUpdateVariable                  = errors.New("Failed to update the variable: %s. Check your settings and try again. If the error persists, contact Azion support")
+	ErrorListVariables                   = errors.New("Failed to list the variables: %s. Check your settings and try again. If the error persists, contact Azion support")
+	ErrorReadEnvFile                     = errors.New("Failed to read the .env file %s: %s")
+	ErrorWriteEnvFile                    = errors.New("Failed to write the .env file %s: %s")
+	ErrorReadManifest                    = errors.New("Failed to read the variables the manifest %s declares: %s. Fix the manifest and try again")
 	ErrorCreateItem                      = errors.New("Failed to create the variable: %s. Check your settings and try again. If the error persists, contact Azion support.")
 )
@@ -51,6 +51,45 @@ var (
 	CreateOutputSuccess    = "Created variable with UUID %s\n"
 	CreateHelpFlag         = "Displays more information about the create subcommand"
 
+	// [ pull ]
+	PullUsage            = "pull"
+	PullShortDescription = "Writes your environment variables to a .env file"
+	PullLongDescription  = "Writes your environment variables to a .env file. The values that changed are rewritten where they are, and the comments and keys only the file has are kept. The values of secrets can't be read, so the ones the file already has are kept and the others are written as comments"
+	PullFlagOut          = "Path of the .env file to write"
+	PullFlagDryRun       = "Lists the keys of the .env file that would change without writing it"
+	PullHelpFlag         = "Displays more information about the pull subcommand"
+	PullSecretsComment   = "# Secrets, whose values can't be read. Set them here to push them"
+	PullOutputSuccess    = "Pulled %d variables into %s; %d secrets were written as comments"
+
+	// [ push ]
+	PushUsage            = "push"
+	PushShortDescription = "Updates your environment variables from a .env file"
+	PushLongDescription  = "Creates the variables of a .env file that don't exist yet, updates the ones whose value changed and, with --prune, deletes the ones neither the file nor the manifest has"
+	PushFlagEnv          = "Path of the .env file to push"
+	PushFlagPrune        = "Deletes the variables the .env file doesn't have. Secrets and the variables the manifest declares are never deleted"
+	PushFlagSecrets      = "Keys of the .env file created or updated as secrets. The variables the manifest declares as secrets always are"
+	PushFlagDryRun       = "Lists the changes that would be made without making them"
+	PushHelpFlag         = "Displays more information about the push subcommand"
+	PushValueDiff        = "%s -> %s"
+	PushNotInFile        = "Not in the .env file"
+	PushSecretKept       = "Secret not in the .env file"
+	PushDeclared         = "Declared in the manifest"
+	PushCreated          = "Created variable %s\n"
+	PushUpdated          = "Updated variable %s: %s\n"
+	PushDeleted          = "Deleted variable %s\n"
+	PushNotDeleted       = "Variable %s was not deleted since the deletion was not confirmed. Push with --yes to delete it\n"
+	PushSummary          = "Pushed %d changes from %s\n"
+	PushNothing          = "Your variables are up to date with %s\n"
+	AskPruneVariables    = "Do you want to delete these %d variables? (y/N)"
+
+	ActionAdd    = "add"
+	ActionUpdate = "update"
+	ActionLocal  = "keep, local only"
+	ActionSecret = "secret, commented out"
+	ActionCreate = "create"
+	ActionDelete = "delete"
+	ActionKeep   = "keep"
+
 	// [ ask ]
 	AskKey        = "Enter the Variable's key:"
 	AskValue      = "Enter the Variable's value:"
@@ -0,0 +1,8 @@
+package vulcan
+
+import "errors"
+
+var (
+	ErrorPinnedVersion  = errors.New("Invalid version of the azion build tool: %s. Pin an exact version, such as 2.5.0, or a semver range, such as ^2.5.0, and try again")
+	ErrorResolveVersion = errors.New("Failed to find the release of the azion build tool the range %s accepts: %s. Verify your connection and the pinned range and try again")
+)
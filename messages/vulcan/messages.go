@@ -2,5 +2,7 @@ package vulcan
 
 var (
 	//vulcan version
-	NewMajorVersion = "There is a new version of an azion build tool. Please update your CLI to use these new features"
+	NewMajorVersion   = "There is a new version of an azion build tool. Please update your CLI to use these new features"
+	PinnedMajor       = "Warning: the project pins the version %s of the azion build tool, whose major %d isn't the major %d this CLI supports. The build may fail or produce unexpected results\n"
+	NoMatchingRelease = "no release matches it"
 )
@@ -12,6 +12,10 @@ import (
 	"go.uber.org/zap"
 )
 
+// RulesEnginePhases are the phases rules can be created in, in the order requests go through them. The default rule
+// has a phase of its own.
+var RulesEnginePhases = []string{"request", "response"}
+
 type CacheSettingsResponse interface {
 	GetId() int64
 	GetName() string
@@ -156,10 +160,13 @@ func (c *Client) UpdateInstance(ctx context.Context, req *UpdateInstanceRequest,
 
 	edgeApplicationsResponse, httpResp, err := request.Execute()
 	if err != nil {
-		logger.Debug("Error while updating an Edge Function instance", zap.Error(err))
-		logger.Debug("Status Code", zap.Any("http", httpResp.StatusCode))
-		logger.Debug("Headers", zap.Any("http", httpResp.Header))
-		logger.Debug("Response body", zap.Any("http", httpResp.Body))
+		if httpResp != nil {
+			logger.Debug("Error while updating an Edge Function instance", zap.Error(err))
+			err := utils.LogAndRewindBody(httpResp)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return nil, utils.ErrorPerStatusCode(httpResp, err)
 	}
 
@@ -362,10 +369,13 @@ func (c *Client) EdgeFuncInstancesList(ctx context.Context, opts *contracts.List
 		Sort(opts.Sort).Execute()
 
 	if err != nil {
-		logger.Debug("Error while listing Edge Function instances", zap.Error(err))
-		logger.Debug("Status Code", zap.Any("http", httpResp.StatusCode))
-		logger.Debug("Headers", zap.Any("http", httpResp.Header))
-		logger.Debug("Response body", zap.Any("http", httpResp.Body))
+		if httpResp != nil {
+			logger.Debug("Error while listing Edge Function instances", zap.Error(err))
+			err := utils.LogAndRewindBody(httpResp)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return nil, utils.ErrorPerStatusCode(httpResp, err)
 	}
 	return resp, nil
@@ -377,10 +387,13 @@ func (c *Client) DeleteFunctionInstance(ctx context.Context, appID string, funcI
 
 	httpResp, err := req.Execute()
 	if err != nil {
-		logger.Debug("Error while deleting an Edge Function instance", zap.Error(err))
-		logger.Debug("Status Code", zap.Any("http", httpResp.StatusCode))
-		logger.Debug("Headers", zap.Any("http", httpResp.Header))
-		logger.Debug("Response body", zap.Any("http", httpResp.Body))
+		if httpResp != nil {
+			logger.Debug("Error while deleting an Edge Function instance", zap.Error(err))
+			err := utils.LogAndRewindBody(httpResp)
+			if err != nil {
+				return err
+			}
+		}
 		return utils.ErrorPerStatusCode(httpResp, err)
 	}
 
@@ -392,10 +405,13 @@ func (c *Client) CreateFuncInstances(ctx context.Context, req *CreateInstanceReq
 	resp, httpResp, err := c.apiClient.EdgeApplicationsEdgeFunctionsInstancesAPI.EdgeApplicationsEdgeApplicationIdFunctionsInstancesPost(ctx, applicationID).
 		ApplicationCreateInstanceRequest(req.ApplicationCreateInstanceRequest).Execute()
 	if err != nil {
-		logger.Debug("Error while creating an Edge Function instance", zap.Error(err))
-		logger.Debug("Status Code", zap.Any("http", httpResp.StatusCode))
-		logger.Debug("Headers", zap.Any("http", httpResp.Header))
-		logger.Debug("Response body", zap.Any("http", httpResp.Body))
+		if httpResp != nil {
+			logger.Debug("Error while creating an Edge Function instance", zap.Error(err))
+			err := utils.LogAndRewindBody(httpResp)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return nil, utils.ErrorPerStatusCode(httpResp, err)
 	}
 	return resp.Results, nil
@@ -405,10 +421,13 @@ func (c *Client) GetFuncInstance(ctx context.Context, edgeApplicationID, instanc
 	logger.Debug("Get Edge Function Instance")
 	resp, httpResp, err := c.apiClient.EdgeApplicationsEdgeFunctionsInstancesAPI.EdgeApplicationsEdgeApplicationIdFunctionsInstancesFunctionsInstancesIdGet(ctx, edgeApplicationID, instanceID).Execute()
 	if err != nil {
-		logger.Debug("Error while getting an Edge Function instance", zap.Error(err))
-		logger.Debug("Status Code", zap.Any("http", httpResp.StatusCode))
-		logger.Debug("Headers", zap.Any("http", httpResp.Header))
-		logger.Debug("Response body", zap.Any("http", httpResp.Body))
+		if httpResp != nil {
+			logger.Debug("Error while getting an Edge Function instance", zap.Error(err))
+			err := utils.LogAndRewindBody(httpResp)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return nil, utils.ErrorPerStatusCode(httpResp, err)
 	}
 	return &resp.Results, nil
@@ -426,10 +445,13 @@ func (c *Client) DeviceGroupsList(ctx context.Context, opts *contracts.ListOptio
 		PageSize(opts.PageSize).
 		Sort(opts.Sort).Execute()
 	if err != nil {
-		logger.Debug("Error while listing device groups", zap.Error(err))
-		logger.Debug("Status Code", zap.Any("http", httpResp.StatusCode))
-		logger.Debug("Headers", zap.Any("http", httpResp.Header))
-		logger.Debug("Response body", zap.Any("http", httpResp.Body))
+		if httpResp != nil {
+			logger.Debug("Error while listing device groups", zap.Error(err))
+			err := utils.LogAndRewindBody(httpResp)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return nil, utils.ErrorPerStatusCode(httpResp, err)
 	}
 	return resp, nil
@@ -441,10 +463,13 @@ func (c *Client) DeleteDeviceGroup(ctx context.Context, appID int64, groupID int
 
 	httpResp, err := req.Execute()
 	if err != nil {
-		logger.Debug("Error while deleting a device group", zap.Error(err))
-		logger.Debug("Status Code", zap.Any("http", httpResp.StatusCode))
-		logger.Debug("Headers", zap.Any("http", httpResp.Header))
-		logger.Debug("Response body", zap.Any("http", httpResp.Body))
+		if httpResp != nil {
+			logger.Debug("Error while deleting a device group", zap.Error(err))
+			err := utils.LogAndRewindBody(httpResp)
+			if err != nil {
+				return err
+			}
+		}
 		return utils.ErrorPerStatusCode(httpResp, err)
 	}
 
@@ -455,10 +480,13 @@ func (c *Client) GetDeviceGroups(ctx context.Context, edgeApplicationID, groupID
 	logger.Debug("Get Device Groups")
 	resp, httpResp, err := c.apiClient.EdgeApplicationsDeviceGroupsAPI.EdgeApplicationsEdgeApplicationIdDeviceGroupsDeviceGroupIdGet(ctx, edgeApplicationID, groupID).Execute()
 	if err != nil {
-		logger.Debug("Error while getting a device group", zap.Error(err))
-		logger.Debug("Status Code", zap.Any("http", httpResp.StatusCode))
-		logger.Debug("Headers", zap.Any("http", httpResp.Header))
-		logger.Debug("Response body", zap.Any("http", httpResp.Body))
+		if httpResp != nil {
+			logger.Debug("Error while getting a device group", zap.Error(err))
+			err := utils.LogAndRewindBody(httpResp)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return nil, utils.ErrorPerStatusCode(httpResp, err)
 	}
 	return &resp.Results, nil
@@ -470,10 +498,13 @@ func (c *Client) UpdateDeviceGroup(ctx context.Context, req sdk.PatchDeviceGroup
 
 	deviceGroup, httpResp, err := request.Execute()
 	if err != nil {
-		logger.Debug("Error while updating a device group", zap.Error(err))
-		logger.Debug("Status Code", zap.Any("http", httpResp.StatusCode))
-		logger.Debug("Headers", zap.Any("http", httpResp.Header))
-		logger.Debug("Response body", zap.Any("http", httpResp.Body))
+		if httpResp != nil {
+			logger.Debug("Error while updating a device group", zap.Error(err))
+			err := utils.LogAndRewindBody(httpResp)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return nil, utils.ErrorPerStatusCode(httpResp, err)
 	}
 
@@ -485,17 +516,20 @@ func (c *Client) CreateDeviceGroups(ctx context.Context, req *CreateDeviceGroups
 	resp, httpResp, err := c.apiClient.EdgeApplicationsDeviceGroupsAPI.EdgeApplicationsEdgeApplicationIdDeviceGroupsPost(ctx, applicationID).
 		CreateDeviceGroupsRequest(req.CreateDeviceGroupsRequest).Execute()
 	if err != nil {
-		logger.Debug("Error while creating a device group", zap.Error(err))
-		logger.Debug("Status Code", zap.Any("http", httpResp.StatusCode))
-		logger.Debug("Headers", zap.Any("http", httpResp.Header))
-		logger.Debug("Response body", zap.Any("http", httpResp.Body))
+		if httpResp != nil {
+			logger.Debug("Error while creating a device group", zap.Error(err))
+			err := utils.LogAndRewindBody(httpResp)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return nil, utils.ErrorPerStatusCode(httpResp, err)
 	}
 
 	return &resp.Results, nil
 }
 
-func (c *Client) CreateRulesEngineNextApplication(ctx context.Context, applicationId int64, cacheId int64, typeLang string, mode string, authorize bool) error {
+func (c *Client) CreateRulesEngineNextApplication(ctx context.Context, applicationId int64, cacheId int64, typeLang string, mode string, authorize bool) (RulesEngineResponse, error) {
 	logger.Debug("Create Rules Engine Next Application")
 
 	req := CreateRulesEngineRequest{}
@@ -524,7 +558,7 @@ func (c *Client) CreateRulesEngineNextApplication(ctx context.Context, applicati
 	criteria[0][0].SetInputValue("")
 	req.SetCriteria(criteria)
 
-	_, httpResp, err := c.apiClient.EdgeApplicationsRulesEngineAPI.
+	resp, httpResp, err := c.apiClient.EdgeApplicationsRulesEngineAPI.
 		EdgeApplicationsEdgeApplicationIdRulesEnginePhaseRulesPost(ctx, applicationId, "response").
 		CreateRulesEngineRequest(req.CreateRulesEngineRequest).Execute()
 	if err != nil {
@@ -532,12 +566,12 @@ func (c *Client) CreateRulesEngineNextApplication(ctx context.Context, applicati
 			logger.Debug("Error while creating a Rules Engine", zap.Error(err))
 			err := utils.LogAndRewindBody(httpResp)
 			if err != nil {
-				return err
+				return nil, err
 			}
-			return utils.ErrorPerStatusCode(httpResp, err)
+			return nil, utils.ErrorPerStatusCode(httpResp, err)
 		}
-		return utils.ErrorPerStatusCode(httpResp, err)
+		return nil, utils.ErrorPerStatusCode(httpResp, err)
 	}
 
-	return nil
+	return &resp.Results, nil
 }
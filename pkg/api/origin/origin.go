@@ -90,8 +90,14 @@ func (c *Client) Get(ctx context.Context, edgeApplicationID int64, originKey str
 
 func (c *Client) ListOrigins(ctx context.Context, opts *contracts.ListOptions, edgeApplicationID int64) (*sdk.OriginsResponse, error) {
 	logger.Debug("List Origins")
-	resp, httpResp, err := c.apiClient.EdgeApplicationsOriginsAPI.
-		EdgeApplicationsEdgeApplicationIdOriginsGet(ctx, edgeApplicationID).Execute()
+	req := c.apiClient.EdgeApplicationsOriginsAPI.EdgeApplicationsEdgeApplicationIdOriginsGet(ctx, edgeApplicationID)
+	if opts.Page > 0 {
+		req = req.Page(opts.Page)
+	}
+	if opts.PageSize > 0 {
+		req = req.PageSize(opts.PageSize)
+	}
+	resp, httpResp, err := req.Execute()
 	if err != nil {
 		if httpResp != nil {
 			logger.Debug("Error while listing your origins", zap.Error(err))
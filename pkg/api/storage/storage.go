@@ -107,8 +107,7 @@ func (c *Client) Upload(ctx context.Context, fileOps *contracts.FileOps, conf *c
 	if err != nil {
 		if httpResp != nil {
 			logger.Debug("Error while uploading file <"+fileOps.Path+"> to storage api", zap.Error(err))
-			err := utils.LogAndRewindBody(httpResp)
-			if err != nil {
+			if err := utils.LogAndRewindBody(httpResp); err != nil {
 				return err
 			}
 			return utils.ErrorPerStatusCode(httpResp, err)
@@ -1,11 +1,23 @@
 package variables
 
 import (
+	"strconv"
 	"time"
 
 	sdk "github.com/aziontech/azionapi-go-sdk/variables"
 )
 
+// SecretMask replaces the values of secrets, which the API never returns
+const SecretMask = "********"
+
+// MaskValue returns the value to print for a variable: quoted, or SecretMask for a secret
+func MaskValue(value string, secret bool) string {
+	if secret {
+		return SecretMask
+	}
+	return strconv.Quote(value)
+}
+
 type Request struct {
 	sdk.VariableCreate
 	Uuid string
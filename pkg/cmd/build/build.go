@@ -10,7 +10,9 @@ import (
 
 	"github.com/aziontech/azion-cli/pkg/cmdutil"
 	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/hooks"
 	"github.com/aziontech/azion-cli/pkg/iostreams"
+	"github.com/aziontech/azion-cli/pkg/token"
 	"github.com/aziontech/azion-cli/utils"
 	"github.com/spf13/cobra"
 )
@@ -27,6 +29,8 @@ type BuildCmd struct {
 	EnvLoader             func(path string) ([]string, error)
 	Stat                  func(path string) (fs.FileInfo, error)
 	GetWorkDir            func() (string, error)
+	Hooks                 func(f *cmdutil.Factory) *hooks.Hooks
+	ReadSettings          func() (token.Settings, error)
 	f                     *cmdutil.Factory
 }
 
@@ -42,7 +46,7 @@ func NewCobraCmd(build *BuildCmd) *cobra.Command {
 		Long:          msg.BuildLongDescription,
 		SilenceErrors: true,
 		SilenceUsage:  true,
-		Example:       heredoc.Doc("\n$ azion build\n"),
+		Example:       heredoc.Doc("\n$ azion build\n$ azion build --force\n$ azion build --vulcan-version 2.5.0\n"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			msgs := []string{}
 			return build.run(fields, &msgs)
@@ -57,6 +61,8 @@ func NewCobraCmd(build *BuildCmd) *cobra.Command {
 	buildCmd.Flags().StringVar(&fields.OwnWorker, "use-own-worker", "", msg.FlagWorker)
 	buildCmd.Flags().BoolVar(&fields.IsFirewall, "firewall", false, msg.IsFirewall)
 	buildCmd.Flags().StringVar(&fields.ProjectPath, "config-dir", "azion", msg.ProjectConfFlag)
+	buildCmd.Flags().BoolVar(&fields.Force, "force", false, msg.FlagForce)
+	buildCmd.Flags().StringVar(&fields.VulcanVersion, "vulcan-version", "", msg.FlagVulcanVersion)
 
 	return buildCmd
 }
@@ -80,6 +86,8 @@ func NewBuildCmd(f *cmdutil.Factory) *BuildCmd {
 		WriteFile:             os.WriteFile,
 		Stat:                  os.Stat,
 		GetWorkDir:            utils.GetWorkingDir,
+		Hooks:                 hooks.NewHooks,
+		ReadSettings:          token.ReadSettings,
 		f:                     f,
 	}
 }
@@ -0,0 +1,156 @@
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gitignore "github.com/sabhiram/go-gitignore"
+)
+
+const (
+	// FingerprintFile records the inputs and outputs of the last build, relative to the project config dir
+	FingerprintFile = "build-fingerprint.json"
+	// PathWorker and PathStorage are the outputs of a build
+	PathWorker  = ".edge/worker.js"
+	PathStorage = ".edge/storage"
+)
+
+// lockfiles are always part of the inputs, even when the .gitignore leaves them out
+var lockfiles = []string{"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "bun.lockb"}
+
+// skipDirs are never inputs of a build: they hold its outputs, or dependencies the lockfile already accounts for
+var skipDirs = []string{".git", ".edge", ".vulcan", "node_modules"}
+
+// Fingerprint is what a build is compared against to tell whether it can be skipped
+type Fingerprint struct {
+	Inputs  string `json:"inputs"`
+	Outputs string `json:"outputs"`
+}
+
+// HashInputs returns the sha256 of the source tree of the project, leaving out what its .gitignore ignores, along with
+// its lockfile and the params the build runs with. The files the CLI writes to the config dir are left out, so a
+// deploy doesn't invalidate the build it follows.
+func HashInputs(workDir, confDir string, params ...string) (string, error) {
+	ignore, err := gitignore.CompileIgnoreFile(filepath.Join(workDir, ".gitignore"))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		ignore = gitignore.CompileIgnoreLines()
+	}
+
+	generated := map[string]bool{
+		filepath.Join(confDir, FingerprintFile):    true,
+		filepath.Join(confDir, "azion.json"):       true,
+		filepath.Join(confDir, "deployments"):      true,
+		filepath.Join(confDir, "environments"):     true,
+		filepath.Join(confDir, "deployments.json"): true,
+		filepath.Join(confDir, "files.json"):       true,
+		filepath.Join(confDir, "journal.json"):     true,
+	}
+
+	h := sha256.New()
+	for _, param := range params {
+		h.Write([]byte("param:" + param + "\x00"))
+	}
+	for _, name := range lockfiles {
+		if err := hashFile(h, workDir, name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	err = filepath.WalkDir(workDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(workDir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if entry.IsDir() {
+			for _, dir := range skipDirs {
+				if entry.Name() == dir {
+					return filepath.SkipDir
+				}
+			}
+			if generated[filepath.FromSlash(rel)] || ignore.MatchesPath(rel+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if generated[filepath.FromSlash(rel)] || ignore.MatchesPath(rel) || !entry.Type().IsRegular() {
+			return nil
+		}
+		return hashFile(h, workDir, rel)
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashOutputs returns the sha256 of the function code and the static files a build produced
+func HashOutputs(workDir string) (string, error) {
+	h := sha256.New()
+	if err := hashFile(h, workDir, PathWorker); err != nil {
+		return "", err
+	}
+	err := filepath.WalkDir(filepath.Join(workDir, PathStorage), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(workDir, path)
+		if err != nil {
+			return err
+		}
+		return hashFile(h, workDir, filepath.ToSlash(rel))
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ReadFingerprint returns the fingerprint of the last build; a project that was never built has an empty one
+func ReadFingerprint(path string) (Fingerprint, error) {
+	fingerprint := Fingerprint{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fingerprint, nil
+		}
+		return fingerprint, err
+	}
+	err = json.Unmarshal(data, &fingerprint)
+	return fingerprint, err
+}
+
+func hashFile(h hash.Hash, workDir, rel string) error {
+	file, err := os.Open(filepath.Join(workDir, filepath.FromSlash(rel)))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	h.Write([]byte("file:" + strings.TrimPrefix(rel, "./") + "\x00"))
+	if _, err := io.Copy(h, file); err != nil {
+		return err
+	}
+	h.Write([]byte{0})
+	return nil
+}
@@ -0,0 +1,105 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestHashInputs(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".gitignore":               "dist/\n*.log\n",
+		"main.js":                  "addEventListener('fetch', () => {})",
+		"package-lock.json":        "{}",
+		"dist/bundle.js":           "bundle",
+		"debug.log":                "log",
+		"node_modules/a/a.js":      "a",
+		".edge/worker.js":          "worker",
+		"azion/azion.json":         "{}",
+		"azion/" + FingerprintFile: "{}",
+		"azion/deployments.json":   "{}",
+		"azion/files.json":         "{}",
+		"azion/journal.json":       "{}",
+	})
+
+	hash, err := HashInputs(dir, "azion", "nextjs", "deliver", "@2.5.0")
+	require.NoError(t, err)
+
+	same := func(t *testing.T, params ...string) bool {
+		current, err := HashInputs(dir, "azion", params...)
+		require.NoError(t, err)
+		return current == hash
+	}
+
+	t.Run("ignored and generated files", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{
+			"dist/bundle.js":         "changed",
+			"debug.log":              "changed",
+			"node_modules/a/a.js":    "changed",
+			".edge/worker.js":        "changed",
+			"azion/azion.json":       `{"name": "changed"}`,
+			"azion/deployments.json": "[]",
+			"azion/files.json":       "[]",
+			"azion/journal.json":     "[]",
+		})
+		require.True(t, same(t, "nextjs", "deliver", "@2.5.0"))
+	})
+
+	t.Run("environment files", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{
+			"azion/environments/staging/azion.json":       "{}",
+			"azion/environments/staging/deployments.json": "[]",
+			"azion/environments/staging/files.json":       "[]",
+			"azion/environments/staging/journal.json":     "[]",
+		})
+		require.True(t, same(t, "nextjs", "deliver", "@2.5.0"))
+	})
+
+	t.Run("params", func(t *testing.T) {
+		require.False(t, same(t, "nextjs", "deliver", "@2.6.0"))
+		require.False(t, same(t, "nextjs", "compute", "@2.5.0"))
+	})
+
+	t.Run("sources", func(t *testing.T) {
+		writeFiles(t, dir, map[string]string{"package-lock.json": `{"lockfileVersion": 3}`})
+		require.False(t, same(t, "nextjs", "deliver", "@2.5.0"))
+	})
+}
+
+func TestHashOutputs(t *testing.T) {
+	dir := t.TempDir()
+	_, err := HashOutputs(dir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	writeFiles(t, dir, map[string]string{PathWorker: "worker"})
+	hash, err := HashOutputs(dir)
+	require.NoError(t, err)
+
+	writeFiles(t, dir, map[string]string{PathStorage + "/index.html": "<html></html>"})
+	withStorage, err := HashOutputs(dir)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, withStorage)
+}
+
+func TestReadFingerprint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FingerprintFile)
+	fingerprint, err := ReadFingerprint(path)
+	require.NoError(t, err)
+	require.Empty(t, fingerprint.Inputs)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"inputs": "in", "outputs": "out"}`), 0644))
+	fingerprint, err = ReadFingerprint(path)
+	require.NoError(t, err)
+	require.Equal(t, Fingerprint{Inputs: "in", Outputs: "out"}, fingerprint)
+}
@@ -1,12 +1,18 @@
 package build
 
 import (
+	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	msg "github.com/aziontech/azion-cli/messages/build"
 	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/hooks"
 	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/output"
+	"github.com/aziontech/azion-cli/utils"
 	"go.uber.org/zap"
 )
 
@@ -24,12 +30,25 @@ func (cmd *BuildCmd) run(fields *contracts.BuildInfo, msgs *[]string) error {
 func RunBuildCmdLine(cmd *BuildCmd, fields *contracts.BuildInfo, msgs *[]string) error {
 	var err error
 
+	// when called by another command, that command prints the messages
+	standalone := len(*msgs) == 0
+
 	conf, err := cmd.GetAzionJsonContent(fields.ProjectPath)
 	if err != nil {
 		logger.Debug("Error while building your project", zap.Error(err))
 		return msg.ErrorBuilding
 	}
 
+	workDir, err := cmd.GetWorkDir()
+	if err != nil {
+		return err
+	}
+
+	buildHooks := cmd.Hooks(cmd.f)
+	if err := buildHooks.Run(workDir, fields.ProjectPath, hooks.PreBuild, msgs); err != nil {
+		return err
+	}
+
 	if fields.Preset != "" {
 		conf.Preset = fields.Preset
 	}
@@ -38,6 +57,10 @@ func RunBuildCmdLine(cmd *BuildCmd, fields *contracts.BuildInfo, msgs *[]string)
 		conf.Mode = fields.Mode
 	}
 
+	if fields.VulcanVersion != "" {
+		conf.VulcanVersion = fields.VulcanVersion
+	}
+
 	var vulcanParams string
 
 	if fields.Entry != "" {
@@ -64,6 +87,125 @@ func RunBuildCmdLine(cmd *BuildCmd, fields *contracts.BuildInfo, msgs *[]string)
 		vulcanParams += " --firewall "
 	}
 
-	return vulcan(cmd, conf, vulcanParams, fields, msgs)
+	pinned, err := resolveVulcan(cmd, conf)
+	if err != nil {
+		return err
+	}
+
+	// a version given with --vulcan-version is pinned in azion.json once it resolves, so the builds and dev servers
+	// that follow run it without the flag, even when this build is skipped
+	if fields.VulcanVersion != "" {
+		if err := cmd.WriteAzionJsonContent(conf, fields.ProjectPath); err != nil {
+			logger.Debug("Error while writing azion.json file", zap.Error(err))
+			return utils.ErrorWritingAzionJsonFile
+		}
+	}
+
+	// the fingerprint is checked before the latest Vulcan version is looked up, so a skipped build of an unpinned
+	// project doesn't reach the network: it holds the latest version the CLI looked up, which the settings keep
+	version := pinned
+	if version == "" {
+		version = cmd.lastVulcanVersion()
+	}
+	fingerprintPath := filepath.Join(workDir, fields.ProjectPath, FingerprintFile)
+	hashInputs := func(version string) string {
+		inputs, err := HashInputs(workDir, fields.ProjectPath,
+			strings.ToLower(conf.Preset), strings.ToLower(conf.Mode), vulcanParams, version)
+		if err != nil {
+			// the build runs as it would without the cache
+			logger.Debug("Error while computing the fingerprint of the build inputs", zap.Error(err))
+			return ""
+		}
+		return inputs
+	}
+	inputs := hashInputs(version)
+
+	if !fields.Force && inputs != "" && cmd.upToDate(workDir, fingerprintPath, inputs) {
+		logger.FInfoFlags(cmd.Io.Out, msg.BuildNotNecessary, cmd.f.Format, cmd.f.Out)
+		*msgs = append(*msgs, msg.BuildNotNecessary)
+	} else {
+		vul, err := checkVulcan(cmd, pinned)
+		if err != nil {
+			return err
+		}
+		if err := vulcan(cmd, vul, conf, vulcanParams, fields, msgs); err != nil {
+			return err
+		}
+		// the lookup refreshes the version the settings keep; the fingerprint records the one the build ran
+		if pinned == "" && inputs != "" {
+			if latest := cmd.lastVulcanVersion(); latest != version {
+				inputs = hashInputs(latest)
+			}
+		}
+	}
 
+	// the post-build hook runs even when the build is skipped, since the steps it adds to the build may depend on
+	// more than its inputs
+	if err := buildHooks.Run(workDir, fields.ProjectPath, hooks.PostBuild, msgs); err != nil {
+		return err
+	}
+	// the outputs are recorded after the hook, which may change them
+	if inputs != "" {
+		cmd.writeFingerprint(workDir, fingerprintPath, inputs)
+	}
+
+	if !standalone {
+		return nil
+	}
+
+	outSlice := output.SliceOutput{
+		Messages: *msgs,
+		GeneralOutput: output.GeneralOutput{
+			Out:   cmd.f.IOStreams.Out,
+			Flags: cmd.f.Flags,
+		},
+	}
+
+	return output.Print(&outSlice)
+}
+
+// lastVulcanVersion returns the latest Vulcan version the CLI looked up, which every build that runs refreshes in the
+// settings. A project that doesn't pin a version is built with it, so a new release changes the fingerprint
+func (cmd *BuildCmd) lastVulcanVersion() string {
+	settings, err := cmd.ReadSettings()
+	if err != nil {
+		logger.Debug("Error while reading the settings", zap.Error(err))
+		return ""
+	}
+	return strings.TrimSpace(settings.LastVulcanVersion)
+}
+
+// upToDate tells whether the inputs match the ones of the last build and its outputs were left as it produced them
+func (cmd *BuildCmd) upToDate(workDir, fingerprintPath, inputs string) bool {
+	last, err := ReadFingerprint(fingerprintPath)
+	if err != nil {
+		logger.Debug("Error while reading the fingerprint of the last build", zap.Error(err))
+		return false
+	}
+	if last.Inputs != inputs {
+		return false
+	}
+	outputs, err := HashOutputs(workDir)
+	if err != nil {
+		logger.Debug("Error while computing the fingerprint of the build outputs", zap.Error(err))
+		return false
+	}
+	return last.Outputs == outputs
+}
+
+// writeFingerprint records the inputs and outputs of a build; failing to do so only means the next build isn't skipped
+func (cmd *BuildCmd) writeFingerprint(workDir, fingerprintPath, inputs string) {
+	outputs, err := HashOutputs(workDir)
+	if err != nil {
+		logger.Debug("Error while computing the fingerprint of the build outputs", zap.Error(err))
+		return
+	}
+	data, err := json.MarshalIndent(Fingerprint{Inputs: inputs, Outputs: outputs}, "", "  ")
+	if err != nil {
+		logger.Debug("Error while marshalling the build fingerprint", zap.Error(err))
+		return
+	}
+	if err := cmd.WriteFile(fingerprintPath, data, 0644); err != nil {
+		logger.Debug("Error while writing the build fingerprint", zap.Error(err))
+	}
 }
@@ -0,0 +1,213 @@
+package build
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	msg "github.com/aziontech/azion-cli/messages/build"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/hooks"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/aziontech/azion-cli/pkg/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRunBuildCmdLine(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	t.Run("skipped build runs the post-build hook without looking up the Vulcan version", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"main.js":  "addEventListener('fetch', () => {})",
+			PathWorker: "worker",
+		})
+
+		inputs, err := HashInputs(dir, "azion", "javascript", "compute", "", "2.5.0")
+		require.NoError(t, err)
+		outputs, err := HashOutputs(dir)
+		require.NoError(t, err)
+		writeFiles(t, dir, map[string]string{
+			"azion/" + FingerprintFile: `{"inputs": "` + inputs + `", "outputs": "` + outputs + `"}`,
+		})
+
+		f, stdout, _ := testutils.NewFactory(&httpmock.Registry{})
+		cmd := NewBuildCmd(f)
+		cmd.GetWorkDir = func() (string, error) { return dir, nil }
+		cmd.GetAzionJsonContent = func(string) (*contracts.AzionApplicationOptions, error) {
+			return &contracts.AzionApplicationOptions{Preset: "javascript", Mode: "compute"}, nil
+		}
+		cmd.ReadSettings = func() (token.Settings, error) {
+			return token.Settings{LastVulcanVersion: "2.5.0\n"}, nil
+		}
+		cmd.CommandRunner = func(*cmdutil.Factory, string, []string) (string, error) {
+			t.Fatal("the Vulcan version should not be looked up")
+			return "", nil
+		}
+		cmd.CommandRunInteractive = func(*cmdutil.Factory, string) error {
+			t.Fatal("the build should be skipped")
+			return nil
+		}
+		ran := []string{}
+		cmd.Hooks = func(f *cmdutil.Factory) *hooks.Hooks {
+			h := hooks.NewHooks(f)
+			h.ReadConfig = func(string) (*contracts.AzionApplicationConfig, error) {
+				config := &contracts.AzionApplicationConfig{}
+				config.BuildData.PostCmd = "npm run sourcemaps"
+				return config, nil
+			}
+			h.Runner = func(dir string, envVars []string, comm string, stdout, stderr io.Writer) error {
+				ran = append(ran, comm)
+				return nil
+			}
+			return h
+		}
+
+		msgs := []string{}
+		require.NoError(t, RunBuildCmdLine(cmd, &contracts.BuildInfo{ProjectPath: "azion"}, &msgs))
+		require.Contains(t, stdout.String(), msg.BuildNotNecessary)
+		require.Equal(t, []string{"npm run sourcemaps"}, ran)
+
+		last, err := ReadFingerprint(filepath.Join(dir, "azion", FingerprintFile))
+		require.NoError(t, err)
+		require.Equal(t, Fingerprint{Inputs: inputs, Outputs: outputs}, last)
+	})
+
+	t.Run("a new Vulcan release looked up since the last build rebuilds an unpinned project", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"main.js":  "addEventListener('fetch', () => {})",
+			PathWorker: "worker",
+		})
+
+		inputs, err := HashInputs(dir, "azion", "javascript", "compute", "", "2.5.0")
+		require.NoError(t, err)
+		outputs, err := HashOutputs(dir)
+		require.NoError(t, err)
+		writeFiles(t, dir, map[string]string{
+			"azion/" + FingerprintFile: `{"inputs": "` + inputs + `", "outputs": "` + outputs + `"}`,
+		})
+
+		f, _, _ := testutils.NewFactory(&httpmock.Registry{})
+		cmd := NewBuildCmd(f)
+		cmd.GetWorkDir = func() (string, error) { return dir, nil }
+		cmd.GetAzionJsonContent = func(string) (*contracts.AzionApplicationOptions, error) {
+			return &contracts.AzionApplicationOptions{Preset: "javascript", Mode: "compute"}, nil
+		}
+		cmd.ReadSettings = func() (token.Settings, error) {
+			return token.Settings{LastVulcanVersion: "2.6.0"}, nil
+		}
+		// the build isn't skipped, so it looks up the version it runs
+		lookedUp := errors.New("looked up")
+		cmd.CommandRunner = func(_ *cmdutil.Factory, comm string, _ []string) (string, error) {
+			require.Equal(t, "npm show edge-functions version", comm)
+			return "", lookedUp
+		}
+		cmd.Hooks = func(f *cmdutil.Factory) *hooks.Hooks {
+			h := hooks.NewHooks(f)
+			h.ReadConfig = func(string) (*contracts.AzionApplicationConfig, error) {
+				return &contracts.AzionApplicationConfig{}, nil
+			}
+			return h
+		}
+
+		msgs := []string{}
+		require.ErrorIs(t, RunBuildCmdLine(cmd, &contracts.BuildInfo{ProjectPath: "azion"}, &msgs), lookedUp)
+	})
+
+	t.Run("the version given with --vulcan-version is pinned when the build is skipped", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"main.js":  "addEventListener('fetch', () => {})",
+			PathWorker: "worker",
+		})
+
+		inputs, err := HashInputs(dir, "azion", "javascript", "compute", "", "2.5.0")
+		require.NoError(t, err)
+		outputs, err := HashOutputs(dir)
+		require.NoError(t, err)
+		writeFiles(t, dir, map[string]string{
+			"azion/" + FingerprintFile: `{"inputs": "` + inputs + `", "outputs": "` + outputs + `"}`,
+		})
+
+		f, _, _ := testutils.NewFactory(&httpmock.Registry{})
+		cmd := NewBuildCmd(f)
+		cmd.GetWorkDir = func() (string, error) { return dir, nil }
+		cmd.GetAzionJsonContent = func(string) (*contracts.AzionApplicationOptions, error) {
+			return &contracts.AzionApplicationOptions{Preset: "javascript", Mode: "compute"}, nil
+		}
+		var written *contracts.AzionApplicationOptions
+		cmd.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+			written = conf
+			return nil
+		}
+		cmd.CommandRunInteractive = func(*cmdutil.Factory, string) error {
+			t.Fatal("the build should be skipped")
+			return nil
+		}
+		cmd.Hooks = func(f *cmdutil.Factory) *hooks.Hooks {
+			h := hooks.NewHooks(f)
+			h.ReadConfig = func(string) (*contracts.AzionApplicationConfig, error) {
+				return &contracts.AzionApplicationConfig{}, nil
+			}
+			return h
+		}
+
+		msgs := []string{}
+		require.NoError(t, RunBuildCmdLine(cmd, &contracts.BuildInfo{ProjectPath: "azion", VulcanVersion: "2.5.0"}, &msgs))
+		require.NotNil(t, written)
+		require.Equal(t, "2.5.0", written.VulcanVersion)
+	})
+
+	t.Run("a new release matching the pinned range rebuilds the project", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"main.js":  "addEventListener('fetch', () => {})",
+			PathWorker: "worker",
+		})
+
+		inputs, err := HashInputs(dir, "azion", "javascript", "compute", "", "2.5.0")
+		require.NoError(t, err)
+		outputs, err := HashOutputs(dir)
+		require.NoError(t, err)
+		writeFiles(t, dir, map[string]string{
+			"azion/" + FingerprintFile: `{"inputs": "` + inputs + `", "outputs": "` + outputs + `"}`,
+		})
+
+		f, _, _ := testutils.NewFactory(&httpmock.Registry{})
+		cmd := NewBuildCmd(f)
+		cmd.GetWorkDir = func() (string, error) { return dir, nil }
+		cmd.GetAzionJsonContent = func(string) (*contracts.AzionApplicationOptions, error) {
+			return &contracts.AzionApplicationOptions{Preset: "javascript", Mode: "compute", VulcanVersion: "^2.5.0"}, nil
+		}
+		cmd.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+			// the range stays pinned, not the release it resolved to
+			require.Equal(t, "^2.5.0", conf.VulcanVersion)
+			return nil
+		}
+		cmd.CommandRunner = func(_ *cmdutil.Factory, comm string, _ []string) (string, error) {
+			return `["2.5.0", "2.6.0"]`, nil
+		}
+		built := ""
+		cmd.CommandRunInteractive = func(_ *cmdutil.Factory, comm string) error {
+			built = comm
+			return nil
+		}
+		cmd.Hooks = func(f *cmdutil.Factory) *hooks.Hooks {
+			h := hooks.NewHooks(f)
+			h.ReadConfig = func(string) (*contracts.AzionApplicationConfig, error) {
+				return &contracts.AzionApplicationConfig{}, nil
+			}
+			return h
+		}
+
+		msgs := []string{}
+		require.NoError(t, RunBuildCmdLine(cmd, &contracts.BuildInfo{ProjectPath: "azion"}, &msgs))
+		require.Contains(t, built, "edge-functions@2.6.0 build")
+	})
+}
@@ -5,16 +5,10 @@ import (
 
 	msg "github.com/aziontech/azion-cli/messages/build"
 	"github.com/aziontech/azion-cli/pkg/logger"
-	"github.com/aziontech/azion-cli/pkg/output"
 	"go.uber.org/zap"
 )
 
 func runCommand(cmd *BuildCmd, command string, msgs *[]string) error {
-	var hasDeployMessage bool
-	if len(*msgs) > 0 {
-		hasDeployMessage = true
-	}
-
 	logger.FInfoFlags(cmd.Io.Out, msg.BuildStart, cmd.f.Format, cmd.f.Out)
 	*msgs = append(*msgs, msg.BuildStart)
 
@@ -32,17 +26,5 @@ func runCommand(cmd *BuildCmd, command string, msgs *[]string) error {
 	logger.FInfoFlags(cmd.Io.Out, msg.BuildSuccessful, cmd.f.Format, cmd.f.Out)
 	*msgs = append(*msgs, msg.BuildSuccessful)
 
-	if hasDeployMessage {
-		return nil
-	}
-
-	outSlice := output.SliceOutput{
-		Messages: *msgs,
-		GeneralOutput: output.GeneralOutput{
-			Out:   cmd.f.IOStreams.Out,
-			Flags: cmd.f.Flags,
-		},
-	}
-
-	return output.Print(&outSlice)
+	return nil
 }
@@ -12,22 +12,46 @@ import (
 	"go.uber.org/zap"
 )
 
-func vulcan(cmd *BuildCmd, conf *contracts.AzionApplicationOptions, vulcanParams string, fields *contracts.BuildInfo, msgs *[]string) error {
+// resolveVulcan returns the exact version the project pins, if any. The highest release a pinned range accepts is looked
+// up, so a new release matching it rebuilds the project
+func resolveVulcan(cmd *BuildCmd, conf *contracts.AzionApplicationOptions) (string, error) {
+	if conf.VulcanVersion == "" {
+		return "", nil
+	}
+	return vulcanPkg.Resolve(conf.VulcanVersion, func(comm string) (string, error) {
+		return cmd.CommandRunner(cmd.f, comm, []string{})
+	})
+}
+
+// checkVulcan checks the vulcan major and returns the package the build runs; a project that pins a version is built
+// with it, whatever version the settings keep
+func checkVulcan(cmd *BuildCmd, pinned string) (*vulcanPkg.VulcanPkg, error) {
+	if pinned != "" {
+		if err := vulcanPkg.Pin(pinned, cmd.f); err != nil {
+			return nil, err
+		}
+		return vulcanPkg.NewVulcan(), nil
+	}
+
 	// checking if vulcan major is correct
 	vulcanVer, err := cmd.CommandRunner(cmd.f, "npm show edge-functions version", []string{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	vul := vulcanPkg.NewVulcan()
 	err = vul.CheckVulcanMajor(vulcanVer, cmd.f, vul)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	return vul, nil
+}
+
+func vulcan(cmd *BuildCmd, vul *vulcanPkg.VulcanPkg, conf *contracts.AzionApplicationOptions, vulcanParams string, fields *contracts.BuildInfo, msgs *[]string) error {
 	command := vul.Command("", "build --preset %s --mode %s%s", cmd.f)
 
-	err = runCommand(cmd, fmt.Sprintf(command, strings.ToLower(conf.Preset), strings.ToLower(conf.Mode), vulcanParams), msgs)
+	err := runCommand(cmd, fmt.Sprintf(command, strings.ToLower(conf.Preset), strings.ToLower(conf.Mode), vulcanParams), msgs)
 	if err != nil {
 		return fmt.Errorf(msg.ErrorVulcanExecute.Error(), err.Error())
 	}
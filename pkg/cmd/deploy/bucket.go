@@ -53,6 +53,10 @@ func (cmd *DeployCmd) doBucket(
 		conf.Bucket = nameBucket
 	}
 
+	if err := cmd.journal.track(contracts.JournalEntry{Resource: resourceBucket, Name: conf.Bucket}); err != nil {
+		return err
+	}
+
 	msgf := fmt.Sprintf(msg.BucketSuccessful, conf.Bucket)
 	logger.FInfoFlags(cmd.Io.Out, msgf, cmd.F.Format, cmd.F.Out)
 	*msgs = append(*msgs, msgf)
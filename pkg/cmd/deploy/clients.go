@@ -1,11 +1,13 @@
 package deploy
 
 import (
+	apiCache "github.com/aziontech/azion-cli/pkg/api/cache_setting"
 	apiDomain "github.com/aziontech/azion-cli/pkg/api/domain"
 	apiEdgeApplications "github.com/aziontech/azion-cli/pkg/api/edge_applications"
 	apiEdgeFunction "github.com/aziontech/azion-cli/pkg/api/edge_function"
 	apiOrigin "github.com/aziontech/azion-cli/pkg/api/origin"
 	apiStorage "github.com/aziontech/azion-cli/pkg/api/storage"
+	apiVariables "github.com/aziontech/azion-cli/pkg/api/variables"
 	"github.com/aziontech/azion-cli/pkg/cmdutil"
 )
 
@@ -16,6 +18,8 @@ type Clients struct {
 	Origin          *apiOrigin.Client
 	Bucket          *apiStorage.Client
 	Storage         *apiStorage.Client
+	Cache           *apiCache.Client
+	Variables       *apiVariables.Client
 }
 
 func NewClients(f *cmdutil.Factory) *Clients {
@@ -31,5 +35,7 @@ func NewClients(f *cmdutil.Factory) *Clients {
 		Origin:          apiOrigin.NewClient(httpClient, apiURL, token),
 		Bucket:          apiStorage.NewClient(httpClient, storageURL, token),
 		Storage:         apiStorage.NewClient(httpClient, storageURL, token),
+		Cache:           apiCache.NewClient(httpClient, apiURL, token),
+		Variables:       apiVariables.NewClient(httpClient, apiURL, token),
 	}
 }
@@ -3,20 +3,25 @@ package deploy
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	msg "github.com/aziontech/azion-cli/messages/deploy"
+	msgmanifest "github.com/aziontech/azion-cli/messages/manifest"
 	apiEdgeApplications "github.com/aziontech/azion-cli/pkg/api/edge_applications"
 	"github.com/aziontech/azion-cli/pkg/cmd/build"
 	"github.com/aziontech/azion-cli/pkg/cmd/sync"
 	"github.com/aziontech/azion-cli/pkg/cmdutil"
 	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/github"
+	"github.com/aziontech/azion-cli/pkg/hooks"
 	"github.com/aziontech/azion-cli/pkg/iostreams"
 	"github.com/aziontech/azion-cli/pkg/logger"
 	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
@@ -28,20 +33,28 @@ import (
 )
 
 type DeployCmd struct {
-	Io                    *iostreams.IOStreams
-	GetWorkDir            func() (string, error)
-	FileReader            func(path string) ([]byte, error)
-	WriteFile             func(filename string, data []byte, perm fs.FileMode) error
-	GetAzionJsonContent   func(pathConfig string) (*contracts.AzionApplicationOptions, error)
-	WriteAzionJsonContent func(conf *contracts.AzionApplicationOptions, confConf string) error
-	EnvLoader             func(path string) ([]string, error)
-	BuildCmd              func(f *cmdutil.Factory) *build.BuildCmd
-	Open                  func(name string) (*os.File, error)
-	FilepathWalk          func(root string, fn filepath.WalkFunc) error
-	F                     *cmdutil.Factory
-	Unmarshal             func(data []byte, v interface{}) error
-	Interpreter           func() *manifestInt.ManifestInterpreter
-	VersionID             func() string
+	Io                     *iostreams.IOStreams
+	GetWorkDir             func() (string, error)
+	FileReader             func(path string) ([]byte, error)
+	WriteFile              func(filename string, data []byte, perm fs.FileMode) error
+	GetAzionJsonContent    func(pathConfig string) (*contracts.AzionApplicationOptions, error)
+	WriteAzionJsonContent  func(conf *contracts.AzionApplicationOptions, confConf string) error
+	EnvLoader              func(path string) ([]string, error)
+	BuildCmd               func(f *cmdutil.Factory) *build.BuildCmd
+	Open                   func(name string) (*os.File, error)
+	FilepathWalk           func(root string, fn filepath.WalkFunc) error
+	F                      *cmdutil.Factory
+	Unmarshal              func(data []byte, v interface{}) error
+	Interpreter            func() *manifestInt.ManifestInterpreter
+	VersionID              func() string
+	GetDeploymentHistory   func(confPath string) (*contracts.DeploymentHistory, error)
+	WriteDeploymentHistory func(history *contracts.DeploymentHistory, confPath string) error
+	HeadCommit             func(path string) (string, error)
+	Hooks                  func(f *cmdutil.Factory) *hooks.Hooks
+	journal                *Journal
+	manifestOverrides      string
+	report                 *contracts.DeployReport
+	staticFiles            []Data
 }
 
 var (
@@ -52,24 +65,37 @@ var (
 	ProjectConf string
 	Sync        bool
 	Env         string
+	DryRun      bool
+	Concurrency int
+
+	RollbackOnFailure bool
+	Environment       string
+	KeepVersions      int
+	// true as well for deploys not started from the command line, such as the one of link
+	Prune = true
 )
 
 func NewDeployCmd(f *cmdutil.Factory) *DeployCmd {
 	return &DeployCmd{
-		Io:                    f.IOStreams,
-		GetWorkDir:            utils.GetWorkingDir,
-		FileReader:            os.ReadFile,
-		WriteFile:             os.WriteFile,
-		EnvLoader:             utils.LoadEnvVarsFromFile,
-		BuildCmd:              build.NewBuildCmd,
-		GetAzionJsonContent:   utils.GetAzionJsonContent,
-		WriteAzionJsonContent: utils.WriteAzionJsonContent,
-		Open:                  os.Open,
-		FilepathWalk:          filepath.Walk,
-		Unmarshal:             json.Unmarshal,
-		F:                     f,
-		Interpreter:           manifestInt.NewManifestInterpreter,
-		VersionID:             utils.Timestamp,
+		Io:                     f.IOStreams,
+		GetWorkDir:             utils.GetWorkingDir,
+		FileReader:             os.ReadFile,
+		WriteFile:              os.WriteFile,
+		EnvLoader:              utils.LoadEnvVarsFromFile,
+		BuildCmd:               build.NewBuildCmd,
+		GetAzionJsonContent:    utils.GetAzionJsonContent,
+		WriteAzionJsonContent:  utils.WriteAzionJsonContent,
+		Open:                   os.Open,
+		FilepathWalk:           filepath.Walk,
+		Unmarshal:              json.Unmarshal,
+		F:                      f,
+		Interpreter:            manifestInt.NewManifestInterpreter,
+		VersionID:              utils.Timestamp,
+		GetDeploymentHistory:   utils.GetDeploymentHistory,
+		WriteDeploymentHistory: utils.WriteDeploymentHistory,
+		HeadCommit:             github.NewGithub().HeadCommit,
+		Hooks:                  hooks.NewHooks,
+		report:                 newReport(),
 	}
 }
 
@@ -84,8 +110,17 @@ func NewCobraCmd(deploy *DeployCmd) *cobra.Command {
        $ azion deploy --help
        $ azion deploy --path dist/storage
        $ azion deploy --auto
+       $ azion deploy --dry-run --format json
+       $ azion deploy --auto --format json --out deploy.json
+       $ azion deploy --concurrency 10
+       $ azion deploy --environment staging
+       $ azion deploy --keep-versions 5
+       $ azion deploy --prune=false
        `),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if Environment != "" && !cmd.Flags().Changed("env") {
+				Env = environmentEnvFile(Env, Environment)
+			}
 			return deploy.Run(deploy.F)
 		},
 	}
@@ -97,6 +132,12 @@ func NewCobraCmd(deploy *DeployCmd) *cobra.Command {
 	deployCmd.Flags().StringVar(&ProjectConf, "config-dir", "azion", msg.EdgeApplicationDeployProjectConfFlag)
 	deployCmd.Flags().BoolVar(&Sync, "sync", false, msg.EdgeApplicationDeploySync)
 	deployCmd.Flags().StringVar(&Env, "env", ".edge/.env", msg.EnvFlag)
+	deployCmd.Flags().BoolVar(&DryRun, "dry-run", false, msg.DeployFlagDryRun)
+	deployCmd.Flags().IntVar(&Concurrency, "concurrency", 5, msg.DeployFlagConcurrency)
+	deployCmd.Flags().BoolVar(&RollbackOnFailure, "rollback-on-failure", false, msg.DeployFlagRollbackOnFailure)
+	deployCmd.Flags().StringVar(&Environment, "environment", "", msg.DeployFlagEnvironment)
+	deployCmd.Flags().IntVar(&KeepVersions, "keep-versions", 0, msg.DeployFlagKeepVersions)
+	deployCmd.Flags().BoolVar(&Prune, "prune", true, msg.DeployFlagPrune)
 	return deployCmd
 }
 
@@ -111,68 +152,293 @@ func (cmd *DeployCmd) ExternalRun(f *cmdutil.Factory, configPath string) error {
 
 func (cmd *DeployCmd) Run(f *cmdutil.Factory) error {
 	msgs := []string{}
+	cmd.report = newReport()
 	logger.FInfoFlags(cmd.F.IOStreams.Out, "Running deploy command\n", cmd.F.Format, cmd.F.Out)
 	msgs = append(msgs, "Running deploy command")
+
+	conf, err := cmd.deploy(f, &msgs)
+	if DryRun {
+		return err
+	}
+
+	// a failed deploy prints its report as well, so automation can tell how far it got
+	cmd.report.Status = reportSucceeded
+	if err != nil {
+		cmd.report.Status = reportFailed
+		cmd.report.Error = err.Error()
+	}
+	if conf != nil {
+		cmd.fillReport(conf)
+	}
+	deployOut := output.DeployOutput{
+		Messages: msgs,
+		Report:   *cmd.report,
+		GeneralOutput: output.GeneralOutput{
+			Out:   cmd.F.IOStreams.Out,
+			Flags: cmd.F.Flags,
+		},
+	}
+
+	if errOut := output.Print(&deployOut); errOut != nil {
+		if err != nil {
+			logger.Debug("Error while printing the report of the failed deploy", zap.Error(errOut))
+			return err
+		}
+		return errOut
+	}
+	return err
+}
+
+// deploy runs every step of the deploy. The configuration is returned once read, even when a later step fails.
+func (cmd *DeployCmd) deploy(f *cmdutil.Factory, msgs *[]string) (*contracts.AzionApplicationOptions, error) {
 	ctx := context.Background()
 
-	err := checkToken(f)
+	if KeepVersions < 0 {
+		return nil, msg.ErrorKeepVersions
+	}
+
+	// the build and the hooks belong to the project, every remote resource to the environment
+	var err error
+	baseConf := ProjectConf
+	var newEnvironment *contracts.AzionApplicationOptions
+	if Environment != "" {
+		ProjectConf, newEnvironment, err = cmd.environmentConf(baseConf)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { ProjectConf = baseConf }()
+
+		if ProjectConf != baseConf {
+			workDir, err := cmd.GetWorkDir()
+			if err != nil {
+				return nil, err
+			}
+			cmd.manifestOverrides = manifestInt.FindManifest(filepath.Join(workDir, ProjectConf))
+		}
+	}
+
+	err = cmd.validateManifest()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	err = checkToken(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if DryRun {
+		conf := newEnvironment
+		if conf == nil {
+			conf, err = cmd.GetAzionJsonContent(ProjectConf)
+			if err != nil {
+				logger.Debug("Failed to get Azion JSON content", zap.Error(err))
+				return nil, err
+			}
+		}
+		conf.Prefix = cmd.VersionID()
+		return nil, cmd.dryRun(f, conf, msgs)
+	}
+
+	if newEnvironment != nil {
+		err = cmd.setupEnvironment(baseConf, ProjectConf, newEnvironment, msgs)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if Sync {
+		start := time.Now()
 		sync.ProjectConf = ProjectConf
 		syncCmd := sync.NewSync(f)
 		syncCmd.EnvPath = Env
 		if err := sync.Sync(syncCmd); err != nil {
 			logger.Debug("Error while synchronizing local resources with remove resources", zap.Error(err))
-			return err
+			return nil, err
 		}
+		cmd.timePhase(phaseSync, start)
 	}
 
 	if !SkipBuild {
+		start := time.Now()
 		buildCmd := cmd.BuildCmd(f)
-		err = buildCmd.ExternalRun(&contracts.BuildInfo{}, ProjectConf, &msgs)
+		err = buildCmd.ExternalRun(&contracts.BuildInfo{}, baseConf, msgs)
 		if err != nil {
 			logger.Debug("Error while running build command called by deploy command", zap.Error(err))
-			return err
+			return nil, err
 		}
+		cmd.timePhase(phaseBuild, start)
 	}
 
 	conf, err := cmd.GetAzionJsonContent(ProjectConf)
 	if err != nil {
 		logger.Debug("Failed to get Azion JSON content", zap.Error(err))
-		return err
+		return nil, err
 	}
 
 	versionID := cmd.VersionID()
 
 	conf.Prefix = versionID
 
+	workDir, err := cmd.GetWorkDir()
+	if err != nil {
+		return conf, err
+	}
+
+	publishHooks := cmd.Hooks(f)
+	err = publishHooks.Run(workDir, baseConf, hooks.PrePublish, msgs)
+	if err != nil {
+		return conf, err
+	}
+
 	err = checkArgsJson(cmd, ProjectConf)
 	if err != nil {
-		return err
+		return conf, err
 	}
 
 	clients := NewClients(f)
+
+	journal, err := readJournal(ProjectConf)
+	if err != nil {
+		return conf, err
+	}
+	if len(journal.Entries) > 0 {
+		msgf := fmt.Sprintf(msg.JournalResuming, journal.VersionID, len(journal.Entries))
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, f.Format, f.Out)
+		*msgs = append(*msgs, msgf)
+		journal.restore(conf)
+	} else {
+		journal.VersionID = versionID
+	}
+	cmd.journal = journal
+
+	err = cmd.deployResources(f, clients, conf, msgs)
+	if err != nil {
+		return conf, cmd.handleFailure(ctx, clients, conf, journal, err)
+	}
+
+	err = journal.complete()
+	if err != nil {
+		logger.Debug("Error while removing the deploy journal", zap.Error(err))
+		return conf, err
+	}
+
+	err = cmd.recordDeployment(conf, versionID, msgs)
+	if err != nil {
+		return conf, err
+	}
+
+	// the next deploy uploads only the static files whose hashes differ from these
+	if cmd.staticFiles != nil {
+		if err := writeFilesJSONL(cmd.staticFiles, ProjectConf); err != nil {
+			logger.Debug("Error while writing files.json file", zap.Error(err))
+			return conf, err
+		}
+	}
+
+	start := time.Now()
+	cmd.pruneVersions(ctx, clients, conf, msgs)
+	cmd.timePhase(phasePrune, start)
+
+	err = publishHooks.Run(workDir, baseConf, hooks.PostPublish, msgs)
+	if err != nil {
+		return conf, err
+	}
+
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msg.DeploySuccessful, f.Format, f.Out)
+	*msgs = append(*msgs, msg.DeploySuccessful)
+
+	msgfOutputDomainSuccess := fmt.Sprintf(msg.DeployOutputDomainSuccess, conf.Domain.Url)
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msgfOutputDomainSuccess, f.Format, f.Out)
+	*msgs = append(*msgs, msgfOutputDomainSuccess)
+
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msg.DeployPropagation, f.Format, f.Out)
+	*msgs = append(*msgs, msg.DeployPropagation)
+
+	return conf, nil
+}
+
+// validateManifest fails the deploy on a broken manifest before anything is synchronized, built or created.
+// A manifest the build has yet to generate is validated once it exists.
+func (cmd *DeployCmd) validateManifest() error {
+	interpreter := cmd.Interpreter()
+	interpreter.OverridesPath = cmd.manifestOverrides
+	interpreter.EnvPath = Env
+
+	pathManifest, err := interpreter.ManifestPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(pathManifest); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return interpreter.ValidateManifest(pathManifest)
+}
+
+// confirmDelete asks before deploy deletes the resources no longer declared in manifest.json. Unless --yes is sent,
+// deploys that cannot ask keep them.
+func (cmd *DeployCmd) confirmDelete(deletions []contracts.ResourcePlan) bool {
+	if cmd.F.GlobalFlagAll {
+		return true
+	}
+	if !cmd.canAsk() {
+		return false
+	}
+
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msgmanifest.DeleteCandidates, cmd.F.Format, cmd.F.Out)
+	for _, resource := range deletions {
+		msgf := fmt.Sprintf(msgmanifest.DeletingResource, resource.Resource, resource.Name, resource.Id)
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+	}
+	return utils.Confirm(cmd.F.GlobalFlagAll, fmt.Sprintf(msg.AskDeleteResources, len(deletions)), false)
+}
+
+// plannedDelete tells the dry-run whether the deploy would delete a resource: with --yes, or by asking first
+func (cmd *DeployCmd) plannedDelete() bool {
+	return cmd.F.GlobalFlagAll || cmd.canAsk()
+}
+
+func (cmd *DeployCmd) canAsk() bool {
+	return !Auto && !NoPrompt && !cmd.F.NonInteractive
+}
+
+// deployResources creates or updates every resource of the project. Each resource it creates is tracked
+// in the journal, so a failure midway can be rolled back or resumed.
+func (cmd *DeployCmd) deployResources(f *cmdutil.Factory, clients *Clients, conf *contracts.AzionApplicationOptions, msgs *[]string) error {
+	ctx := context.Background()
 	interpreter := cmd.Interpreter()
+	interpreter.Created = cmd.journal.track
+	interpreter.Changed = cmd.recordChange
+	interpreter.OverridesPath = cmd.manifestOverrides
+	interpreter.Prune = Prune
+	interpreter.ConfirmDelete = cmd.confirmDelete
+	interpreter.EnvPath = Env
 
 	pathManifest, err := interpreter.ManifestPath()
 	if err != nil {
 		return err
 	}
 
-	err = cmd.doApplication(clients.EdgeApplication, context.Background(), conf, &msgs)
+	// the build may have generated or changed the manifest, so it is validated again before anything is created from it
+	err = interpreter.ValidateManifest(pathManifest)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = cmd.doApplication(clients.EdgeApplication, ctx, conf, msgs)
 	if err != nil {
 		return err
 	}
 
-	singleOriginId, err := cmd.doOriginSingle(clients.Origin, ctx, conf, &msgs)
+	singleOriginId, err := cmd.doOriginSingle(clients.Origin, ctx, conf, msgs)
 	if err != nil {
 		return err
 	}
+	cmd.timePhase(phaseApplication, start)
 
-	err = cmd.doBucket(clients.Bucket, ctx, conf, &msgs)
+	start = time.Now()
+	err = cmd.doBucket(clients.Bucket, ctx, conf, msgs)
 	if err != nil {
 		return err
 	}
@@ -181,17 +447,22 @@ func (cmd *DeployCmd) Run(f *cmdutil.Factory) error {
 	if _, err := os.Stat(PathStatic); os.IsNotExist(err) {
 		logger.Debug(msg.SkipUpload)
 	} else {
-		err = cmd.uploadFiles(f, conf, &msgs)
+		err = cmd.uploadFiles(f, conf, msgs)
 		if err != nil {
 			return err
 		}
 	}
+	cmd.timePhase(phaseStorage, start)
 
+	start = time.Now()
 	conf.Function.File = ".edge/worker.js"
-	err = cmd.doFunction(clients, ctx, conf, &msgs)
+	err = cmd.doFunction(clients, ctx, conf, msgs)
 	if err != nil {
 		return err
 	}
+	cmd.timePhase(phaseFunction, start)
+
+	start = time.Now()
 
 	if !conf.NotFirstRun {
 		ruleDefaultID, err := clients.EdgeApplication.GetRulesDefault(ctx, conf.Application.ID, "request")
@@ -236,45 +507,30 @@ func (cmd *DeployCmd) Run(f *cmdutil.Factory) error {
 		}
 	}
 
-	manifestStructure, err := interpreter.ReadManifest(pathManifest, f, &msgs)
+	manifestStructure, err := interpreter.ReadManifest(pathManifest, f, msgs)
 	if err != nil {
 		return err
 	}
 
 	if len(conf.RulesEngine.Rules) == 0 {
-		err = cmd.doRulesDeploy(ctx, conf, clients.EdgeApplication, &msgs)
+		err = cmd.doRulesDeploy(ctx, conf, clients.EdgeApplication, msgs)
 		if err != nil {
 			return err
 		}
 	}
 
-	err = interpreter.CreateResources(conf, manifestStructure, f, ProjectConf, &msgs)
+	err = interpreter.CreateResources(conf, manifestStructure, f, ProjectConf, msgs)
 	if err != nil {
 		return err
 	}
+	cmd.timePhase(phaseManifest, start)
 
-	err = cmd.doDomain(clients.Domain, ctx, conf, &msgs)
+	start = time.Now()
+	err = cmd.doDomain(clients.Domain, ctx, conf, manifestStructure, msgs)
 	if err != nil {
 		return err
 	}
+	cmd.timePhase(phaseDomain, start)
 
-	logger.FInfoFlags(cmd.F.IOStreams.Out, msg.DeploySuccessful, f.Format, f.Out)
-	msgs = append(msgs, msg.DeploySuccessful)
-
-	msgfOutputDomainSuccess := fmt.Sprintf(msg.DeployOutputDomainSuccess, conf.Domain.Url)
-	logger.FInfoFlags(cmd.F.IOStreams.Out, msgfOutputDomainSuccess, f.Format, f.Out)
-	msgs = append(msgs, msgfOutputDomainSuccess)
-
-	logger.FInfoFlags(cmd.F.IOStreams.Out, msg.DeployPropagation, f.Format, f.Out)
-	msgs = append(msgs, msg.DeployPropagation)
-
-	outSlice := output.SliceOutput{
-		Messages: msgs,
-		GeneralOutput: output.GeneralOutput{
-			Out:   cmd.F.IOStreams.Out,
-			Flags: cmd.F.Flags,
-		},
-	}
-
-	return output.Print(&outSlice)
+	return nil
 }
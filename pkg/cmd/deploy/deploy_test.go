@@ -5,14 +5,17 @@ import (
 	"encoding/json"
 	"net/http"
 	"os"
+	"path/filepath"
 	"testing"
 
+	msg "github.com/aziontech/azion-cli/messages/deploy"
 	"github.com/aziontech/azion-cli/pkg/logger"
 	"go.uber.org/zap/zapcore"
 
 	apiapp "github.com/aziontech/azion-cli/pkg/api/edge_applications"
 	"github.com/aziontech/azion-cli/pkg/contracts"
 	"github.com/aziontech/azion-cli/pkg/httpmock"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
 	"github.com/aziontech/azion-cli/pkg/testutils"
 	"github.com/stretchr/testify/require"
 )
@@ -68,6 +71,26 @@ func TestDeployCmd(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("broken manifest fails before any request", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, ".edge"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".edge", "manifest.json"), []byte(`{"origins": []}`), 0644))
+
+		mock := &httpmock.Registry{}
+		f, _, _ := testutils.NewFactory(mock)
+		deployCmd := NewDeployCmd(f)
+		deployCmd.Interpreter = func() *manifestInt.ManifestInterpreter {
+			interpreter := manifestInt.NewManifestInterpreter()
+			interpreter.GetWorkDir = func() (string, error) { return dir, nil }
+			return interpreter
+		}
+
+		err := deployCmd.Run(f)
+		var invalid manifestInt.ValidationErrors
+		require.ErrorAs(t, err, &invalid)
+		require.Empty(t, mock.Requests)
+	})
+
 	t.Run("failed to create application", func(t *testing.T) {
 
 		mock := &httpmock.Registry{}
@@ -129,4 +152,30 @@ func TestDeployCmd(t *testing.T) {
 		_, err := cmd.createApplication(cliapp, ctx, options, &msgs)
 		require.NoError(t, err)
 	})
+	t.Run("non-interactive mode renames instead of asking", func(t *testing.T) {
+		f, _, _ := testutils.NewFactory(nil)
+		f.NonInteractive = true
+		cmd := NewDeployCmd(f)
+
+		msgs := []string{}
+		name, err := cmd.renameInUse("my-app", msg.NameInUseApplication, &msgs)
+		require.NoError(t, err)
+		require.Regexp(t, `^my-app-\d{14}$`, name)
+		require.Len(t, msgs, 1)
+	})
+	t.Run("deletions are kept unless confirmed", func(t *testing.T) {
+		deletions := []contracts.ResourcePlan{{Resource: "origin", Name: "api", Id: "e4f0761b", Action: "delete"}}
+
+		f, stdout, _ := testutils.NewFactory(nil)
+		f.NonInteractive = true
+		cmd := NewDeployCmd(f)
+		require.False(t, cmd.confirmDelete(deletions))
+		require.False(t, cmd.plannedDelete())
+		// nothing is listed when the deploy can't ask
+		require.Empty(t, stdout.String())
+
+		f.GlobalFlagAll = true
+		require.True(t, cmd.confirmDelete(deletions))
+		require.True(t, cmd.plannedDelete())
+	})
 }
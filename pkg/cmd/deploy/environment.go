@@ -0,0 +1,111 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	msg "github.com/aziontech/azion-cli/messages/deploy"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/utils"
+	"go.uber.org/zap"
+)
+
+// environmentConf returns the configuration directory of the environment being deployed. An environment deployed
+// for the first time gets the configuration of the project without any remote ID, returned as newConf; it is only
+// written to disk by setupEnvironment, so a dry-run leaves the project untouched.
+func (cmd *DeployCmd) environmentConf(baseConf string) (string, *contracts.AzionApplicationOptions, error) {
+	confPath, err := utils.EnvironmentConfPath(baseConf, Environment)
+	if err != nil {
+		return "", nil, err
+	}
+	if confPath == baseConf {
+		return confPath, nil, nil
+	}
+
+	workDir, err := cmd.GetWorkDir()
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := os.Stat(path.Join(workDir, confPath, "azion.json")); !errors.Is(err, os.ErrNotExist) {
+		return confPath, nil, err
+	}
+
+	base, err := cmd.GetAzionJsonContent(baseConf)
+	if err != nil {
+		logger.Debug("Failed to get Azion JSON content", zap.Error(err))
+		return "", nil, err
+	}
+
+	// only what describes the project is kept; the remote resources are created for the environment
+	newConf := &contracts.AzionApplicationOptions{
+		Name:    utils.Concat(base.Name, "-", Environment),
+		Preset:  base.Preset,
+		Mode:    base.Mode,
+		Env:     Environment,
+		RtPurge: base.RtPurge,
+		Function: contracts.AzionJsonDataFunction{
+			Name:         base.Function.Name,
+			File:         base.Function.File,
+			Args:         path.Join(confPath, "args.json"),
+			InstanceName: base.Function.InstanceName,
+		},
+		Application: contracts.AzionJsonDataApplication{Name: base.Application.Name},
+		Domain:      contracts.AzionJsonDataDomain{Name: base.Domain.Name},
+	}
+	if newConf.Function.Name != "" && newConf.Function.Name != "__DEFAULT__" {
+		newConf.Function.Name = utils.Concat(newConf.Function.Name, "-", Environment)
+	}
+	if newConf.Application.Name != "" && newConf.Application.Name != "__DEFAULT__" {
+		newConf.Application.Name = utils.Concat(newConf.Application.Name, "-", Environment)
+	}
+	if newConf.Domain.Name != "" && newConf.Domain.Name != "__DEFAULT__" {
+		newConf.Domain.Name = utils.Concat(newConf.Domain.Name, "-", Environment)
+	}
+
+	return confPath, newConf, nil
+}
+
+// setupEnvironment writes the configuration directory of an environment deployed for the first time,
+// starting from the function args of the project
+func (cmd *DeployCmd) setupEnvironment(baseConf, confPath string, conf *contracts.AzionApplicationOptions, msgs *[]string) error {
+	workDir, err := cmd.GetWorkDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path.Join(workDir, confPath), os.ModePerm); err != nil {
+		logger.Debug("Error while creating the environment directory", zap.Error(err))
+		return fmt.Errorf(msg.ErrorCreateEnvironment.Error(), Environment, err)
+	}
+
+	args, err := cmd.FileReader(path.Join(workDir, baseConf, "args.json"))
+	if err != nil {
+		args = []byte("{}")
+	}
+	if err := cmd.WriteFile(path.Join(workDir, confPath, "args.json"), args, 0644); err != nil {
+		logger.Debug("Error while writing args.json of the environment", zap.Error(err))
+		return fmt.Errorf(msg.ErrorCreateEnvironment.Error(), Environment, err)
+	}
+
+	if err := cmd.WriteAzionJsonContent(conf, confPath); err != nil {
+		logger.Debug("Error while writing azion.json file", zap.Error(err))
+		return fmt.Errorf(msg.ErrorCreateEnvironment.Error(), Environment, err)
+	}
+
+	msgf := fmt.Sprintf(msg.EnvironmentCreated, Environment, confPath)
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+	*msgs = append(*msgs, msgf)
+	return nil
+}
+
+// environmentEnvFile is the .env file of the environment, next to the one of the project, when it exists
+func environmentEnvFile(envFile, environment string) string {
+	envFileEnvironment := utils.Concat(envFile, ".", environment)
+	if _, err := os.Stat(envFileEnvironment); err != nil {
+		return envFile
+	}
+	return envFileEnvironment
+}
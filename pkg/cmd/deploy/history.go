@@ -0,0 +1,98 @@
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	msg "github.com/aziontech/azion-cli/messages/deploy"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	SnapshotCode = "worker.js"
+	SnapshotArgs = "args.json"
+)
+
+// SnapshotDir is where the function code and args of a deploy are kept, so a rollback can restore them
+func SnapshotDir(confPath, versionID string) string {
+	return filepath.Join(confPath, "deployments", versionID)
+}
+
+// HashCode returns the sha256 of the function code, before the storage configuration is injected
+func HashCode(code []byte) string {
+	sum := sha256.Sum256(code)
+	return hex.EncodeToString(sum[:])
+}
+
+// InjectStorage prepends the storage configuration read by the edge runtime to the function code
+func InjectStorage(code []byte, bucket, prefix string) []byte {
+	prependText := fmt.Sprintf(injectIntoFunction, bucket, prefix)
+	return append([]byte(prependText), code...)
+}
+
+// recordDeployment snapshots the function code and args of a successful deploy and appends it to deployments.json
+func (cmd *DeployCmd) recordDeployment(conf *contracts.AzionApplicationOptions, versionID string, msgs *[]string) error {
+	code, err := cmd.FileReader(conf.Function.File)
+	if err != nil {
+		logger.Debug("Error while reading Edge Function file <"+conf.Function.File+">", zap.Error(err))
+		return fmt.Errorf("%s: %w", msg.ErrorCodeFlag, err)
+	}
+
+	args, err := cmd.FileReader(conf.Function.Args)
+	if err != nil {
+		logger.Debug("Error while reading args.json file <"+conf.Function.Args+">", zap.Error(err))
+		return fmt.Errorf("%s: %w", msg.ErrorArgsFlag, err)
+	}
+
+	dir := SnapshotDir(ProjectConf, versionID)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		logger.Debug("Error while creating deployment snapshot directory", zap.Error(err))
+		return fmt.Errorf(msg.ErrorSnapshot.Error(), err)
+	}
+
+	if err := cmd.WriteFile(filepath.Join(dir, SnapshotCode), code, 0644); err != nil {
+		logger.Debug("Error while writing deployment snapshot", zap.Error(err))
+		return fmt.Errorf(msg.ErrorSnapshot.Error(), err)
+	}
+
+	if err := cmd.WriteFile(filepath.Join(dir, SnapshotArgs), args, 0644); err != nil {
+		logger.Debug("Error while writing deployment snapshot", zap.Error(err))
+		return fmt.Errorf(msg.ErrorSnapshot.Error(), err)
+	}
+
+	// projects that are not git repositories are recorded without a commit
+	commit, err := cmd.HeadCommit(".")
+	if err != nil {
+		logger.Debug("Could not read the git commit of the project", zap.Error(err))
+	}
+
+	history, err := cmd.GetDeploymentHistory(ProjectConf)
+	if err != nil {
+		return err
+	}
+
+	history.Deployments = append(history.Deployments, contracts.DeploymentRecord{
+		VersionID:    versionID,
+		FunctionHash: HashCode(code),
+		Timestamp:    time.Now().Format(time.RFC3339),
+		GitCommit:    commit,
+		Bucket:       conf.Bucket,
+		Prefix:       conf.Prefix,
+	})
+
+	if err := cmd.WriteDeploymentHistory(history, ProjectConf); err != nil {
+		logger.Debug("Error while writing deployments.json file", zap.Error(err))
+		return err
+	}
+
+	msgf := fmt.Sprintf(msg.DeployOutputVersionRecorded, versionID)
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+	*msgs = append(*msgs, msgf)
+	return nil
+}
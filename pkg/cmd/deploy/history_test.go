@@ -0,0 +1,66 @@
+package deploy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func Test_recordDeployment(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	t.Run("record deploy and snapshot", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+		f, _, _ := testutils.NewFactory(mock)
+
+		ProjectConf = t.TempDir()
+		defer func() { ProjectConf = "azion" }()
+		code := []byte("async function handleRequest(request) {}")
+
+		var written *contracts.DeploymentHistory
+		cmd := NewDeployCmd(f)
+		cmd.FileReader = func(path string) ([]byte, error) {
+			if path == "args.json" {
+				return []byte("{}"), nil
+			}
+			return code, nil
+		}
+		cmd.HeadCommit = func(path string) (string, error) {
+			return "", errors.New("repository does not exist")
+		}
+		cmd.GetDeploymentHistory = func(confPath string) (*contracts.DeploymentHistory, error) {
+			return &contracts.DeploymentHistory{}, nil
+		}
+		cmd.WriteDeploymentHistory = func(history *contracts.DeploymentHistory, confPath string) error {
+			written = history
+			return nil
+		}
+
+		conf := &contracts.AzionApplicationOptions{
+			Bucket:   "bucket",
+			Prefix:   "20240101000000",
+			Function: contracts.AzionJsonDataFunction{File: "worker.js", Args: "args.json"},
+		}
+
+		msgs := []string{}
+		err := cmd.recordDeployment(conf, "20240202000000", &msgs)
+		require.NoError(t, err)
+		require.Len(t, written.Deployments, 1)
+		require.Equal(t, "20240202000000", written.Deployments[0].VersionID)
+		require.Equal(t, "20240101000000", written.Deployments[0].Prefix)
+		require.Equal(t, HashCode(code), written.Deployments[0].FunctionHash)
+		require.Empty(t, written.Deployments[0].GitCommit)
+
+		snapshot, err := os.ReadFile(filepath.Join(SnapshotDir(ProjectConf, "20240202000000"), SnapshotCode))
+		require.NoError(t, err)
+		require.Equal(t, code, snapshot)
+	})
+}
@@ -0,0 +1,457 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	msg "github.com/aziontech/azion-cli/messages/deploy"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
+	"github.com/aziontech/azion-cli/utils"
+	"go.uber.org/zap"
+)
+
+const journalFile = "journal.json"
+
+// Journal tracks every resource created by a deploy until it completes. A failed deploy either removes them
+// or leaves the journal behind, so the next deploy reuses them instead of creating duplicates.
+type Journal struct {
+	VersionID string                   `json:"version-id"`
+	Entries   []contracts.JournalEntry `json:"entries"`
+	confPath  string
+}
+
+func readJournal(confPath string) (*Journal, error) {
+	journal := &Journal{confPath: confPath}
+	content, err := os.ReadFile(filepath.Join(confPath, journalFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return journal, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, journal); err != nil {
+		logger.Debug("Error while reading the deploy journal", zap.Error(err))
+		return nil, fmt.Errorf(msg.ErrorReadJournal.Error(), err)
+	}
+	return journal, nil
+}
+
+func (j *Journal) write() error {
+	content, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(j.confPath, journalFile), content, 0644)
+}
+
+// track records a created resource right away, so it is known even if the deploy is killed. The entry is kept in
+// memory when the journal can't be written, so the failure it causes can still roll the resource back.
+func (j *Journal) track(entry contracts.JournalEntry) error {
+	if j == nil {
+		return nil
+	}
+	j.Entries = append(j.Entries, entry)
+	if err := j.write(); err != nil {
+		logger.Debug("Error while writing the deploy journal", zap.Error(err))
+		return fmt.Errorf(msg.ErrorWriteJournal.Error(), err)
+	}
+	return nil
+}
+
+// complete discards the journal once every resource of the deploy is recorded in azion.json
+func (j *Journal) complete() error {
+	j.Entries = nil
+	err := os.Remove(filepath.Join(j.confPath, journalFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// restore brings back into azion.json the resources of an interrupted deploy that it may have missed
+func (j *Journal) restore(conf *contracts.AzionApplicationOptions) {
+	for _, entry := range j.Entries {
+		switch entry.Resource {
+		case resourceApplication:
+			if conf.Application.ID == 0 {
+				conf.Application.ID = entry.ID
+			}
+		case resourceBucket:
+			if conf.Bucket == "" {
+				conf.Bucket = entry.Name
+			}
+		case resourceFunction:
+			if conf.Function.ID == 0 {
+				conf.Function.ID = entry.ID
+			}
+		case resourceInstance:
+			if conf.Function.InstanceID == 0 {
+				conf.Function.InstanceID = entry.ID
+			}
+		case resourceDefaultRule:
+			// the rule exists, so the deploy must not create it again
+			conf.NotFirstRun = true
+		case resourceDomain:
+			if conf.Domain.Id == 0 {
+				conf.Domain.Id = entry.ID
+				conf.Domain.Name = entry.Name
+			}
+		case manifestInt.ResourceOrigin:
+			if !hasOrigin(conf, entry.Key) {
+				conf.Origin = append(conf.Origin, contracts.AzionJsonDataOrigin{
+					OriginId:  entry.ID,
+					OriginKey: entry.Key,
+					Name:      entry.Name,
+				})
+			}
+		case manifestInt.ResourceCache:
+			if !hasCache(conf, entry.ID) {
+				conf.CacheSettings = append(conf.CacheSettings, contracts.AzionJsonDataCacheSettings{
+					Id:   entry.ID,
+					Name: entry.Name,
+				})
+			}
+		case manifestInt.ResourceRule:
+			if !hasRule(conf, entry.ID) {
+				conf.RulesEngine.Rules = append(conf.RulesEngine.Rules, contracts.AzionJsonDataRules{
+					Id:    entry.ID,
+					Name:  entry.Name,
+					Phase: entry.Phase,
+				})
+			}
+		case manifestInt.ResourceDeviceGroup:
+			if !hasDeviceGroup(conf, entry.ID) {
+				conf.DeviceGroups = append(conf.DeviceGroups, contracts.AzionJsonDataDeviceGroup{
+					Id:   entry.ID,
+					Name: entry.Name,
+				})
+			}
+		case manifestInt.ResourceFunction:
+			if !hasManifestFunction(conf, entry.ID) {
+				conf.Functions = append(conf.Functions, contracts.AzionJsonDataManifestFunction{
+					Id:   entry.ID,
+					Name: entry.Name,
+				})
+			}
+		case manifestInt.ResourceFunctionInstance:
+			if !hasFunctionInstance(conf, entry.ID) {
+				conf.FunctionInstances = append(conf.FunctionInstances, contracts.AzionJsonDataFunctionInstance{
+					Id:   entry.ID,
+					Name: entry.Name,
+				})
+			}
+		case manifestInt.ResourceDomain:
+			if !hasManifestDomain(conf, entry.ID) {
+				conf.Domains = append(conf.Domains, contracts.AzionJsonDataManifestDomain{
+					Id:   entry.ID,
+					Name: entry.Name,
+				})
+			}
+		case manifestInt.ResourceVariable:
+			if !hasVariable(conf, entry.Key) {
+				conf.Variables = append(conf.Variables, contracts.AzionJsonDataVariable{
+					Uuid: entry.Key,
+					Key:  entry.Name,
+				})
+			}
+		}
+	}
+}
+
+// forget removes a deleted resource from azion.json, so the next deploy creates it again
+func forget(conf *contracts.AzionApplicationOptions, entry contracts.JournalEntry) {
+	switch entry.Resource {
+	case resourceApplication:
+		if conf.Application.ID == entry.ID {
+			conf.Application.ID = 0
+			conf.NotFirstRun = false
+		}
+	case resourceBucket:
+		if conf.Bucket == entry.Name {
+			conf.Bucket = ""
+		}
+	case resourceFunction:
+		if conf.Function.ID == entry.ID {
+			conf.Function.ID = 0
+		}
+	case resourceInstance:
+		if conf.Function.InstanceID == entry.ID {
+			conf.Function.InstanceID = 0
+		}
+	case resourceDomain:
+		if conf.Domain.Id == entry.ID {
+			conf.Domain = contracts.AzionJsonDataDomain{}
+		}
+	case manifestInt.ResourceOrigin:
+		origins := []contracts.AzionJsonDataOrigin{}
+		for _, origin := range conf.Origin {
+			if origin.OriginKey != entry.Key {
+				origins = append(origins, origin)
+			}
+		}
+		conf.Origin = origins
+	case manifestInt.ResourceCache:
+		caches := []contracts.AzionJsonDataCacheSettings{}
+		for _, cache := range conf.CacheSettings {
+			if cache.Id != entry.ID {
+				caches = append(caches, cache)
+			}
+		}
+		conf.CacheSettings = caches
+	case manifestInt.ResourceRule:
+		rules := []contracts.AzionJsonDataRules{}
+		for _, rule := range conf.RulesEngine.Rules {
+			if rule.Id != entry.ID {
+				rules = append(rules, rule)
+			}
+		}
+		conf.RulesEngine.Rules = rules
+	case manifestInt.ResourceDeviceGroup:
+		groups := []contracts.AzionJsonDataDeviceGroup{}
+		for _, group := range conf.DeviceGroups {
+			if group.Id != entry.ID {
+				groups = append(groups, group)
+			}
+		}
+		conf.DeviceGroups = groups
+	case manifestInt.ResourceFunction:
+		functions := []contracts.AzionJsonDataManifestFunction{}
+		for _, function := range conf.Functions {
+			if function.Id != entry.ID {
+				functions = append(functions, function)
+			}
+		}
+		conf.Functions = functions
+	case manifestInt.ResourceFunctionInstance:
+		instances := []contracts.AzionJsonDataFunctionInstance{}
+		for _, instance := range conf.FunctionInstances {
+			if instance.Id != entry.ID {
+				instances = append(instances, instance)
+			}
+		}
+		conf.FunctionInstances = instances
+	case manifestInt.ResourceDomain:
+		domains := []contracts.AzionJsonDataManifestDomain{}
+		for _, domain := range conf.Domains {
+			if domain.Id != entry.ID {
+				domains = append(domains, domain)
+			}
+		}
+		conf.Domains = domains
+	case manifestInt.ResourceVariable:
+		variables := []contracts.AzionJsonDataVariable{}
+		for _, variable := range conf.Variables {
+			if variable.Uuid != entry.Key {
+				variables = append(variables, variable)
+			}
+		}
+		conf.Variables = variables
+	}
+}
+
+func hasOrigin(conf *contracts.AzionApplicationOptions, key string) bool {
+	for _, origin := range conf.Origin {
+		if origin.OriginKey == key {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCache(conf *contracts.AzionApplicationOptions, id int64) bool {
+	for _, cache := range conf.CacheSettings {
+		if cache.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRule(conf *contracts.AzionApplicationOptions, id int64) bool {
+	for _, rule := range conf.RulesEngine.Rules {
+		if rule.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDeviceGroup(conf *contracts.AzionApplicationOptions, id int64) bool {
+	for _, group := range conf.DeviceGroups {
+		if group.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func hasManifestFunction(conf *contracts.AzionApplicationOptions, id int64) bool {
+	for _, function := range conf.Functions {
+		if function.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func hasFunctionInstance(conf *contracts.AzionApplicationOptions, id int64) bool {
+	for _, instance := range conf.FunctionInstances {
+		if instance.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func hasManifestDomain(conf *contracts.AzionApplicationOptions, id int64) bool {
+	for _, domain := range conf.Domains {
+		if domain.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func hasVariable(conf *contracts.AzionApplicationOptions, uuid string) bool {
+	for _, variable := range conf.Variables {
+		if variable.Uuid == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+// handleFailure decides what happens to the resources created by a failed deploy: they are removed when
+// --rollback-on-failure is sent or the user agrees to it, otherwise they are kept for the next deploy to resume from
+func (cmd *DeployCmd) handleFailure(
+	ctx context.Context,
+	clients *Clients,
+	conf *contracts.AzionApplicationOptions,
+	journal *Journal,
+	cause error) error {
+	if len(journal.Entries) == 0 {
+		return cause
+	}
+
+	out := cmd.F.IOStreams.Out
+	logger.FInfoFlags(out, fmt.Sprintf(msg.JournalFailed, len(journal.Entries)), cmd.F.Format, cmd.F.Out)
+	for _, entry := range journal.Entries {
+		logger.FInfoFlags(out, describeEntry(entry), cmd.F.Format, cmd.F.Out)
+	}
+
+	rollback := RollbackOnFailure
+	if !rollback && cmd.canAsk() {
+		rollback = utils.Confirm(cmd.F.GlobalFlagAll, msg.AskRollbackOnFailure, false)
+	}
+
+	if !rollback {
+		logger.FInfoFlags(out, msg.JournalResumePlan, cmd.F.Format, cmd.F.Out)
+		return cause
+	}
+
+	if err := cmd.rollbackJournal(ctx, clients, conf, journal); err != nil {
+		logger.Debug("Error while removing the resources created by the deploy", zap.Error(err))
+		return fmt.Errorf(msg.ErrorRollbackOnFailure.Error(), cause, err)
+	}
+
+	logger.FInfoFlags(out, msg.JournalRolledBack, cmd.F.Format, cmd.F.Out)
+	return cause
+}
+
+// rollbackJournal removes the tracked resources, newest first, so instances go before their functions and
+// nested resources before their edge application. Progress is saved after each removal.
+func (cmd *DeployCmd) rollbackJournal(
+	ctx context.Context,
+	clients *Clients,
+	conf *contracts.AzionApplicationOptions,
+	journal *Journal) error {
+	for i := len(journal.Entries) - 1; i >= 0; i-- {
+		entry := journal.Entries[i]
+		err := cmd.deleteEntry(ctx, clients, entry)
+		if err != nil && !errors.Is(err, utils.ErrorNotFound404) {
+			return err
+		}
+
+		forget(conf, entry)
+		if err := cmd.WriteAzionJsonContent(conf, ProjectConf); err != nil {
+			logger.Debug("Error while writing azion.json file", zap.Error(err))
+			return err
+		}
+		journal.Entries = journal.Entries[:i]
+		if err := journal.write(); err != nil {
+			logger.Debug("Error while writing the deploy journal", zap.Error(err))
+		}
+
+		msgf := fmt.Sprintf(msg.JournalRemoved, entry.Resource, entry.Name)
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+	}
+
+	return journal.complete()
+}
+
+func (cmd *DeployCmd) deleteEntry(ctx context.Context, clients *Clients, entry contracts.JournalEntry) error {
+	switch entry.Resource {
+	case resourceApplication:
+		return clients.EdgeApplication.Delete(ctx, entry.ID)
+	case resourceFunction, manifestInt.ResourceFunction:
+		return clients.EdgeFunction.Delete(ctx, entry.ID)
+	case resourceInstance, manifestInt.ResourceFunctionInstance:
+		return clients.EdgeApplication.DeleteFunctionInstance(ctx,
+			strconv.FormatInt(entry.ApplicationID, 10), strconv.FormatInt(entry.ID, 10))
+	case resourceDomain, manifestInt.ResourceDomain:
+		return clients.Domain.Delete(ctx, entry.ID)
+	case resourceBucket:
+		return emptyAndDeleteBucket(ctx, clients, entry.Name)
+	case manifestInt.ResourceOrigin:
+		return clients.Origin.DeleteOrigins(ctx, entry.ApplicationID, entry.Key)
+	case manifestInt.ResourceCache:
+		return clients.Cache.Delete(ctx, entry.ApplicationID, entry.ID)
+	case manifestInt.ResourceRule, resourceDefaultRule:
+		return clients.EdgeApplication.DeleteRulesEngine(ctx, entry.ApplicationID, entry.Phase, entry.ID)
+	case manifestInt.ResourceDeviceGroup:
+		return clients.EdgeApplication.DeleteDeviceGroup(ctx, entry.ApplicationID, entry.ID)
+	case manifestInt.ResourceVariable:
+		return clients.Variables.Delete(ctx, entry.Key)
+	}
+	return nil
+}
+
+// emptyAndDeleteBucket removes the objects uploaded to a bucket created by the deploy, since only empty buckets can be deleted
+func emptyAndDeleteBucket(ctx context.Context, clients *Clients, bucket string) error {
+	opts := &contracts.ListOptions{PageSize: 1000}
+	for {
+		resp, err := clients.Storage.ListObject(ctx, bucket, opts)
+		if err != nil {
+			return err
+		}
+		for _, object := range resp.GetResults() {
+			if err := clients.Storage.DeleteObject(ctx, bucket, object.GetKey()); err != nil {
+				return err
+			}
+		}
+		if resp.GetContinuationToken() == "" || len(resp.GetResults()) == 0 {
+			break
+		}
+		opts.ContinuationToken = resp.GetContinuationToken()
+	}
+	return clients.Bucket.DeleteBucket(ctx, bucket)
+}
+
+func describeEntry(entry contracts.JournalEntry) string {
+	id := entry.Key
+	if entry.ID > 0 {
+		id = strconv.FormatInt(entry.ID, 10)
+	}
+	if id == "" {
+		return fmt.Sprintf(msg.JournalEntryNoID, entry.Resource, entry.Name)
+	}
+	return fmt.Sprintf(msg.JournalEntry, entry.Resource, entry.Name, id)
+}
@@ -0,0 +1,187 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+var journalEntries = []contracts.JournalEntry{
+	{Resource: resourceApplication, ID: 1, Name: "app"},
+	{Resource: manifestInt.ResourceOrigin, ID: 2, Key: "origin-key", Name: "app_single", ApplicationID: 1},
+	{Resource: resourceFunction, ID: 3, Name: "func"},
+	{Resource: resourceInstance, ID: 4, Name: "instance", ApplicationID: 1},
+	{Resource: manifestInt.ResourceRule, ID: 6, Name: "rule", ApplicationID: 1, Phase: "request"},
+	{Resource: resourceDomain, ID: 5, Name: "domain"},
+}
+
+func TestJournal(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	t.Run("track persists every entry", func(t *testing.T) {
+		dir := t.TempDir()
+		journal, err := readJournal(dir)
+		require.NoError(t, err)
+		require.Empty(t, journal.Entries)
+
+		journal.VersionID = "20240101000000"
+		for _, entry := range journalEntries {
+			require.NoError(t, journal.track(entry))
+		}
+
+		read, err := readJournal(dir)
+		require.NoError(t, err)
+		require.Equal(t, "20240101000000", read.VersionID)
+		require.Equal(t, journalEntries, read.Entries)
+
+		require.NoError(t, read.complete())
+		_, err = os.Stat(filepath.Join(dir, journalFile))
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("track returns the write error and keeps the entry", func(t *testing.T) {
+		journal := &Journal{confPath: filepath.Join(t.TempDir(), "missing")}
+		err := journal.track(journalEntries[0])
+		require.Error(t, err)
+		require.Equal(t, journalEntries[:1], journal.Entries)
+	})
+
+	t.Run("restore is idempotent and forget undoes it", func(t *testing.T) {
+		journal := &Journal{Entries: journalEntries}
+		conf := &contracts.AzionApplicationOptions{NotFirstRun: true}
+
+		journal.restore(conf)
+		journal.restore(conf)
+		require.Equal(t, int64(1), conf.Application.ID)
+		require.Equal(t, int64(3), conf.Function.ID)
+		require.Equal(t, int64(4), conf.Function.InstanceID)
+		require.Equal(t, int64(5), conf.Domain.Id)
+		require.Len(t, conf.Origin, 1)
+		require.Len(t, conf.RulesEngine.Rules, 1)
+
+		for _, entry := range journalEntries {
+			forget(conf, entry)
+		}
+		require.Zero(t, conf.Application.ID)
+		require.Zero(t, conf.Function.ID)
+		require.Zero(t, conf.Function.InstanceID)
+		require.Zero(t, conf.Domain.Id)
+		require.Empty(t, conf.Origin)
+		require.Empty(t, conf.RulesEngine.Rules)
+		require.False(t, conf.NotFirstRun)
+	})
+
+	t.Run("rollback removes the newest resources first", func(t *testing.T) {
+		ProjectConf = t.TempDir()
+		defer func() { ProjectConf = "azion" }()
+
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST("DELETE", "domains/5"), httpmock.StatusStringResponse(http.StatusNoContent, ""))
+		mock.Register(httpmock.REST("DELETE", "edge_applications/1/rules_engine/request/rules/6"), httpmock.StatusStringResponse(http.StatusNoContent, ""))
+		mock.Register(httpmock.REST("DELETE", "edge_applications/1/functions_instances/4"), httpmock.StatusStringResponse(http.StatusNoContent, ""))
+		mock.Register(httpmock.REST("DELETE", "edge_functions/3"), httpmock.StatusStringResponse(http.StatusNoContent, ""))
+		mock.Register(httpmock.REST("DELETE", "edge_applications/1/origins/origin-key"), httpmock.StatusStringResponse(http.StatusNoContent, ""))
+		mock.Register(httpmock.REST("DELETE", "edge_applications/1"), httpmock.StatusStringResponse(http.StatusNotFound, ""))
+
+		f, _, _ := testutils.NewFactory(mock)
+		cmd := NewDeployCmd(f)
+		written := 0
+		cmd.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+			written++
+			return nil
+		}
+
+		journal := &Journal{confPath: ProjectConf, Entries: append([]contracts.JournalEntry{}, journalEntries...)}
+		require.NoError(t, journal.write())
+		conf := &contracts.AzionApplicationOptions{}
+		journal.restore(conf)
+
+		RollbackOnFailure = true
+		defer func() { RollbackOnFailure = false }()
+
+		cause := errors.New("Failed to create the Domain")
+		err := cmd.handleFailure(context.Background(), NewClients(f), conf, journal, cause)
+		require.ErrorIs(t, err, cause)
+		mock.Verify(t)
+
+		require.Len(t, mock.Requests, len(journalEntries))
+		require.Equal(t, "/domains/5", mock.Requests[0].URL.Path)
+		require.Equal(t, "/edge_applications/1", mock.Requests[len(mock.Requests)-1].URL.Path)
+		require.Equal(t, len(journalEntries), written)
+		require.Zero(t, conf.Application.ID)
+		require.Empty(t, journal.Entries)
+		_, err = os.Stat(filepath.Join(ProjectConf, journalFile))
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("failure without rollback keeps the journal", func(t *testing.T) {
+		ProjectConf = t.TempDir()
+		defer func() { ProjectConf = "azion" }()
+
+		Auto = true
+		defer func() { Auto = false }()
+
+		f, stdout, _ := testutils.NewFactory(&httpmock.Registry{})
+		cmd := NewDeployCmd(f)
+
+		journal := &Journal{confPath: ProjectConf}
+		require.NoError(t, journal.track(journalEntries[0]))
+
+		cause := errors.New("Failed to create the Edge Function")
+		err := cmd.handleFailure(context.Background(), NewClients(f), &contracts.AzionApplicationOptions{}, journal, cause)
+		require.ErrorIs(t, err, cause)
+		require.Contains(t, stdout.String(), "edge_application app (1)")
+
+		read, err := readJournal(ProjectConf)
+		require.NoError(t, err)
+		require.Len(t, read.Entries, 1)
+	})
+
+	t.Run("first deploy journals its default rule", func(t *testing.T) {
+		ProjectConf = t.TempDir()
+		defer func() { ProjectConf = "azion" }()
+
+		Auto = true
+		defer func() { Auto = false }()
+
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST("POST", "edge_applications/1/rules_engine/response/rules"),
+			httpmock.JSONFromString(`{"schema_version": 3, "results": {"id": 7, "name": "enable gzip", "phase": "response",
+				"criteria": [], "behaviors": [], "is_active": true, "order": 1}}`))
+
+		f, _, _ := testutils.NewFactory(mock)
+		cmd := NewDeployCmd(f)
+		cmd.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+			return nil
+		}
+		cmd.journal = &Journal{confPath: ProjectConf}
+
+		conf := &contracts.AzionApplicationOptions{Application: contracts.AzionJsonDataApplication{ID: 1}}
+		msgs := []string{}
+		err := cmd.doRulesDeploy(context.Background(), conf, NewClients(f).EdgeApplication, &msgs)
+		require.NoError(t, err)
+		mock.Verify(t)
+
+		read, err := readJournal(ProjectConf)
+		require.NoError(t, err)
+		require.Equal(t, []contracts.JournalEntry{{
+			Resource: resourceDefaultRule, ID: 7, Name: "enable gzip", ApplicationID: 1, Phase: "response",
+		}}, read.Entries)
+
+		// a resumed deploy doesn't create the rule again
+		resumed := &contracts.AzionApplicationOptions{}
+		read.restore(resumed)
+		require.True(t, resumed.NotFirstRun)
+	})
+}
@@ -0,0 +1,142 @@
+package deploy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aziontech/azion-cli/pkg/api/storage"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// emptyFileHash is the hash files.json records for an empty file. The worker doesn't upload empty files, so their
+// objects are never found in the bucket
+var emptyFileHash = fmt.Sprintf("%x", sha256.Sum256(nil))
+
+// uploadPlan tells whether a deploy can serve the objects of the previous deploy instead of uploading its static files
+type uploadPlan struct {
+	// Reuse is the prefix of the previous deploy when no file changed, so its objects are served as they are
+	Reuse string
+	// From is the prefix of the previous deploy the files are compared with
+	From string
+	// Unchanged are the files whose hash matches files.json and whose object is still in the bucket, or that are empty
+	Unchanged map[string]bool
+	Changed   int
+}
+
+// planUpload compares the hashes of the static files with the ones files.json recorded for the previous deploy. When no
+// file changed, the objects under the prefix of the previous deploy are reused as they are. Otherwise every file is
+// uploaded under the new prefix: the storage API can't copy objects, and reading an unchanged object back only to
+// upload it again would move its bytes twice.
+func (cmd *DeployCmd) planUpload(
+	ctx context.Context,
+	client *storage.Client,
+	conf *contracts.AzionApplicationOptions,
+	files []Data) (*uploadPlan, error) {
+	plan := &uploadPlan{Unchanged: make(map[string]bool), Changed: len(files)}
+
+	previous, err := ReadFilesJSONL(ProjectConf)
+	if err != nil {
+		// a corrupted files.json only costs a full upload
+		logger.Debug("Error while reading files.json, uploading every file", zap.Error(err))
+		return plan, nil
+	}
+	prefix, err := cmd.previousPrefix(conf.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if len(previous) == 0 || prefix == "" {
+		return plan, nil
+	}
+
+	hashes := make(map[string]string, len(previous))
+	for _, file := range previous {
+		hashes[file.Name] = file.Hash
+	}
+
+	// the objects may have been removed from the bucket since files.json was written
+	keys, err := listObjectKeys(ctx, client, conf.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		hash, ok := hashes[file.Name]
+		if !ok || hash != file.Hash {
+			continue
+		}
+		if hash != emptyFileHash && !keys[objectKey(prefix, file.Name)] {
+			logger.Debug("Object of the previous upload is missing", zap.String("file", file.Name))
+			continue
+		}
+		plan.Unchanged[file.Name] = true
+	}
+	plan.Changed = len(files) - len(plan.Unchanged)
+	plan.From = prefix
+	if plan.Changed == 0 && len(previous) == len(files) {
+		plan.Reuse = prefix
+	}
+	return plan, nil
+}
+
+// previousPrefix returns the prefix the last deploy recorded in deployments.json stored its files under in the bucket.
+// Deploys whose files were pruned are skipped, since there is nothing left to reuse from them.
+func (cmd *DeployCmd) previousPrefix(bucket string) (string, error) {
+	history, err := cmd.GetDeploymentHistory(ProjectConf)
+	if err != nil {
+		logger.Debug("Error while reading deployments.json file", zap.Error(err))
+		return "", err
+	}
+	for i := len(history.Deployments) - 1; i >= 0; i-- {
+		record := history.Deployments[i]
+		if record.Pruned {
+			continue
+		}
+		if record.Bucket != bucket {
+			return "", nil
+		}
+		return record.StoragePrefix(), nil
+	}
+	return "", nil
+}
+
+func listObjectKeys(ctx context.Context, client *storage.Client, bucket string) (map[string]bool, error) {
+	keys := make(map[string]bool)
+	opts := &contracts.ListOptions{PageSize: 1000}
+	for {
+		resp, err := client.ListObject(ctx, bucket, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range resp.GetResults() {
+			keys[object.GetKey()] = true
+		}
+		if resp.GetContinuationToken() == "" || len(resp.GetResults()) == 0 {
+			return keys, nil
+		}
+		opts.ContinuationToken = resp.GetContinuationToken()
+	}
+}
+
+// writeFilesJSONL records the hashes of the static files of a successful deploy, which the next deploy compares with
+func writeFilesJSONL(files []Data, confPath string) error {
+	content, err := json.MarshalIndent(files, "  ", " ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(confPath, "files.json"), content, 0644)
+}
+
+// objectKey mirrors the key storage.Client.Upload builds for a file found under PathStatic
+func objectKey(prefix, path string) string {
+	rel, err := filepath.Rel(PathStatic, path)
+	if err != nil {
+		rel = path
+	}
+	return prefix + "/" + filepath.ToSlash(rel)
+}
@@ -0,0 +1,158 @@
+package deploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aziontech/azion-cli/pkg/api/storage"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+var objectsResponse = `
+{
+	"continuation_token": null,
+	"results": [
+		{"key": "20240101000000/index.html", "last_modified": "2024-01-01T00:00:00Z", "size": 120},
+		{"key": "20240101000000/js/main.js", "last_modified": "2024-01-01T00:00:00Z", "size": 2048}
+	]
+}
+`
+
+func Test_planUpload(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	uploaded := []Data{
+		{Name: ".edge/storage/index.html", Hash: "aaa"},
+		{Name: ".edge/storage/js/main.js", Hash: "bbb"},
+	}
+
+	tests := []struct {
+		name          string
+		bucket        string
+		previous      []Data
+		files         []Data
+		wantReuse     string
+		wantFrom      string
+		wantUnchanged map[string]bool
+		wantChanged   int
+	}{
+		{
+			name:          "unchanged files reuse the previous prefix",
+			bucket:        "bucket",
+			files:         uploaded,
+			wantReuse:     "20240101000000",
+			wantFrom:      "20240101000000",
+			wantUnchanged: map[string]bool{uploaded[0].Name: true, uploaded[1].Name: true},
+		},
+		{
+			name:          "changed file",
+			bucket:        "bucket",
+			files:         []Data{uploaded[0], {Name: ".edge/storage/js/main.js", Hash: "ccc"}},
+			wantFrom:      "20240101000000",
+			wantUnchanged: map[string]bool{uploaded[0].Name: true},
+			wantChanged:   1,
+		},
+		{
+			name:          "new file",
+			bucket:        "bucket",
+			files:         append([]Data{{Name: ".edge/storage/about.html", Hash: "ddd"}}, uploaded...),
+			wantFrom:      "20240101000000",
+			wantUnchanged: map[string]bool{uploaded[0].Name: true, uploaded[1].Name: true},
+			wantChanged:   1,
+		},
+		{
+			name:          "object removed from the bucket",
+			bucket:        "bucket",
+			previous:      append([]Data{{Name: ".edge/storage/gone.css", Hash: "eee"}}, uploaded...),
+			files:         append([]Data{{Name: ".edge/storage/gone.css", Hash: "eee"}}, uploaded...),
+			wantFrom:      "20240101000000",
+			wantUnchanged: map[string]bool{uploaded[0].Name: true, uploaded[1].Name: true},
+			wantChanged:   1,
+		},
+		{
+			name:          "empty files are never uploaded",
+			bucket:        "bucket",
+			previous:      append([]Data{{Name: ".edge/storage/.nojekyll", Hash: emptyFileHash}}, uploaded...),
+			files:         append([]Data{{Name: ".edge/storage/.nojekyll", Hash: emptyFileHash}}, uploaded...),
+			wantReuse:     "20240101000000",
+			wantFrom:      "20240101000000",
+			wantUnchanged: map[string]bool{".edge/storage/.nojekyll": true, uploaded[0].Name: true, uploaded[1].Name: true},
+		},
+		{
+			name:          "different bucket",
+			bucket:        "other",
+			files:         uploaded,
+			wantUnchanged: map[string]bool{},
+			wantChanged:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ProjectConf = t.TempDir()
+			defer func() { ProjectConf = "azion" }()
+
+			previous := uploaded
+			if tt.previous != nil {
+				previous = tt.previous
+			}
+			require.NoError(t, writeFilesJSONL(previous, ProjectConf))
+
+			mock := &httpmock.Registry{}
+			mock.Register(
+				httpmock.REST("GET", "v4/storage/buckets/bucket/objects"),
+				httpmock.JSONFromString(objectsResponse),
+			)
+			f, _, _ := testutils.NewFactory(mock)
+			client := storage.NewClient(f.HttpClient, f.Config.GetString("storage_url"), f.Config.GetString("token"))
+
+			cmd := NewDeployCmd(f)
+			cmd.GetDeploymentHistory = func(confPath string) (*contracts.DeploymentHistory, error) {
+				return &contracts.DeploymentHistory{Deployments: []contracts.DeploymentRecord{
+					{VersionID: "20240101000000", Bucket: "bucket", Prefix: "20240101000000"},
+				}}, nil
+			}
+			conf := &contracts.AzionApplicationOptions{Bucket: tt.bucket}
+			got, err := cmd.planUpload(context.Background(), client, conf, tt.files)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantReuse, got.Reuse)
+			require.Equal(t, tt.wantFrom, got.From)
+			require.Equal(t, tt.wantUnchanged, got.Unchanged)
+			require.Equal(t, tt.wantChanged, got.Changed)
+		})
+	}
+
+	t.Run("pruned deploys are skipped", func(t *testing.T) {
+		f, _, _ := testutils.NewFactory(&httpmock.Registry{})
+		cmd := NewDeployCmd(f)
+		cmd.GetDeploymentHistory = func(confPath string) (*contracts.DeploymentHistory, error) {
+			return &contracts.DeploymentHistory{Deployments: []contracts.DeploymentRecord{
+				{VersionID: "20240101000000", Bucket: "bucket"},
+				{VersionID: "20240201000000", Bucket: "bucket", Pruned: true},
+			}}, nil
+		}
+		prefix, err := cmd.previousPrefix("bucket")
+		require.NoError(t, err)
+		require.Equal(t, "20240101000000", prefix)
+	})
+
+	t.Run("first deploy uploads every file", func(t *testing.T) {
+		ProjectConf = t.TempDir()
+		defer func() { ProjectConf = "azion" }()
+
+		f, _, _ := testutils.NewFactory(&httpmock.Registry{})
+		cmd := NewDeployCmd(f)
+		cmd.GetDeploymentHistory = func(confPath string) (*contracts.DeploymentHistory, error) {
+			return &contracts.DeploymentHistory{}, nil
+		}
+		got, err := cmd.planUpload(context.Background(), nil, &contracts.AzionApplicationOptions{Bucket: "bucket"}, uploaded)
+		require.NoError(t, err)
+		require.Equal(t, 2, got.Changed)
+		require.Empty(t, got.Unchanged)
+	})
+}
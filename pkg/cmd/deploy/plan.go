@@ -0,0 +1,237 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	msg "github.com/aziontech/azion-cli/messages/deploy"
+	msgmanifest "github.com/aziontech/azion-cli/messages/manifest"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
+	"github.com/aziontech/azion-cli/pkg/output"
+	"github.com/aziontech/azion-cli/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	resourceApplication = "edge_application"
+	resourceFunction    = "edge_function"
+	resourceInstance    = "function_instance"
+	resourceBucket      = "bucket"
+	resourceStorage     = "storage_objects"
+	resourceDomain      = "domain"
+	resourcePurge       = "cache_purge"
+	// the gzip rule the first deploy creates, which isn't declared in the manifest
+	resourceDefaultRule = "default_rule"
+)
+
+// dryRun prints every action a deploy would take without calling any mutating endpoint.
+// Nothing is written to azion.json and the build step is skipped.
+func (cmd *DeployCmd) dryRun(f *cmdutil.Factory, conf *contracts.AzionApplicationOptions, msgs *[]string) error {
+	ctx := context.Background()
+	clients := NewClients(f)
+	interpreter := cmd.Interpreter()
+	interpreter.OverridesPath = cmd.manifestOverrides
+	interpreter.Prune = Prune
+	interpreter.ConfirmDelete = func([]contracts.ResourcePlan) bool { return cmd.plannedDelete() }
+	interpreter.EnvPath = Env
+	plan := []contracts.ResourcePlan{}
+
+	pathManifest, err := interpreter.ManifestPath()
+	if err != nil {
+		return err
+	}
+
+	err = interpreter.ValidateManifest(pathManifest)
+	if err != nil {
+		return err
+	}
+
+	application, err := planResource(resourceApplication, conf.Application.ID, nameOrDefault(conf.Application.Name, conf.Name),
+		func() error {
+			_, err := clients.EdgeApplication.Get(ctx, strconv.FormatInt(conf.Application.ID, 10))
+			return err
+		})
+	if err != nil {
+		return err
+	}
+	plan = append(plan, application)
+
+	if !conf.NotFirstRun {
+		plan = append(plan, contracts.ResourcePlan{
+			Resource: manifestInt.ResourceOrigin,
+			Name:     utils.Concat(conf.Name, "_single"),
+			Action:   manifestInt.PlanCreate,
+		})
+	}
+
+	if conf.Bucket == "" && conf.Preset != "javascript" && conf.Preset != "typescript" {
+		plan = append(plan, contracts.ResourcePlan{
+			Resource: resourceBucket,
+			Name:     replaceInvalidChars(conf.Name),
+			Action:   manifestInt.PlanCreate,
+		})
+	}
+
+	// old versions are only deleted once the deploy succeeds, so they come last
+	var pruned []contracts.ResourcePlan
+	if _, err := os.Stat(PathStatic); err == nil {
+		totalFiles := 0
+		if err := cmd.FilepathWalk(PathStatic, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				totalFiles++
+			}
+			return nil
+		}); err != nil {
+			logger.Debug("Error while reading files to be uploaded", zap.Error(err))
+			return err
+		}
+		upload := contracts.ResourcePlan{
+			Resource: resourceStorage,
+			Name:     utils.Concat(nameOrDefault(conf.Bucket, replaceInvalidChars(conf.Name)), "/", conf.Prefix),
+			Action:   msg.PlanUpload,
+			Details:  fmt.Sprintf(msg.PlanFilesCount, totalFiles),
+		}
+		if conf.Bucket != "" {
+			files, err := ReadFilesEdgeStorage()
+			if err != nil {
+				return err
+			}
+			uploads, err := cmd.planUpload(ctx, clients.Storage, conf, files)
+			if err != nil {
+				logger.Debug("Error while checking the objects of the previous upload", zap.Error(err))
+				return err
+			}
+			current := conf.Prefix
+			if uploads.Reuse != "" {
+				upload.Name = utils.Concat(conf.Bucket, "/", uploads.Reuse)
+				upload.Action = msg.PlanReuse
+				current = uploads.Reuse
+			} else if len(uploads.Unchanged) > 0 {
+				upload.Details = fmt.Sprintf(msg.PlanFilesChanged, len(files), uploads.Changed, uploads.From)
+			}
+			pruned, err = planPrune(ctx, clients, conf.Bucket, current)
+			if err != nil {
+				return err
+			}
+		}
+		plan = append(plan, upload)
+	}
+
+	function, err := planResource(resourceFunction, conf.Function.ID, nameOrDefault(conf.Function.Name, conf.Name),
+		func() error {
+			_, err := clients.EdgeFunction.Get(ctx, conf.Function.ID)
+			return err
+		})
+	if err != nil {
+		return err
+	}
+	plan = append(plan, function)
+
+	instance := contracts.ResourcePlan{
+		Resource: resourceInstance,
+		Name:     nameOrDefault(conf.Function.InstanceName, conf.Name),
+		Action:   manifestInt.PlanCreate,
+	}
+	if conf.Function.InstanceID > 0 {
+		instance.Action = manifestInt.PlanUpdate
+		instance.Id = strconv.FormatInt(conf.Function.InstanceID, 10)
+	}
+	plan = append(plan, instance)
+
+	if !conf.NotFirstRun {
+		plan = append(plan, contracts.ResourcePlan{
+			Resource: manifestInt.ResourceRule,
+			Name:     "Default Rule",
+			Action:   manifestInt.PlanUpdate,
+		})
+		if len(conf.RulesEngine.Rules) == 0 {
+			plan = append(plan, contracts.ResourcePlan{
+				Resource: manifestInt.ResourceRule,
+				Name:     "enable gzip",
+				Action:   manifestInt.PlanCreate,
+				Details:  fmt.Sprintf(msgmanifest.PlanPhase, "response"),
+			})
+		}
+	}
+
+	manifestStructure, err := interpreter.ReadManifest(pathManifest, f, msgs)
+	if err != nil {
+		return err
+	}
+
+	resources, err := interpreter.PlanResources(conf, manifestStructure, f)
+	if err != nil {
+		return err
+	}
+	plan = append(plan, resources...)
+
+	domain, err := planResource(resourceDomain, conf.Domain.Id, nameOrDefault(conf.Domain.Name, conf.Name),
+		func() error {
+			_, err := clients.Domain.Get(ctx, strconv.FormatInt(conf.Domain.Id, 10))
+			return err
+		})
+	if err != nil {
+		return err
+	}
+	plan = append(plan, domain)
+
+	if conf.RtPurge.PurgeOnPublish && conf.Domain.Id > 0 {
+		plan = append(plan, contracts.ResourcePlan{
+			Resource: resourcePurge,
+			Name:     conf.Domain.DomainName,
+			Action:   msg.PlanPurge,
+		})
+	}
+
+	plan = append(plan, pruned...)
+
+	planOut := output.PlanOutput{
+		Actions: plan,
+		GeneralOutput: output.GeneralOutput{
+			Out:   cmd.F.IOStreams.Out,
+			Flags: cmd.F.Flags,
+		},
+	}
+
+	return output.Print(&planOut)
+}
+
+// planResource decides between creating and updating a resource tracked by a single ID in azion.json,
+// using get to find out whether the tracked resource still exists remotely
+func planResource(resource string, id int64, name string, get func() error) (contracts.ResourcePlan, error) {
+	if id == 0 {
+		return contracts.ResourcePlan{Resource: resource, Name: name, Action: manifestInt.PlanCreate}, nil
+	}
+
+	action := contracts.ResourcePlan{
+		Resource: resource,
+		Name:     name,
+		Id:       strconv.FormatInt(id, 10),
+		Action:   manifestInt.PlanUpdate,
+	}
+	if err := get(); err != nil {
+		if !errors.Is(err, utils.ErrorNotFound404) {
+			logger.Debug("Error while reading remote resource", zap.String("resource", resource), zap.Error(err))
+			return action, err
+		}
+		action.Details = msgmanifest.PlanNotFoundRemotely
+	}
+	return action, nil
+}
+
+func nameOrDefault(name, projectName string) string {
+	if name == "" || strings.EqualFold(name, "__DEFAULT__") {
+		return projectName
+	}
+	return name
+}
@@ -0,0 +1,77 @@
+package deploy
+
+import (
+	"errors"
+	"testing"
+
+	msgmanifest "github.com/aziontech/azion-cli/messages/manifest"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/aziontech/azion-cli/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_planResource(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int64
+		getErr  error
+		want    contracts.ResourcePlan
+		wantErr bool
+	}{
+		{
+			name: "not tracked yet",
+			want: contracts.ResourcePlan{Resource: resourceDomain, Name: "proj", Action: manifestInt.PlanCreate},
+		},
+		{
+			name: "tracked and found remotely",
+			id:   1234,
+			want: contracts.ResourcePlan{Resource: resourceDomain, Name: "proj", Id: "1234", Action: manifestInt.PlanUpdate},
+		},
+		{
+			name:   "tracked but missing remotely",
+			id:     1234,
+			getErr: utils.ErrorNotFound404,
+			want: contracts.ResourcePlan{
+				Resource: resourceDomain, Name: "proj", Id: "1234", Action: manifestInt.PlanUpdate, Details: msgmanifest.PlanNotFoundRemotely},
+		},
+		{
+			name:    "remote read failure",
+			id:      1234,
+			getErr:  errors.New("boom"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := planResource(resourceDomain, tt.id, "proj", func() error { return tt.getErr })
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_dryRunInvalidManifest(t *testing.T) {
+	mock := &httpmock.Registry{}
+	f, _, _ := testutils.NewFactory(mock)
+	cmd := NewDeployCmd(f)
+	cmd.Interpreter = func() *manifestInt.ManifestInterpreter {
+		interpreter := manifestInt.NewManifestInterpreter()
+		interpreter.GetWorkDir = func() (string, error) { return "", nil }
+		interpreter.FileReader = func(path string) ([]byte, error) {
+			return []byte(`{"rules": [{"name": "r", "phase": "deliver"}]}`), nil
+		}
+		return interpreter
+	}
+
+	msgs := []string{}
+	err := cmd.dryRun(f, &contracts.AzionApplicationOptions{Application: contracts.AzionJsonDataApplication{ID: 1}}, &msgs)
+	require.ErrorIs(t, err, msgmanifest.ErrorInvalidManifest)
+	require.Empty(t, mock.Requests)
+}
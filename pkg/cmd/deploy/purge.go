@@ -13,7 +13,9 @@ import (
 	msg "github.com/aziontech/azion-cli/messages/deploy"
 	apidom "github.com/aziontech/azion-cli/pkg/api/domain"
 	apipurge "github.com/aziontech/azion-cli/pkg/api/realtime_purge"
+	"github.com/aziontech/azion-cli/pkg/contracts"
 	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
 	"go.uber.org/zap"
 )
 
@@ -34,6 +36,7 @@ func (cmd *DeployCmd) PurgeWildcard(domain []string, path string) error {
 		logger.Debug("Error while purging wildcard domain", zap.Error(err))
 		return err
 	}
+	cmd.report.PurgedURLs = append(cmd.report.PurgedURLs, purgeDomains...)
 	return nil
 }
 
@@ -49,16 +52,25 @@ func (cmd *DeployCmd) PurgeUrls(domain []string, path string) error {
 		logger.Debug("Error while purging urls domain", zap.Error(err))
 		return err
 	}
+	cmd.report.PurgedURLs = append(cmd.report.PurgedURLs, purgeDomains...)
 	return nil
 }
 
-func PurgeForUpdatedFiles(cmd *DeployCmd, domain apidom.DomainResponse, confPath string, msgs *[]string) error {
-	listURLsDomains := domain.GetCnames()
+// purgeHostnames returns every hostname the project is served under: the ones of its domain and of the domains
+// declared in the manifest
+func purgeHostnames(domain apidom.DomainResponse, conf *contracts.AzionApplicationOptions) []string {
+	hostnames := append([]string{}, domain.GetCnames()...)
 	if !domain.GetCnameAccessOnly() {
-		listURLsDomains = append(listURLsDomains, domain.GetDomainName())
+		hostnames = append(hostnames, domain.GetDomainName())
 	}
+	for _, declared := range conf.Domains {
+		hostnames = append(hostnames, manifestInt.Hostnames(declared)...)
+	}
+	return hostnames
+}
 
-	currentDataMap, err := ReadFilesJSONL()
+func PurgeForUpdatedFiles(cmd *DeployCmd, listURLsDomains []string, confPath string, msgs *[]string) error {
+	currentDataMap, err := ReadFilesJSONL(confPath)
 	if err != nil {
 		return err
 	}
@@ -99,27 +111,13 @@ func PurgeForUpdatedFiles(cmd *DeployCmd, domain apidom.DomainResponse, confPath
 		}
 	}
 
-	jsonl, err := json.MarshalIndent(newData, "  ", " ")
-	if err != nil {
-		return err
-	}
-
-	jsonlFile, err := os.Create(path.Join(confPath, "files.json"))
-	if err != nil {
-		return err
-	}
-	defer jsonlFile.Close()
-
-	if _, err := jsonlFile.Write(jsonl); err != nil {
-		return err
-	}
-
+	// files.json is written once the deploy succeeds, so a failed deploy is compared with the last successful one
 	return nil
 }
 
-func ReadFilesJSONL() ([]Data, error) {
+func ReadFilesJSONL(confPath string) ([]Data, error) {
 	var dt []Data
-	file, err := os.Open("./azion/files.json")
+	file, err := os.Open(path.Join(confPath, "files.json"))
 	if os.IsNotExist(err) {
 		return dt, nil
 	}
@@ -0,0 +1,30 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	sdk "github.com/aziontech/azionapi-go-sdk/domains"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeHostnames(t *testing.T) {
+	domain := &sdk.DomainResults{
+		DomainName:      "proj.map.azionedge.net",
+		Cnames:          []string{"proj.example.com"},
+		CnameAccessOnly: false,
+	}
+	conf := &contracts.AzionApplicationOptions{
+		Domains: []contracts.AzionJsonDataManifestDomain{
+			{Name: "site", DomainName: "site.map.azionedge.net", Cnames: []string{"www.example.com"}, CnameAccessOnly: true},
+			{Name: "api", DomainName: "api.map.azionedge.net"},
+		},
+	}
+
+	require.Equal(t, []string{
+		"proj.example.com",
+		"proj.map.azionedge.net",
+		"www.example.com",
+		"api.map.azionedge.net",
+	}, purgeHostnames(domain, conf))
+}
@@ -0,0 +1,55 @@
+package deploy
+
+import (
+	"time"
+
+	"github.com/aziontech/azion-cli/pkg/contracts"
+)
+
+const (
+	phaseSync        = "sync"
+	phaseBuild       = "build"
+	phaseApplication = "application"
+	phaseStorage     = "storage"
+	phaseFunction    = "function"
+	phaseManifest    = "manifest"
+	phaseDomain      = "domain"
+	phasePrune       = "prune"
+
+	reportSucceeded = "succeeded"
+	reportFailed    = "failed"
+)
+
+// newReport starts an empty report, whose lists are printed as empty rather than null
+func newReport() *contracts.DeployReport {
+	return &contracts.DeployReport{
+		PurgedURLs: []string{},
+		Resources:  []contracts.ResourcePlan{},
+		Phases:     []contracts.DeployPhase{},
+	}
+}
+
+// timePhase records in the report how long a phase of the deploy took since start
+func (cmd *DeployCmd) timePhase(name string, start time.Time) {
+	cmd.report.Phases = append(cmd.report.Phases, contracts.DeployPhase{
+		Name:       name,
+		DurationMs: time.Since(start).Milliseconds(),
+	})
+}
+
+// recordChange adds to the report a manifest resource created, updated or deleted by the deploy
+func (cmd *DeployCmd) recordChange(change contracts.ResourcePlan) {
+	cmd.report.Resources = append(cmd.report.Resources, change)
+}
+
+// fillReport copies the IDs of the deployed resources into the report once they are all known
+func (cmd *DeployCmd) fillReport(conf *contracts.AzionApplicationOptions) {
+	cmd.report.ApplicationID = conf.Application.ID
+	cmd.report.FunctionID = conf.Function.ID
+	cmd.report.InstanceID = conf.Function.InstanceID
+	cmd.report.DomainID = conf.Domain.Id
+	cmd.report.Bucket = conf.Bucket
+	cmd.report.URL = conf.Domain.Url
+	cmd.report.Prefix = conf.Prefix
+	cmd.report.Environment = Environment
+}
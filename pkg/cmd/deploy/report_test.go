@@ -0,0 +1,96 @@
+package deploy
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
+	"github.com/aziontech/azion-cli/pkg/output"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/aziontech/azion-cli/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestReport(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	t.Run("purged urls are recorded", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST("POST", "purge/url"), httpmock.StatusStringResponse(201, ""))
+		mock.Register(httpmock.REST("POST", "purge/wildcard"), httpmock.StatusStringResponse(201, ""))
+
+		f, _, _ := testutils.NewFactory(mock)
+		cmd := NewDeployCmd(f)
+
+		require.NoError(t, cmd.PurgeUrls([]string{"a.map.azionedge.net", "www.example.com"}, "/index.html"))
+		require.NoError(t, cmd.PurgeWildcard([]string{"a.map.azionedge.net"}, "/*"))
+		mock.Verify(t)
+		require.Equal(t, []string{
+			"a.map.azionedge.net/index.html",
+			"www.example.com/index.html",
+			"a.map.azionedge.net/*",
+		}, cmd.report.PurgedURLs)
+	})
+
+	t.Run("json output carries the report", func(t *testing.T) {
+		f, stdout, _ := testutils.NewFactory(nil)
+		f.Flags.Format = "json"
+		cmd := NewDeployCmd(f)
+
+		cmd.timePhase(phaseBuild, time.Now())
+		cmd.recordChange(contracts.ResourcePlan{Resource: manifestInt.ResourceRule, Name: "rule", Id: "6", Action: manifestInt.PlanCreate})
+		cmd.report.FilesUploaded = 3
+		cmd.fillReport(&contracts.AzionApplicationOptions{
+			Bucket:      "bucket",
+			Prefix:      "20240101000000",
+			Application: contracts.AzionJsonDataApplication{ID: 1},
+			Function:    contracts.AzionJsonDataFunction{ID: 3, InstanceID: 4},
+			Domain:      contracts.AzionJsonDataDomain{Id: 5, Url: "https://a.map.azionedge.net"},
+		})
+
+		deployOut := output.DeployOutput{
+			Messages:      []string{"Your project was deployed successfully"},
+			Report:        *cmd.report,
+			GeneralOutput: output.GeneralOutput{Out: f.IOStreams.Out, Flags: f.Flags},
+		}
+		require.NoError(t, output.Print(&deployOut))
+
+		result := struct {
+			Messages []string               `json:"messages"`
+			Report   contracts.DeployReport `json:"report"`
+		}{}
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+		require.Len(t, result.Messages, 1)
+		require.Equal(t, int64(1), result.Report.ApplicationID)
+		require.Equal(t, int64(4), result.Report.InstanceID)
+		require.Equal(t, int64(5), result.Report.DomainID)
+		require.Equal(t, "https://a.map.azionedge.net", result.Report.URL)
+		require.Equal(t, "20240101000000", result.Report.Prefix)
+		require.Equal(t, 3, result.Report.FilesUploaded)
+		require.Empty(t, result.Report.PurgedURLs)
+		require.Len(t, result.Report.Resources, 1)
+		require.Equal(t, phaseBuild, result.Report.Phases[0].Name)
+	})
+
+	t.Run("failed deploy prints its report", func(t *testing.T) {
+		f, stdout, _ := testutils.NewFactory(&httpmock.Registry{})
+		f.Flags.Format = "json"
+		cmd := NewDeployCmd(f)
+
+		// no token is configured, so the deploy fails before anything is created
+		err := cmd.Run(f)
+		require.ErrorIs(t, err, utils.ErrorTokenNotProvided)
+
+		result := struct {
+			Report contracts.DeployReport `json:"report"`
+		}{}
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+		require.Equal(t, reportFailed, result.Report.Status)
+		require.Equal(t, utils.ErrorTokenNotProvided.Error(), result.Report.Error)
+	})
+}
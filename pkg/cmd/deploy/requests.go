@@ -20,6 +20,7 @@ import (
 	"github.com/aziontech/azion-cli/pkg/cmdutil"
 	"github.com/aziontech/azion-cli/pkg/contracts"
 	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
 	"github.com/aziontech/azion-cli/pkg/token"
 	"github.com/aziontech/azion-cli/utils"
 )
@@ -56,6 +57,13 @@ func (cmd *DeployCmd) doFunction(clients *Clients, ctx context.Context, conf *co
 		} else {
 			conf.Function.ID = functionId
 		}
+		if err := cmd.journal.track(contracts.JournalEntry{
+			Resource: resourceFunction,
+			ID:       conf.Function.ID,
+			Name:     nameOrDefault(conf.Function.Name, conf.Name),
+		}); err != nil {
+			return err
+		}
 
 		err = cmd.WriteAzionJsonContent(conf, ProjectConf)
 		if err != nil {
@@ -73,7 +81,7 @@ func (cmd *DeployCmd) doFunction(clients *Clients, ctx context.Context, conf *co
 					if Auto {
 						projName = thoth.GenerateName()
 					} else {
-						projName, err = askForInput(msg.AskInputName, thoth.GenerateName())
+						projName, err = cmd.renameInUse(nameOrDefault(conf.Function.InstanceName, conf.Name), msg.NameInUseInstance, msgs)
 						if err != nil {
 							return err
 						}
@@ -84,6 +92,14 @@ func (cmd *DeployCmd) doFunction(clients *Clients, ctx context.Context, conf *co
 				return err
 			}
 			conf.Function.InstanceID = instance.GetId()
+			if err := cmd.journal.track(contracts.JournalEntry{
+				Resource:      resourceInstance,
+				ID:            instance.GetId(),
+				Name:          instance.GetName(),
+				ApplicationID: conf.Application.ID,
+			}); err != nil {
+				return err
+			}
 			break
 		}
 		err = cmd.WriteAzionJsonContent(conf, ProjectConf)
@@ -131,7 +147,7 @@ func (cmd *DeployCmd) doApplication(
 						logger.FInfoFlags(cmd.Io.Out, msgf, cmd.F.Format, cmd.F.Out)
 						*msgs = append(*msgs, msgf)
 					} else {
-						projName, err = askForInput(msg.AskInputName, thoth.GenerateName())
+						projName, err = cmd.renameInUse(conf.Name, msg.NameInUseApplication, msgs)
 						if err != nil {
 							return err
 						}
@@ -142,6 +158,13 @@ func (cmd *DeployCmd) doApplication(
 				return err
 			}
 			conf.Application.ID = applicationId
+			if err := cmd.journal.track(contracts.JournalEntry{
+				Resource: resourceApplication,
+				ID:       applicationId,
+				Name:     nameOrDefault(conf.Application.Name, conf.Name),
+			}); err != nil {
+				return err
+			}
 			break
 		}
 
@@ -160,15 +183,23 @@ func (cmd *DeployCmd) doApplication(
 	return nil
 }
 
-func (cmd *DeployCmd) doDomain(client *apidom.Client, ctx context.Context, conf *contracts.AzionApplicationOptions, msgs *[]string) error {
+// doDomain creates or updates the project's domain, with the CNAMEs and certificate the manifest declares for it
+// under the __DEFAULT__ name
+func (cmd *DeployCmd) doDomain(
+	client *apidom.Client,
+	ctx context.Context,
+	conf *contracts.AzionApplicationOptions,
+	manifest *contracts.Manifest,
+	msgs *[]string) error {
 	var domain apidom.DomainResponse
 	var err error
+	declared := manifestInt.DeclaredDomain(manifest, manifestInt.DefaultDomain)
 
 	newDomain := false
 	if conf.Domain.Id == 0 {
 		var projName string
 		for {
-			domain, err = cmd.createDomain(client, ctx, conf, msgs)
+			domain, err = cmd.createDomain(client, ctx, conf, declared, msgs)
 			if err != nil {
 				// if the name is already in use, we ask for another one
 				if strings.Contains(err.Error(), utils.ErrorNameInUse.Error()) {
@@ -184,7 +215,7 @@ func (cmd *DeployCmd) doDomain(client *apidom.Client, ctx context.Context, conf
 						*msgs = append(*msgs, msgf)
 						projName = thoth.GenerateName()
 					} else {
-						projName, err = askForInput(msg.AskInputName, thoth.GenerateName())
+						projName, err = cmd.renameInUse(nameOrDefault(conf.Domain.Name, conf.Name), msg.NameInUseDomain, msgs)
 						if err != nil {
 							return err
 						}
@@ -199,25 +230,32 @@ func (cmd *DeployCmd) doDomain(client *apidom.Client, ctx context.Context, conf
 			conf.Domain.DomainName = domain.GetDomainName()
 			conf.Domain.Url = utils.Concat("https://", domain.GetDomainName())
 			newDomain = true
+			if err := cmd.journal.track(contracts.JournalEntry{
+				Resource: resourceDomain,
+				ID:       domain.GetId(),
+				Name:     domain.GetName(),
+			}); err != nil {
+				return err
+			}
 			break
 		}
-
-		err = cmd.WriteAzionJsonContent(conf, ProjectConf)
-		if err != nil {
-			logger.Debug("Error while writing azion.json file", zap.Error(err))
-			return err
-		}
-
 	} else {
-		domain, err = cmd.updateDomain(client, ctx, conf, msgs)
+		domain, err = cmd.updateDomain(client, ctx, conf, declared, msgs)
 		if err != nil {
 			logger.Debug("Error while updating domain", zap.Error(err))
 			return err
 		}
 	}
 
+	conf.Domain.DigitalCertificateId = domain.GetDigitalCertificateId()
+	err = cmd.WriteAzionJsonContent(conf, ProjectConf)
+	if err != nil {
+		logger.Debug("Error while writing azion.json file", zap.Error(err))
+		return err
+	}
+
 	if conf.RtPurge.PurgeOnPublish && !newDomain {
-		err = PurgeForUpdatedFiles(cmd, domain, ProjectConf, msgs)
+		err = PurgeForUpdatedFiles(cmd, purgeHostnames(domain, conf), ProjectConf, msgs)
 		if err != nil {
 			logger.Debug("Error while purging domain", zap.Error(err))
 			return err
@@ -227,6 +265,20 @@ func (cmd *DeployCmd) doDomain(client *apidom.Client, ctx context.Context, conf
 	return nil
 }
 
+// renameInUse returns the name to retry with when the one of a resource is taken. Non-interactive mode appends
+// a timestamp to it instead of asking for another one.
+func (cmd *DeployCmd) renameInUse(name, inUse string, msgs *[]string) (string, error) {
+	if !cmd.F.NonInteractive {
+		return askForInput(msg.AskInputName, thoth.GenerateName())
+	}
+
+	newName := fmt.Sprintf("%s-%s", name, utils.Timestamp())
+	msgf := fmt.Sprintf(inUse, newName)
+	logger.FInfoFlags(cmd.Io.Out, msgf, cmd.F.Format, cmd.F.Out)
+	*msgs = append(*msgs, msgf)
+	return newName, nil
+}
+
 func (cmd *DeployCmd) doRulesDeploy(
 	ctx context.Context,
 	conf *contracts.AzionApplicationOptions,
@@ -237,7 +289,7 @@ func (cmd *DeployCmd) doRulesDeploy(
 	}
 	var cacheId int64
 	var authorize bool
-	if Auto || NoPrompt {
+	if Auto || NoPrompt || cmd.F.NonInteractive {
 		authorize = false
 	} else {
 		authorize = utils.Confirm(cmd.F.GlobalFlagAll, msg.AskCreateCacheSettings, false)
@@ -256,16 +308,39 @@ func (cmd *DeployCmd) doRulesDeploy(
 		logger.FInfoFlags(cmd.F.IOStreams.Out, msg.CacheSettingsSuccessful, cmd.F.Format, cmd.F.Out)
 		*msgs = append(*msgs, msg.CacheSettingsSuccessful)
 		cacheId = cache.GetId()
+		if err := cmd.journal.track(contracts.JournalEntry{
+			Resource:      manifestInt.ResourceCache,
+			ID:            cacheId,
+			Name:          cache.GetName(),
+			ApplicationID: conf.Application.ID,
+		}); err != nil {
+			return err
+		}
 	}
 
 	// creates gzip and cache rules
-	err := client.CreateRulesEngineNextApplication(ctx, conf.Application.ID, cacheId, conf.Preset, conf.Mode, authorize)
+	rule, err := client.CreateRulesEngineNextApplication(ctx, conf.Application.ID, cacheId, conf.Preset, conf.Mode, authorize)
 	if err != nil {
 		logger.Debug("Error while creating rules engine", zap.Error(err))
 		return err
 	}
+	if err := cmd.journal.track(contracts.JournalEntry{
+		Resource:      resourceDefaultRule,
+		ID:            rule.GetId(),
+		Name:          rule.GetName(),
+		ApplicationID: conf.Application.ID,
+		Phase:         rule.GetPhase(),
+	}); err != nil {
+		return err
+	}
 
 	conf.NotFirstRun = true
+	// saved right away, otherwise a deploy resumed after a failure would create the rules again
+	err = cmd.WriteAzionJsonContent(conf, ProjectConf)
+	if err != nil {
+		logger.Debug("Error while writing azion.json file", zap.Error(err))
+		return err
+	}
 	return nil
 }
 
@@ -280,6 +355,13 @@ func (cmd *DeployCmd) doOriginSingle(
 		return 0, nil
 	}
 
+	// already created by a deploy that failed before completing
+	for _, origin := range conf.Origin {
+		if origin.Name == utils.Concat(conf.Name, "_single") {
+			return origin.OriginId, nil
+		}
+	}
+
 	reqSingleOrigin := apiori.CreateRequest{}
 	addresses := prepareAddresses(DefaultOrigin[:])
 	reqSingleOrigin.SetAddresses(addresses)
@@ -300,6 +382,15 @@ func (cmd *DeployCmd) doOriginSingle(
 		Name:      origin.GetName(),
 	}
 	conf.Origin = append(conf.Origin, newOrigin)
+	if err := cmd.journal.track(contracts.JournalEntry{
+		Resource:      manifestInt.ResourceOrigin,
+		ID:            newOrigin.OriginId,
+		Key:           newOrigin.OriginKey,
+		Name:          newOrigin.Name,
+		ApplicationID: conf.Application.ID,
+	}); err != nil {
+		return 0, err
+	}
 
 	return newOrigin.OriginId, nil
 }
@@ -313,8 +404,7 @@ func (cmd *DeployCmd) createFunction(client *api.Client, ctx context.Context, co
 		return 0, fmt.Errorf("%s: %w", msg.ErrorCodeFlag, err)
 	}
 
-	prependText := fmt.Sprintf(injectIntoFunction, conf.Bucket, conf.Prefix)
-	newCode := append([]byte(prependText), code...)
+	newCode := InjectStorage(code, conf.Bucket, conf.Prefix)
 
 	reqCre.SetCode(string(newCode))
 
@@ -358,8 +448,7 @@ func (cmd *DeployCmd) updateFunction(client *api.Client, ctx context.Context, co
 		return 0, fmt.Errorf("%s: %w", msg.ErrorCodeFlag, err)
 	}
 
-	prependText := fmt.Sprintf(injectIntoFunction, conf.Bucket, conf.Prefix)
-	newCode := append([]byte(prependText), code...)
+	newCode := InjectStorage(code, conf.Bucket, conf.Prefix)
 
 	reqUpd.SetCode(string(newCode))
 
@@ -446,18 +535,17 @@ func (cmd *DeployCmd) updateApplication(client *apiapp.Client, ctx context.Conte
 	return nil
 }
 
-func (cmd *DeployCmd) createDomain(client *apidom.Client, ctx context.Context, conf *contracts.AzionApplicationOptions, msgs *[]string) (apidom.DomainResponse, error) {
-	reqDom := apidom.CreateRequest{}
-	if conf.Domain.Name == "__DEFAULT__" {
-		reqDom.SetName(conf.Name)
-	} else {
-		reqDom.SetName(conf.Domain.Name)
+func (cmd *DeployCmd) createDomain(
+	client *apidom.Client,
+	ctx context.Context,
+	conf *contracts.AzionApplicationOptions,
+	declared *contracts.Domain,
+	msgs *[]string) (apidom.DomainResponse, error) {
+	name := conf.Domain.Name
+	if name == "__DEFAULT__" {
+		name = conf.Name
 	}
-	reqDom.SetCnames([]string{})
-	reqDom.SetCnameAccessOnly(false)
-	reqDom.SetIsActive(true)
-	reqDom.SetEdgeApplicationId(conf.Application.ID)
-	domain, err := client.Create(ctx, &reqDom)
+	domain, err := client.Create(ctx, manifestInt.NewDomainCreateRequest(name, declared, conf.Application.ID))
 	if err != nil {
 		return nil, fmt.Errorf(msg.ErrorCreateDomain.Error(), err)
 	}
@@ -467,16 +555,18 @@ func (cmd *DeployCmd) createDomain(client *apidom.Client, ctx context.Context, c
 	return domain, nil
 }
 
-func (cmd *DeployCmd) updateDomain(client *apidom.Client, ctx context.Context, conf *contracts.AzionApplicationOptions, msgs *[]string) (apidom.DomainResponse, error) {
-	reqDom := apidom.UpdateRequest{}
-	if conf.Domain.Name == "__DEFAULT__" {
-		reqDom.SetName(conf.Name)
-	} else {
-		reqDom.SetName(conf.Domain.Name)
-	}
-	reqDom.SetEdgeApplicationId(conf.Application.ID)
-	reqDom.Id = conf.Domain.Id
-	domain, err := client.Update(ctx, &reqDom)
+func (cmd *DeployCmd) updateDomain(
+	client *apidom.Client,
+	ctx context.Context,
+	conf *contracts.AzionApplicationOptions,
+	declared *contracts.Domain,
+	msgs *[]string) (apidom.DomainResponse, error) {
+	name := conf.Domain.Name
+	if name == "__DEFAULT__" {
+		name = conf.Name
+	}
+	request := manifestInt.NewDomainUpdateRequest(conf.Domain.Id, name, declared, conf.Application.ID, conf.Domain.DigitalCertificateId)
+	domain, err := client.Update(ctx, request)
 	if err != nil {
 		return nil, fmt.Errorf(msg.ErrorUpdateDomain.Error(), err)
 	}
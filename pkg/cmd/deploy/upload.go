@@ -1,8 +1,13 @@
 package deploy
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"sync/atomic"
+	"syscall"
 
 	msg "github.com/aziontech/azion-cli/messages/deploy"
 	"github.com/aziontech/azion-cli/pkg/api/storage"
@@ -16,22 +21,67 @@ import (
 
 var (
 	PathStatic = ".edge/storage"
-	Jobs       chan contracts.FileOps
-	Retries    int64
 )
 
 func (cmd *DeployCmd) uploadFiles(
 	f *cmdutil.Factory, conf *contracts.AzionApplicationOptions, msgs *[]string) error {
-	// Get total amount of files to display progress
-	totalFiles := 0
+	clientUpload := storage.NewClient(cmd.F.HttpClient, cmd.F.Config.GetString("storage_url"), cmd.F.Config.GetString("token"))
+
+	files, err := ReadFilesEdgeStorage()
+	if err != nil {
+		return err
+	}
+
+	plan, err := cmd.planUpload(context.Background(), clientUpload, conf, files)
+	if err != nil {
+		logger.Debug("Error while checking the objects of the previous upload", zap.Error(err))
+		return err
+	}
+	cmd.staticFiles = files
+
+	if plan.Reuse != "" {
+		conf.Prefix = plan.Reuse
+		cmd.report.FilesSkipped = len(files)
+		msgf := fmt.Sprintf(msg.UploadSkipped, plan.Reuse)
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, f.Format, f.Out)
+		*msgs = append(*msgs, msgf)
+		return nil
+	}
+
+	if len(plan.Unchanged) > 0 {
+		msgf := fmt.Sprintf(msg.UploadChangedOnly, len(files), plan.Changed, plan.From)
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, f.Format, f.Out)
+		*msgs = append(*msgs, msgf)
+	}
+
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msg.UploadStart, f.Format, f.Out)
+	*msgs = append(*msgs, msg.UploadStart)
+
+	if Concurrency < 1 {
+		return msg.ErrorConcurrency
+	}
+
+	uploadJobs := []uploadJob{}
 	if err := cmd.FilepathWalk(PathStatic, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			logger.Debug("Error while reading files to be uploaded", zap.Error(err))
-			logger.Debug("File that caused the error: " + PathStatic)
+			logger.Debug("File that caused the error: " + path)
 			return err
 		}
+
 		if !info.IsDir() {
-			totalFiles++
+			fileString := strings.TrimPrefix(path, PathStatic)
+			mimeType, err := mimemagic.MatchFilePath(path, -1)
+			if err != nil {
+				logger.Debug("Error while matching file path", zap.Error(err))
+				return err
+			}
+			uploadJobs = append(uploadJobs, uploadJob{
+				Path: path,
+				FileOps: contracts.FileOps{
+					Path:     fileString,
+					MimeType: mimeType.MediaType(),
+				},
+			})
 		}
 		return nil
 	}); err != nil {
@@ -39,23 +89,26 @@ func (cmd *DeployCmd) uploadFiles(
 		return err
 	}
 
-	clientUpload := storage.NewClient(cmd.F.HttpClient, cmd.F.Config.GetString("storage_url"), cmd.F.Config.GetString("token"))
+	// Ctrl-C stops the upload; files already sent stay in the bucket under this deploy's prefix
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	logger.FInfoFlags(cmd.F.IOStreams.Out, msg.UploadStart, f.Format, f.Out)
-	*msgs = append(*msgs, msg.UploadStart)
-
-	noOfWorkers := 5
 	var currentFile int64
-	Jobs := make(chan contracts.FileOps, totalFiles)
-	results := make(chan error, noOfWorkers)
+	jobs := make(chan uploadJob, len(uploadJobs))
+	results := make(chan uploadResult, Concurrency)
+
+	for _, job := range uploadJobs {
+		jobs <- job
+	}
+	close(jobs)
 
 	// Create worker goroutines
-	for i := 1; i <= noOfWorkers; i++ {
-		go worker(Jobs, results, &currentFile, clientUpload, conf)
+	for i := 1; i <= Concurrency; i++ {
+		go worker(ctx, jobs, results, &currentFile, clientUpload, conf, cmd.Open)
 	}
 
 	bar := progressbar.NewOptions(
-		totalFiles,
+		len(uploadJobs),
 		progressbar.OptionSetDescription("Uploading files"),
 		progressbar.OptionShowCount(),
 		progressbar.OptionSetWriter(cmd.F.IOStreams.Out),
@@ -66,58 +119,42 @@ func (cmd *DeployCmd) uploadFiles(
 		bar = nil
 	}
 
-	if err := cmd.FilepathWalk(PathStatic, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	failed := []uploadResult{}
+	skipped := 0
+	for range uploadJobs {
+		result := <-results
+		if result.Err != nil {
+			failed = append(failed, result)
+		} else if result.Skipped {
+			skipped++
 		}
 
-		if !info.IsDir() {
-			fileContent, err := cmd.Open(path)
-			if err != nil {
-				logger.Debug("Error while trying to read file <"+path+"> about to be uploaded", zap.Error(err))
-				return err
-			}
-
-			fileString := strings.TrimPrefix(path, PathStatic)
-			mimeType, err := mimemagic.MatchFilePath(path, -1)
-			if err != nil {
-				logger.Debug("Error while matching file path", zap.Error(err))
-				return err
-			}
-			fileOptions := contracts.FileOps{
-				Path:        fileString,
-				MimeType:    mimeType.MediaType(),
-				FileContent: fileContent,
+		if bar != nil {
+			if err := bar.Set(int(atomic.LoadInt64(&currentFile))); err != nil {
+				logger.Debug("Error while updating the progress bar", zap.Error(err))
 			}
-
-			Jobs <- fileOptions
 		}
-		return nil
-	}); err != nil {
-		logger.Debug("Error while reading files to be uploaded", zap.Error(err))
-		return err
 	}
-	close(Jobs)
 
-	// Check for errors from workers
-	for a := 1; a <= totalFiles; a++ {
-		result := <-results
-		if result != nil {
-			return result
-		}
+	if ctx.Err() != nil {
+		return msg.ErrorUploadCanceled
+	}
 
-		if bar != nil {
-			err := bar.Set(int(currentFile))
-			if err != nil {
-				return err
-			}
+	if len(failed) > 0 {
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msg.UploadFailedReport, f.Format, f.Out)
+		*msgs = append(*msgs, msg.UploadFailedReport)
+		for _, result := range failed {
+			msgf := fmt.Sprintf(msg.UploadFailedFile, result.Path, result.Err)
+			logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, f.Format, f.Out)
+			*msgs = append(*msgs, msgf)
 		}
+		return fmt.Errorf(msg.ErrorUploadFailed.Error(), len(failed), len(uploadJobs))
 	}
 
-	// All jobs are processed, no more values will be sent on results:
-	close(results)
 	logger.FInfoFlags(cmd.F.IOStreams.Out, msg.UploadSuccessful, f.Format, f.Out)
 	*msgs = append(*msgs, msg.UploadSuccessful)
+	cmd.report.FilesUploaded = len(uploadJobs) - skipped
+	cmd.report.FilesSkipped = skipped
 
 	return nil
 }
@@ -0,0 +1,139 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	msg "github.com/aziontech/azion-cli/messages/deploy"
+	msgstorage "github.com/aziontech/azion-cli/messages/storage"
+	"github.com/aziontech/azion-cli/pkg/cmd/storage/prune"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const resourcePrefix = "storage_prefix"
+
+// oldPrefixes returns the deploy prefixes of the bucket beyond the newest KeepVersions. current is the prefix the deploy
+// serves its files from; it is always kept and counted, even when a dry-run has not uploaded anything to it yet.
+func oldPrefixes(ctx context.Context, clients *Clients, bucket, current string) ([]prune.Prefix, error) {
+	prefixes, err := prune.ListPrefixes(ctx, clients.Storage, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := false
+	for _, prefix := range prefixes {
+		if prefix.Name == current {
+			stored = true
+		}
+	}
+	if !stored {
+		prefixes = append([]prune.Prefix{{Name: current}}, prefixes...)
+	}
+
+	opts := prune.Options{
+		Keep:      KeepVersions,
+		Protected: map[string]bool{current: true},
+		Now:       time.Now(),
+	}
+
+	old := []prune.Prefix{}
+	for i, action := range prune.Decide(prefixes, opts) {
+		if action == msgstorage.ActionDelete {
+			old = append(old, prefixes[i])
+		}
+	}
+	return old, nil
+}
+
+// pruneVersions deletes the static files of the deploys beyond --keep-versions. The deploy itself already
+// succeeded, so a failure is reported without failing the command.
+func (cmd *DeployCmd) pruneVersions(ctx context.Context, clients *Clients, conf *contracts.AzionApplicationOptions, msgs *[]string) {
+	if KeepVersions <= 0 || conf.Bucket == "" {
+		return
+	}
+
+	old, err := oldPrefixes(ctx, clients, conf.Bucket, conf.Prefix)
+	if err == nil {
+		pruned := make(map[string]bool)
+		for _, prefix := range old {
+			if err = prune.Delete(ctx, clients.Storage, conf.Bucket, prefix); err != nil {
+				break
+			}
+			pruned[prefix.Name] = true
+			msgf := fmt.Sprintf(msg.DeployOutputPrunedVersion, prefix.Name, len(prefix.Keys))
+			logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+			*msgs = append(*msgs, msgf)
+		}
+		// the prefixes deleted before a failure are marked as well
+		if errMark := cmd.markPruned(conf.Bucket, pruned); errMark != nil && err == nil {
+			err = errMark
+		}
+	}
+
+	if err != nil {
+		logger.Debug("Error while pruning old versions", zap.Error(err))
+		msgf := fmt.Sprintf(msg.DeployOutputPruneFailed, err)
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+		*msgs = append(*msgs, msgf)
+	}
+}
+
+// markPruned flags the deploys whose static files were deleted in deployments.json, so they are no longer
+// rolled back to or reused, and deletes their snapshots, which nothing can restore anymore
+func (cmd *DeployCmd) markPruned(bucket string, prefixes map[string]bool) error {
+	if len(prefixes) == 0 {
+		return nil
+	}
+
+	history, err := cmd.GetDeploymentHistory(ProjectConf)
+	if err != nil {
+		return err
+	}
+	snapshots := []string{}
+	for i, record := range history.Deployments {
+		if record.Bucket == bucket && prefixes[record.StoragePrefix()] {
+			history.Deployments[i].Pruned = true
+			snapshots = append(snapshots, SnapshotDir(ProjectConf, record.VersionID))
+		}
+	}
+
+	if err := cmd.WriteDeploymentHistory(history, ProjectConf); err != nil {
+		logger.Debug("Error while writing deployments.json file", zap.Error(err))
+		return err
+	}
+
+	// the snapshots are deleted once the records no longer point to them; one left behind only takes disk space
+	for _, dir := range snapshots {
+		if err := os.RemoveAll(dir); err != nil {
+			logger.Debug("Error while deleting deployment snapshot", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// planPrune adds to the dry-run plan the prefixes --keep-versions would delete
+func planPrune(ctx context.Context, clients *Clients, bucket, current string) ([]contracts.ResourcePlan, error) {
+	if KeepVersions <= 0 || bucket == "" {
+		return nil, nil
+	}
+
+	old, err := oldPrefixes(ctx, clients, bucket, current)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := []contracts.ResourcePlan{}
+	for _, prefix := range old {
+		plan = append(plan, contracts.ResourcePlan{
+			Resource: resourcePrefix,
+			Name:     bucket + "/" + prefix.Name,
+			Action:   msgstorage.ActionDelete,
+			Details:  fmt.Sprintf(msg.PlanFilesCount, len(prefix.Keys)),
+		})
+	}
+	return plan, nil
+}
@@ -0,0 +1,121 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+var prefixesResponse = `
+{
+	"continuation_token": null,
+	"results": [
+		{"key": "20240101000000/index.html", "last_modified": "2024-01-01T00:00:00Z", "size": 120},
+		{"key": "20240201000000/index.html", "last_modified": "2024-02-01T00:00:00Z", "size": 120},
+		{"key": "20240301000000/index.html", "last_modified": "2024-03-01T00:00:00Z", "size": 120}
+	]
+}
+`
+
+func Test_oldPrefixes(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		name    string
+		current string
+		want    []string
+	}{
+		{
+			name:    "new prefix counts towards the versions kept",
+			current: "20240401000000",
+			want:    []string{"20240201000000", "20240101000000"},
+		},
+		{
+			name:    "reused prefix",
+			current: "20240301000000",
+			want:    []string{"20240101000000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			KeepVersions = 2
+			defer func() { KeepVersions = 0 }()
+
+			mock := &httpmock.Registry{}
+			mock.Register(httpmock.REST("GET", "v4/storage/buckets/bucket/objects"), httpmock.JSONFromString(prefixesResponse))
+			f, _, _ := testutils.NewFactory(mock)
+
+			old, err := oldPrefixes(context.Background(), NewClients(f), "bucket", tt.current)
+			require.NoError(t, err)
+
+			names := []string{}
+			for _, prefix := range old {
+				names = append(names, prefix.Name)
+			}
+			require.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func Test_pruneVersions(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	KeepVersions = 2
+	defer func() { KeepVersions = 0 }()
+
+	mock := &httpmock.Registry{}
+	mock.Register(httpmock.REST("GET", "v4/storage/buckets/bucket/objects"), httpmock.JSONFromString(prefixesResponse))
+	mock.Register(httpmock.REST("DELETE", "v4/storage/buckets/bucket/objects/20240101000000/index.html"),
+		httpmock.JSONFromString(`{"state": "executed"}`))
+	f, _, _ := testutils.NewFactory(mock)
+
+	ProjectConf = t.TempDir()
+	defer func() { ProjectConf = "azion" }()
+	versions := []string{"20240101000000", "20240115000000", "20240201000000", "20240301000000"}
+	for _, version := range versions {
+		require.NoError(t, os.MkdirAll(SnapshotDir(ProjectConf, version), os.ModePerm))
+	}
+
+	var written *contracts.DeploymentHistory
+	cmd := NewDeployCmd(f)
+	cmd.GetDeploymentHistory = func(confPath string) (*contracts.DeploymentHistory, error) {
+		return &contracts.DeploymentHistory{Deployments: []contracts.DeploymentRecord{
+			{VersionID: "20240101000000", Bucket: "bucket"},
+			{VersionID: "20240115000000", Bucket: "bucket", Prefix: "20240101000000"},
+			{VersionID: "20240201000000", Bucket: "bucket"},
+			{VersionID: "20240301000000", Bucket: "bucket"},
+		}}, nil
+	}
+	cmd.WriteDeploymentHistory = func(history *contracts.DeploymentHistory, confPath string) error {
+		written = history
+		return nil
+	}
+
+	msgs := []string{}
+	conf := &contracts.AzionApplicationOptions{Bucket: "bucket", Prefix: "20240301000000"}
+	cmd.pruneVersions(context.Background(), NewClients(f), conf, &msgs)
+	mock.Verify(t)
+
+	require.NotNil(t, written)
+	pruned := []bool{}
+	for _, record := range written.Deployments {
+		pruned = append(pruned, record.Pruned)
+	}
+	require.Equal(t, []bool{true, true, false, false}, pruned)
+
+	// the snapshots of the pruned deploys are deleted
+	kept := []bool{}
+	for _, version := range versions {
+		_, err := os.Stat(SnapshotDir(ProjectConf, version))
+		kept = append(kept, err == nil)
+	}
+	require.Equal(t, []bool{false, false, true, true}, kept)
+}
@@ -2,59 +2,135 @@ package deploy
 
 import (
 	"context"
+	"errors"
+	"math/rand"
+	"os"
 	"sync/atomic"
+	"time"
 
 	"github.com/aziontech/azion-cli/pkg/api/storage"
 	"github.com/aziontech/azion-cli/pkg/contracts"
 	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/utils"
 	"go.uber.org/zap"
 )
 
-// worker reads the range of jobs and uploads the file, if there is an error during upload, we returning it through the results channel
-func worker(jobs <-chan contracts.FileOps, results chan<- error, currentFile *int64, clientUpload *storage.Client, conf *contracts.AzionApplicationOptions) {
+const (
+	maxUploadRetries = 5
+	backoffBase      = 500 * time.Millisecond
+	backoffMax       = 10 * time.Second
+)
+
+// retryBackoff is how long to wait before the given retry; tests replace it to avoid sleeping
+var retryBackoff = backoff
+
+// uploadJob is a file found under PathStatic; it is only opened by the worker that uploads it
+type uploadJob struct {
+	Path    string
+	FileOps contracts.FileOps
+}
+
+type uploadResult struct {
+	Path    string
+	Skipped bool
+	Err     error
+}
+
+// worker uploads the files received from jobs and reports one result per file, so a failed file never stops the others
+func worker(
+	ctx context.Context,
+	jobs <-chan uploadJob,
+	results chan<- uploadResult,
+	currentFile *int64,
+	clientUpload *storage.Client,
+	conf *contracts.AzionApplicationOptions,
+	open func(name string) (*os.File, error)) {
 	for job := range jobs {
-		// Once ENG-27343 is completed, we might be able to remove this piece of code
-		fileInfo, err := job.FileContent.Stat()
-		if err != nil {
-			logger.Debug("Error while worker tried to read file stats", zap.Error(err))
-			results <- err
-			return
+		skipped, err := uploadFile(ctx, job, clientUpload, conf, open)
+		atomic.AddInt64(currentFile, 1)
+		results <- uploadResult{Path: job.FileOps.Path, Skipped: skipped, Err: err}
+	}
+}
+
+// uploadFile sends a file to the bucket and tells whether it was skipped for being empty
+func uploadFile(
+	ctx context.Context,
+	job uploadJob,
+	clientUpload *storage.Client,
+	conf *contracts.AzionApplicationOptions,
+	open func(name string) (*os.File, error)) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	fileContent, err := open(job.Path)
+	if err != nil {
+		logger.Debug("Error while trying to read file <"+job.Path+"> about to be uploaded", zap.Error(err))
+		return false, err
+	}
+	defer fileContent.Close()
+
+	// Once ENG-27343 is completed, we might be able to remove this piece of code
+	fileInfo, err := fileContent.Stat()
+	if err != nil {
+		logger.Debug("Error while worker tried to read file stats", zap.Error(err))
+		return false, err
+	}
+
+	if fileInfo.Size() == 0 {
+		logger.Debug("Skipping upload of empty file: " + job.Path)
+		return true, nil
+	}
+
+	fileOps := job.FileOps
+	fileOps.FileContent = fileContent
+
+	err = withRetries(ctx, job.Path, func() error {
+		if _, err := fileContent.Seek(0, 0); err != nil {
+			logger.Debug("An error occurred while seeking fileContent", zap.Error(err))
+			return err
+		}
+		return clientUpload.Upload(ctx, &fileOps, conf)
+	})
+	return false, err
+}
+
+// withRetries runs attempt until it succeeds, the retry budget is exhausted or the upload is canceled
+func withRetries(ctx context.Context, path string, attempt func() error) error {
+	for retry := 0; ; retry++ {
+		err := attempt()
+		if err == nil {
+			return nil
 		}
+		logger.Debug("Error while worker tried to upload file: <"+path+"> to storage api", zap.Int("attempt", retry+1), zap.Error(err))
 
-		// Check if the file size is zero
-		if fileInfo.Size() == 0 {
-			logger.Debug("\nSkipping upload of empty file: " + job.Path)
-			results <- nil
-			atomic.AddInt64(currentFile, 1)
-			return
+		if retry >= maxUploadRetries || ctx.Err() != nil || !retryable(err) {
+			return err
 		}
 
-		if err := clientUpload.Upload(context.Background(), &job, conf); err != nil {
-			logger.Debug("Error while worker tried to upload file: <"+job.Path+"> to storage api", zap.Error(err))
-			for Retries < 5 {
-				atomic.AddInt64(&Retries, 1)
-				_, err := job.FileContent.Seek(0, 0)
-				if err != nil {
-					logger.Debug("An error occurred while seeking fileContent", zap.Error(err))
-					break
-				}
-
-				logger.Debug("Retrying to upload the following file: <"+job.Path+"> to storage api", zap.Error(err))
-				err = clientUpload.Upload(context.Background(), &job, conf)
-				if err != nil {
-					continue
-				}
-				break
-			}
-
-			if Retries >= 5 {
-				logger.Debug("There have been 5 retries already, quitting upload")
-				results <- err
-				return
-			}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryBackoff(retry)):
 		}
+	}
+}
 
-		atomic.AddInt64(currentFile, 1)
-		results <- nil
+// retryable tells whether a failed request may succeed when sent again: server errors, rate limiting and network
+// failures, which ErrorPerStatusCode reports as server errors or timeouts. Other client errors never succeed.
+func retryable(err error) bool {
+	return errors.Is(err, utils.ErrorInternalServerError) ||
+		errors.Is(err, utils.ErrorTimeoutAPICall) ||
+		errors.Is(err, utils.ErrorTooManyRequests429)
+}
+
+// backoff grows exponentially with the attempt and keeps a random half of it, so workers retrying
+// at the same time do not hit the storage API together
+func backoff(attempt int) time.Duration {
+	wait := backoffBase << attempt
+	if wait > backoffMax || wait <= 0 {
+		wait = backoffMax
 	}
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
 }
@@ -0,0 +1,160 @@
+package deploy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aziontech/azion-cli/pkg/api/storage"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+// storageFailing answers the first failures uploads with the given error status and the following ones with success
+func storageFailing(failures int64, failStatus int, calls *int64) *storage.Client {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		status := http.StatusOK
+		body := `{"state": "executed", "data": {"object_key": "key"}}`
+		if atomic.AddInt64(calls, 1) <= failures {
+			status = failStatus
+			body = `{"detail": "error"}`
+		}
+		return &http.Response{
+			StatusCode: status,
+			Request:    req,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	})
+	return storage.NewClient(&http.Client{Transport: transport}, "", "token")
+}
+
+func Test_worker(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+	retryBackoff = func(attempt int) time.Duration { return 0 }
+	defer func() { retryBackoff = backoff }()
+
+	dir := t.TempDir()
+	full := filepath.Join(dir, "index.html")
+	empty := filepath.Join(dir, "empty.txt")
+	require.NoError(t, os.WriteFile(full, []byte("<html></html>"), 0644))
+	require.NoError(t, os.WriteFile(empty, []byte{}, 0644))
+
+	conf := &contracts.AzionApplicationOptions{Bucket: "bucket", Prefix: "20240101000000"}
+
+	tests := []struct {
+		name          string
+		files         []string
+		failures      int64
+		failStatus    int
+		cancel        bool
+		expectedCalls int64
+		expectedErrs  int
+		expectedSkips int
+	}{
+		{
+			name:          "transient failures are retried",
+			files:         []string{full},
+			failures:      2,
+			expectedCalls: 3,
+		},
+		{
+			name:          "rate limited uploads are retried",
+			files:         []string{full},
+			failures:      1,
+			failStatus:    http.StatusTooManyRequests,
+			expectedCalls: 2,
+		},
+		{
+			name:          "client errors are not retried",
+			files:         []string{full},
+			failures:      100,
+			failStatus:    http.StatusForbidden,
+			expectedCalls: 1,
+			expectedErrs:  1,
+		},
+		{
+			name:          "retry budget exhausted",
+			files:         []string{full},
+			failures:      100,
+			expectedCalls: maxUploadRetries + 1,
+			expectedErrs:  1,
+		},
+		{
+			name:          "empty file does not stop the worker",
+			files:         []string{empty, full},
+			expectedCalls: 1,
+			expectedSkips: 1,
+		},
+		{
+			name:         "canceled upload",
+			files:        []string{full},
+			cancel:       true,
+			expectedErrs: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int64
+			failStatus := tt.failStatus
+			if failStatus == 0 {
+				failStatus = http.StatusInternalServerError
+			}
+			client := storageFailing(tt.failures, failStatus, &calls)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+
+			jobs := make(chan uploadJob, len(tt.files))
+			results := make(chan uploadResult, len(tt.files))
+			for _, file := range tt.files {
+				jobs <- uploadJob{Path: file, FileOps: contracts.FileOps{Path: "/" + filepath.Base(file), MimeType: "text/html"}}
+			}
+			close(jobs)
+
+			var currentFile int64
+			worker(ctx, jobs, results, &currentFile, client, conf, os.Open)
+
+			errs, skips := 0, 0
+			for range tt.files {
+				result := <-results
+				if result.Err != nil {
+					errs++
+				}
+				if result.Skipped {
+					skips++
+				}
+			}
+			require.Equal(t, tt.expectedErrs, errs)
+			require.Equal(t, tt.expectedSkips, skips)
+			require.Equal(t, tt.expectedCalls, atomic.LoadInt64(&calls))
+			require.Equal(t, int64(len(tt.files)), currentFile)
+		})
+	}
+}
+
+func Test_backoff(t *testing.T) {
+	for attempt := 0; attempt < 40; attempt++ {
+		wait := backoff(attempt)
+		require.Greater(t, wait, time.Duration(0))
+		require.LessOrEqual(t, wait, backoffMax)
+	}
+}
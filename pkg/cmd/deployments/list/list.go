@@ -0,0 +1,121 @@
+package list
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	msg "github.com/aziontech/azion-cli/messages/deployments"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/output"
+	"github.com/aziontech/azion-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+type ListCmd struct {
+	F                    *cmdutil.Factory
+	GetAzionJsonContent  func(confPath string) (*contracts.AzionApplicationOptions, error)
+	GetDeploymentHistory func(confPath string) (*contracts.DeploymentHistory, error)
+	EnvironmentConfPath  func(confPath, environment string) (string, error)
+}
+
+var (
+	ProjectConf string
+	Environment string
+)
+
+func NewListCmd(f *cmdutil.Factory) *ListCmd {
+	return &ListCmd{
+		F:                    f,
+		GetAzionJsonContent:  utils.GetAzionJsonContent,
+		GetDeploymentHistory: utils.GetDeploymentHistory,
+		EnvironmentConfPath:  utils.EnvironmentConfPath,
+	}
+}
+
+func NewCobraCmd(list *ListCmd) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:           msg.ListUsage,
+		Short:         msg.ListShortDescription,
+		Long:          msg.ListLongDescription,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Example: heredoc.Doc(`
+		$ azion deployments list
+		$ azion deployments list --format json
+		$ azion deployments list --environment staging
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return list.Run()
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&ProjectConf, "config-dir", "azion", msg.FlagConfigDir)
+	cobraCmd.Flags().StringVar(&Environment, "environment", "", msg.FlagEnvironment)
+	cobraCmd.Flags().BoolP("help", "h", false, msg.ListFlagHelp)
+	return cobraCmd
+}
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	return NewCobraCmd(NewListCmd(f))
+}
+
+func (cmd *ListCmd) Run() error {
+	confPath, err := cmd.EnvironmentConfPath(ProjectConf, Environment)
+	if err != nil {
+		return err
+	}
+
+	conf, err := cmd.GetAzionJsonContent(confPath)
+	if err != nil {
+		logger.Debug("Failed to get Azion JSON content", zap.Error(err))
+		return err
+	}
+
+	history, err := cmd.GetDeploymentHistory(confPath)
+	if err != nil {
+		return err
+	}
+
+	listOut := output.ListOutput{}
+	listOut.Columns = []string{"VERSION", "TIMESTAMP", "GIT COMMIT", "FUNCTION HASH", "ACTIVE", "PRUNED"}
+	listOut.Out = cmd.F.IOStreams.Out
+	listOut.Flags = cmd.F.Flags
+
+	// most recent deploys first; several deploys may share a prefix, the latest of them is the active one
+	marked := false
+	for i := len(history.Deployments) - 1; i >= 0; i-- {
+		record := history.Deployments[i]
+		active := ""
+		if !marked && record.StoragePrefix() == conf.Prefix {
+			active = "*"
+			marked = true
+		}
+		ln := []string{
+			record.VersionID,
+			record.Timestamp,
+			shortHash(record.GitCommit),
+			shortHash(record.FunctionHash),
+			active,
+			pruned(record),
+		}
+		listOut.Lines = append(listOut.Lines, ln)
+	}
+
+	return output.Print(&listOut)
+}
+
+// pruned marks the deploys whose static files were deleted, which can't be rolled back to
+func pruned(record contracts.DeploymentRecord) string {
+	if record.Pruned {
+		return "*"
+	}
+	return ""
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
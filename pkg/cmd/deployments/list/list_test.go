@@ -0,0 +1,62 @@
+package list
+
+import (
+	"testing"
+
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestList(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	t.Run("list recorded deploys", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+		f, stdout, _ := testutils.NewFactory(mock)
+
+		listCmd := NewListCmd(f)
+		listCmd.GetAzionJsonContent = func(confPath string) (*contracts.AzionApplicationOptions, error) {
+			return &contracts.AzionApplicationOptions{Prefix: "20240202000000"}, nil
+		}
+		listCmd.GetDeploymentHistory = func(confPath string) (*contracts.DeploymentHistory, error) {
+			return &contracts.DeploymentHistory{
+				Deployments: []contracts.DeploymentRecord{
+					{VersionID: "20240101000000", GitCommit: "3f1c2b7a9e0d4c6b8a2f"},
+					{VersionID: "20240202000000", FunctionHash: "9b74c9897bac770ffc029102a200c5de"},
+				},
+			}, nil
+		}
+
+		cmd := NewCobraCmd(listCmd)
+		cmd.SetArgs([]string{})
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "20240101000000")
+		require.Contains(t, stdout.String(), "3f1c2b7a9e0d")
+		require.NotContains(t, stdout.String(), "3f1c2b7a9e0d4")
+	})
+
+	t.Run("no deploys recorded", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+		f, _, _ := testutils.NewFactory(mock)
+
+		listCmd := NewListCmd(f)
+		listCmd.GetAzionJsonContent = func(confPath string) (*contracts.AzionApplicationOptions, error) {
+			return &contracts.AzionApplicationOptions{}, nil
+		}
+		listCmd.GetDeploymentHistory = func(confPath string) (*contracts.DeploymentHistory, error) {
+			return &contracts.DeploymentHistory{}, nil
+		}
+
+		cmd := NewCobraCmd(listCmd)
+		cmd.SetArgs([]string{})
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+	})
+}
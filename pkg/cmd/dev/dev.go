@@ -24,14 +24,19 @@ type DevCmd struct {
 	CommandRunnerStream   func(out io.Writer, cmd string, envvars []string) error
 	CommandRunInteractive func(f *cmdutil.Factory, comm string) error
 	BuildCmd              func(f *cmdutil.Factory) *build.BuildCmd
-	F                     *cmdutil.Factory
+	GetAzionJsonContent   func(confPath string) (*contracts.AzionApplicationOptions, error)
+	// ConfigDir is where the azion.json of the project is stored
+	ConfigDir string
+	F         *cmdutil.Factory
 }
 
 func NewDevCmd(f *cmdutil.Factory) *DevCmd {
 	return &DevCmd{
-		F:        f,
-		Io:       f.IOStreams,
-		BuildCmd: build.NewBuildCmd,
+		F:                   f,
+		Io:                  f.IOStreams,
+		BuildCmd:            build.NewBuildCmd,
+		GetAzionJsonContent: utils.GetAzionJsonContent,
+		ConfigDir:           "azion",
 		CommandRunInteractive: func(f *cmdutil.Factory, comm string) error {
 			return utils.CommandRunInteractive(f, comm)
 		},
@@ -55,6 +60,7 @@ func NewCobraCmd(dev *DevCmd) *cobra.Command {
 	}
 	devCmd.Flags().BoolP("help", "h", false, msg.DevFlagHelp)
 	devCmd.Flags().BoolVar(&isFirewall, "firewall", false, msg.IsFirewall)
+	devCmd.Flags().StringVar(&dev.ConfigDir, "config-dir", "azion", msg.FlagConfigDir)
 	return devCmd
 }
 
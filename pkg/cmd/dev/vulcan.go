@@ -10,6 +10,14 @@ import (
 )
 
 func vulcan(cmd *DevCmd, isFirewall bool) error {
+	// the dev server runs the edge-functions version the project pins, if any
+	if conf, err := cmd.GetAzionJsonContent(cmd.ConfigDir); err != nil {
+		logger.Debug("Error while reading azion.json file, running the dev server unpinned", zap.Error(err))
+	} else if conf.VulcanVersion != "" {
+		if err := vulcanPkg.Pin(conf.VulcanVersion, cmd.F); err != nil {
+			return err
+		}
+	}
 
 	vul := vulcanPkg.NewVulcan()
 	command := vul.Command("", "dev", cmd.F)
@@ -0,0 +1,147 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc"
+	msg "github.com/aziontech/azion-cli/messages/diff"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
+	"github.com/aziontech/azion-cli/pkg/output"
+	"github.com/aziontech/azion-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+type DiffCmd struct {
+	F                   *cmdutil.Factory
+	GetAzionJsonContent func(confPath string) (*contracts.AzionApplicationOptions, error)
+	EnvironmentConfPath func(confPath, environment string) (string, error)
+	Interpreter         func() *manifestInt.ManifestInterpreter
+	ReadRemote          func(ctx context.Context, f *cmdutil.Factory, applicationID int64) (*manifestInt.Remote, error)
+}
+
+var (
+	ProjectConf string
+	Environment string
+	ExitCode    bool
+	Untracked   bool
+)
+
+func NewDiffCmd(f *cmdutil.Factory) *DiffCmd {
+	return &DiffCmd{
+		F:                   f,
+		GetAzionJsonContent: utils.GetAzionJsonContent,
+		EnvironmentConfPath: utils.EnvironmentConfPath,
+		Interpreter:         manifestInt.NewManifestInterpreter,
+		ReadRemote:          manifestInt.ReadRemote,
+	}
+}
+
+func NewCobraCmd(diff *DiffCmd) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:           msg.Usage,
+		Short:         msg.ShortDescription,
+		Long:          msg.LongDescription,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Example: heredoc.Doc(`
+		$ azion diff
+		$ azion diff --exit-code
+		$ azion diff --untracked --format json
+		$ azion diff --environment staging
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return diff.Run()
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&ProjectConf, "config-dir", "azion", msg.FlagConfigDir)
+	cobraCmd.Flags().StringVar(&Environment, "environment", "", msg.FlagEnvironment)
+	cobraCmd.Flags().BoolVar(&ExitCode, "exit-code", false, msg.FlagExitCode)
+	cobraCmd.Flags().BoolVar(&Untracked, "untracked", false, msg.FlagUntracked)
+	cobraCmd.Flags().BoolP("help", "h", false, msg.FlagHelp)
+	return cobraCmd
+}
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	return NewCobraCmd(NewDiffCmd(f))
+}
+
+func (cmd *DiffCmd) Run() error {
+	confPath, err := cmd.EnvironmentConfPath(ProjectConf, Environment)
+	if err != nil {
+		return err
+	}
+
+	conf, err := cmd.GetAzionJsonContent(confPath)
+	if err != nil {
+		logger.Debug("Failed to get Azion JSON content", zap.Error(err))
+		return err
+	}
+	if conf.Application.ID == 0 {
+		return msg.ErrorNotDeployed
+	}
+
+	interpreter := cmd.Interpreter()
+	if confPath != ProjectConf {
+		workDir, err := interpreter.GetWorkDir()
+		if err != nil {
+			return err
+		}
+		interpreter.OverridesPath = manifestInt.FindManifest(filepath.Join(workDir, confPath))
+	}
+
+	pathManifest, err := interpreter.ManifestPath()
+	if err != nil {
+		return err
+	}
+	if err := interpreter.ValidateManifest(pathManifest); err != nil {
+		return err
+	}
+	msgs := []string{}
+	manifest, err := interpreter.ReadManifest(pathManifest, cmd.F, &msgs)
+	if err != nil {
+		return err
+	}
+
+	remote, err := cmd.ReadRemote(context.Background(), cmd.F, conf.Application.ID)
+	if err != nil {
+		return err
+	}
+
+	drifts := manifestInt.Diff(manifest, conf, remote, Untracked)
+	if len(drifts) == 0 {
+		msgf := fmt.Sprintf(msg.NoDrift, conf.Application.ID)
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+		msgs = append(msgs, msgf)
+		outSlice := output.SliceOutput{
+			Messages: msgs,
+			GeneralOutput: output.GeneralOutput{
+				Out:   cmd.F.IOStreams.Out,
+				Flags: cmd.F.Flags,
+			},
+		}
+		return output.Print(&outSlice)
+	}
+
+	diffOut := output.DiffOutput{
+		Drifts: drifts,
+		GeneralOutput: output.GeneralOutput{
+			Out:   cmd.F.IOStreams.Out,
+			Flags: cmd.F.Flags,
+		},
+	}
+	if err := output.Print(&diffOut); err != nil {
+		return err
+	}
+
+	if ExitCode {
+		return fmt.Errorf(msg.ErrorDrift.Error(), len(drifts))
+	}
+	return nil
+}
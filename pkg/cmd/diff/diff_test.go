@@ -0,0 +1,60 @@
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestDiff(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	newCmd := func(f *cmdutil.Factory, ttl string) *DiffCmd {
+		cmd := NewDiffCmd(f)
+		cmd.GetAzionJsonContent = func(confPath string) (*contracts.AzionApplicationOptions, error) {
+			return &contracts.AzionApplicationOptions{
+				Application:   contracts.AzionJsonDataApplication{ID: 1337},
+				CacheSettings: []contracts.AzionJsonDataCacheSettings{{Id: 7, Name: "static"}},
+			}, nil
+		}
+		cmd.Interpreter = func() *manifestInt.ManifestInterpreter {
+			interpreter := manifestInt.NewManifestInterpreter()
+			interpreter.GetWorkDir = func() (string, error) { return "", nil }
+			interpreter.FileReader = func(path string) ([]byte, error) {
+				return []byte(`{"cache": [{"name": "static", "cdn_cache_settings_maximum_ttl": ` + ttl + `}]}`), nil
+			}
+			return interpreter
+		}
+		cmd.ReadRemote = func(ctx context.Context, f *cmdutil.Factory, applicationID int64) (*manifestInt.Remote, error) {
+			name, remoteTTL := "static", int64(7200)
+			return &manifestInt.Remote{CacheSettings: []manifestInt.RemoteCache{
+				{ID: 7, Cache: contracts.CacheSetting{Name: &name, CdnCacheSettingsMaximumTtl: &remoteTTL}},
+			}}, nil
+		}
+		return cmd
+	}
+
+	t.Run("no drift", func(t *testing.T) {
+		f, stdout, _ := testutils.NewFactory(&httpmock.Registry{})
+		require.NoError(t, newCmd(f, "7200").Run())
+		require.Contains(t, stdout.String(), "No drift found between manifest.json and edge application 1337")
+	})
+
+	t.Run("drift with --exit-code", func(t *testing.T) {
+		ExitCode = true
+		defer func() { ExitCode = false }()
+
+		f, stdout, _ := testutils.NewFactory(&httpmock.Registry{})
+		err := newCmd(f, "3600").Run()
+		require.EqualError(t, err, "Found 1 differences between manifest.json and the remote configuration")
+		require.Contains(t, stdout.String(), "cdn_cache_settings_maximum_ttl")
+	})
+}
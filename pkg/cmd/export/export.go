@@ -0,0 +1,203 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/MakeNowJust/heredoc"
+	msg "github.com/aziontech/azion-cli/messages/export"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
+	"github.com/aziontech/azion-cli/pkg/output"
+	"github.com/aziontech/azion-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+type ExportCmd struct {
+	F                     *cmdutil.Factory
+	GetWorkDir            func() (string, error)
+	WriteFile             func(filename string, data []byte, perm os.FileMode) error
+	GetAzionJsonContent   func(confPath string) (*contracts.AzionApplicationOptions, error)
+	WriteAzionJsonContent func(conf *contracts.AzionApplicationOptions, confPath string) error
+	Export                func(ctx context.Context, f *cmdutil.Factory, applicationID int64) (*contracts.Manifest, *contracts.AzionApplicationOptions, error)
+	AskInput              func(msg string) (string, error)
+	Confirm               func(globalFlagAll bool, msg string, defaultYes bool) bool
+}
+
+var (
+	ApplicationID int64
+	ProjectConf   string
+	Path          string
+)
+
+func NewExportCmd(f *cmdutil.Factory) *ExportCmd {
+	return &ExportCmd{
+		F:                     f,
+		GetWorkDir:            utils.GetWorkingDir,
+		WriteFile:             os.WriteFile,
+		GetAzionJsonContent:   utils.GetAzionJsonContent,
+		WriteAzionJsonContent: utils.WriteAzionJsonContent,
+		Export:                manifestInt.Export,
+		AskInput:              utils.AskInput,
+		Confirm:               utils.Confirm,
+	}
+}
+
+func NewCobraCmd(export *ExportCmd) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:           msg.Usage,
+		Short:         msg.ShortDescription,
+		Long:          msg.LongDescription,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Example: heredoc.Doc(`
+		$ azion export --application-id 1234
+		$ azion export --application-id 1234 --config-dir azion --path .edge/manifest.json
+		$ azion export --application-id 1234 --yes
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("application-id") {
+				if export.F.NonInteractive {
+					return &utils.NonInteractiveError{Flag: "--application-id"}
+				}
+				answer, err := export.AskInput(msg.AskApplicationID)
+				if err != nil {
+					return err
+				}
+				ApplicationID, err = strconv.ParseInt(answer, 10, 64)
+				if err != nil {
+					logger.Debug("Error while converting the application ID", zap.Error(err))
+					return msg.ErrorConvertApplicationID
+				}
+			}
+			return export.Run()
+		},
+	}
+
+	cobraCmd.Flags().Int64Var(&ApplicationID, "application-id", 0, msg.FlagApplicationID)
+	cobraCmd.Flags().StringVar(&ProjectConf, "config-dir", "azion", msg.FlagConfigDir)
+	cobraCmd.Flags().StringVar(&Path, "path", filepath.Join(".edge", "manifest.json"), msg.FlagPath)
+	cobraCmd.Flags().BoolP("help", "h", false, msg.FlagHelp)
+	return cobraCmd
+}
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	return NewCobraCmd(NewExportCmd(f))
+}
+
+func (cmd *ExportCmd) Run() error {
+	if ApplicationID <= 0 {
+		return msg.ErrorMissingApplicationID
+	}
+
+	workDir, err := cmd.GetWorkDir()
+	if err != nil {
+		return err
+	}
+	manifestPath := Path
+	if !filepath.IsAbs(manifestPath) {
+		manifestPath = filepath.Join(workDir, manifestPath)
+	}
+
+	msgs := []string{}
+	if _, err := os.Stat(manifestPath); err == nil {
+		if cmd.F.NonInteractive && !cmd.F.GlobalFlagAll {
+			return &utils.NonInteractiveError{Flag: "--yes"}
+		}
+		if !cmd.Confirm(cmd.F.GlobalFlagAll, fmt.Sprintf(msg.AskOverwrite, Path), false) {
+			msgf := fmt.Sprintf(msg.ExportNotOverwrite, Path)
+			logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+			msgs = append(msgs, msgf)
+			return cmd.print(msgs)
+		}
+	}
+
+	manifest, exported, err := cmd.Export(context.Background(), cmd.F, ApplicationID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf(msg.ErrorWriteManifest.Error(), Path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(manifestPath), os.ModePerm); err != nil {
+		return fmt.Errorf(msg.ErrorWriteManifest.Error(), Path, err)
+	}
+	if err := cmd.WriteFile(manifestPath, data, 0644); err != nil {
+		logger.Debug("Error while writing the manifest", zap.Error(err))
+		return fmt.Errorf(msg.ErrorWriteManifest.Error(), Path, err)
+	}
+	msgf := fmt.Sprintf(msg.ExportedManifest,
+		len(manifest.Origins), len(manifest.CacheSettings), len(manifest.Rules), ApplicationID, Path)
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+	msgs = append(msgs, msgf)
+
+	conf, err := cmd.GetAzionJsonContent(ProjectConf)
+	if err != nil {
+		// a project linked before the export is completed, otherwise it starts from scratch
+		if !errors.Is(err, utils.ErrorOpeningAzionJsonFile) {
+			return err
+		}
+		conf = &contracts.AzionApplicationOptions{}
+		if err := os.MkdirAll(filepath.Join(workDir, ProjectConf), os.ModePerm); err != nil {
+			return fmt.Errorf(msg.ErrorWriteAzionJson.Error(), err)
+		}
+	}
+	adopt(conf, exported)
+	if err := cmd.WriteAzionJsonContent(conf, ProjectConf); err != nil {
+		logger.Debug("Error while writing azion.json file", zap.Error(err))
+		return fmt.Errorf(msg.ErrorWriteAzionJson.Error(), err)
+	}
+	if conf.Function.InstanceID > 0 {
+		msgf = fmt.Sprintf(msg.ExportedInstance, conf.Function.InstanceID)
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+		msgs = append(msgs, msgf)
+	}
+	msgf = fmt.Sprintf(msg.ExportedAzionJson, filepath.Join(ProjectConf, "azion.json"))
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+	msgs = append(msgs, msgf)
+
+	return cmd.print(msgs)
+}
+
+func (cmd *ExportCmd) print(msgs []string) error {
+	outSlice := output.SliceOutput{
+		Messages: msgs,
+		GeneralOutput: output.GeneralOutput{
+			Out:   cmd.F.IOStreams.Out,
+			Flags: cmd.F.Flags,
+		},
+	}
+	return output.Print(&outSlice)
+}
+
+// adopt tracks the exported resources in the configuration of the project, keeping what describes the project itself
+// (its preset, mode, function files and purge settings)
+func adopt(conf, exported *contracts.AzionApplicationOptions) {
+	if conf.Name == "" {
+		conf.Name = exported.Name
+	}
+	if exported.Bucket != "" {
+		conf.Bucket = exported.Bucket
+	}
+	conf.NotFirstRun = exported.NotFirstRun
+	conf.Application = exported.Application
+	conf.Origin = exported.Origin
+	conf.CacheSettings = exported.CacheSettings
+	conf.RulesEngine = exported.RulesEngine
+	conf.DeviceGroups = exported.DeviceGroups
+	if exported.Function.InstanceID > 0 {
+		conf.Function.ID = exported.Function.ID
+		conf.Function.InstanceID = exported.Function.InstanceID
+		conf.Function.InstanceName = exported.Function.InstanceName
+	}
+}
@@ -0,0 +1,113 @@
+package export
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/aziontech/azion-cli/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// page matches the requests for one page of a paginated list
+func page(matcher httpmock.Matcher, number string) httpmock.Matcher {
+	return func(req *http.Request) bool {
+		return matcher(req) && req.URL.Query().Get("page") == number
+	}
+}
+
+func TestExport(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	reset := func() {
+		ApplicationID, ProjectConf, Path = 0, "azion", filepath.Join(".edge", "manifest.json")
+	}
+
+	t.Run("exports the application and adopts its resources", func(t *testing.T) {
+		defer reset()
+		reset()
+		ApplicationID = 1337
+
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST("GET", "edge_applications/1337"), httpmock.JSONFromFile("./fixtures/application.json"))
+		mock.Register(page(httpmock.REST("GET", "edge_applications/1337/origins"), "1"), httpmock.JSONFromFile("./fixtures/origins.json"))
+		mock.Register(page(httpmock.REST("GET", "edge_applications/1337/origins"), "2"), httpmock.JSONFromFile("./fixtures/origins_page2.json"))
+		mock.Register(httpmock.REST("GET", "edge_applications/1337/device_groups"), httpmock.JSONFromFile("./fixtures/device_groups.json"))
+		mock.Register(httpmock.REST("GET", "edge_applications/1337/cache_settings"), httpmock.JSONFromFile("./fixtures/cache_settings.json"))
+		mock.Register(page(httpmock.REST("GET", "edge_applications/1337/functions_instances"), "1"), httpmock.JSONFromFile("./fixtures/instances.json"))
+		mock.Register(page(httpmock.REST("GET", "edge_applications/1337/functions_instances"), "2"), httpmock.JSONFromFile("./fixtures/instances_page2.json"))
+		mock.Register(httpmock.REST("GET", "edge_applications/1337/rules_engine/request/rules"), httpmock.JSONFromFile("./fixtures/rules_request.json"))
+		mock.Register(httpmock.REST("GET", "edge_applications/1337/rules_engine/response/rules"), httpmock.JSONFromFile("./fixtures/rules_response.json"))
+
+		dir := t.TempDir()
+		f, stdout, _ := testutils.NewFactory(mock)
+		cmd := NewExportCmd(f)
+		cmd.GetWorkDir = func() (string, error) { return dir, nil }
+		cmd.GetAzionJsonContent = func(confPath string) (*contracts.AzionApplicationOptions, error) {
+			return &contracts.AzionApplicationOptions{Name: "project", Preset: "javascript", Mode: "compute"}, nil
+		}
+		var written *contracts.AzionApplicationOptions
+		cmd.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+			written = conf
+			return nil
+		}
+
+		require.NoError(t, cmd.Run())
+		mock.Verify(t)
+		require.Contains(t, stdout.String(), "Exported 2 origins, 1 cache settings and 3 rules of edge application 1337")
+
+		raw, err := os.ReadFile(filepath.Join(dir, ".edge", "manifest.json"))
+		require.NoError(t, err)
+		require.NoError(t, manifestInt.Validate(raw, false))
+
+		interpreter := manifestInt.NewManifestInterpreter()
+		manifest, err := interpreter.ReadManifest(filepath.Join(dir, ".edge", "manifest.json"), f, &[]string{})
+		require.NoError(t, err)
+		require.Len(t, manifest.Rules, 3)
+		behaviors := manifest.Rules[0].Behaviors
+		require.Equal(t, "api", behaviors[0].RulesEngineBehaviorString.Target)
+		require.Equal(t, "static", behaviors[1].RulesEngineBehaviorString.Target)
+		require.Equal(t, "api.example.com", manifest.Origins[0].Addresses[0].Address)
+		require.Equal(t, "assets.example.com", manifest.Origins[1].Addresses[0].Address)
+		require.Equal(t, []contracts.DeviceGroup{{Name: "mobile", UserAgent: "Mobile|Android|iPhone"}}, manifest.DeviceGroups)
+		require.Equal(t, []string{"mobile"}, manifest.CacheSettings[0].DeviceGroups)
+		require.Equal(t, []int32{40}, manifest.CacheSettings[0].DeviceGroup)
+		require.Equal(t, "mobile", *manifest.Rules[2].Criteria[0][0].InputValue)
+
+		require.Equal(t, "project", written.Name)
+		require.Equal(t, "javascript", written.Preset)
+		require.True(t, written.NotFirstRun)
+		require.Equal(t, int64(1337), written.Application.ID)
+		require.Equal(t, "origin-key", written.Origin[0].OriginKey)
+		require.Equal(t, int64(7), written.CacheSettings[0].Id)
+		require.Len(t, written.RulesEngine.Rules, 3)
+		require.Equal(t, []contracts.AzionJsonDataDeviceGroup{{Id: 12, Name: "mobile"}}, written.DeviceGroups)
+		require.Equal(t, int64(55), written.Function.ID)
+		require.Equal(t, int64(99), written.Function.InstanceID)
+	})
+
+	t.Run("non-interactive mode requires --yes to overwrite the manifest", func(t *testing.T) {
+		defer reset()
+		reset()
+		ApplicationID = 1337
+
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, ".edge"), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".edge", "manifest.json"), []byte("{}"), 0644))
+
+		f, _, _ := testutils.NewFactory(&httpmock.Registry{})
+		f.NonInteractive = true
+		cmd := NewExportCmd(f)
+		cmd.GetWorkDir = func() (string, error) { return dir, nil }
+
+		err := cmd.Run()
+		require.ErrorIs(t, err, utils.ErrorNonInteractive)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/aziontech/azion-cli/pkg/cmd/dev"
 	"github.com/aziontech/azion-cli/pkg/cmdutil"
 	"github.com/aziontech/azion-cli/pkg/github"
+	"github.com/aziontech/azion-cli/pkg/hooks"
 	"github.com/aziontech/azion-cli/pkg/iostreams"
 	"github.com/aziontech/azion-cli/pkg/logger"
 	"github.com/aziontech/azion-cli/pkg/node"
@@ -55,6 +56,7 @@ type initCmd struct {
 	deployCmd             func(f *cmdutil.Factory) *deploy.DeployCmd
 	devCmd                func(f *cmdutil.Factory) *dev.DevCmd
 	changeDir             func(dir string) error
+	hooks                 func(f *cmdutil.Factory) *hooks.Hooks
 }
 
 func NewInitCmd(f *cmdutil.Factory) *initCmd {
@@ -79,6 +81,7 @@ func NewInitCmd(f *cmdutil.Factory) *initCmd {
 		devCmd:          dev.NewDevCmd,
 		deployCmd:       deploy.NewDeployCmd,
 		changeDir:       os.Chdir,
+		hooks:           hooks.NewHooks,
 		commandRunner: func(cmd string, envvars []string) (string, int, error) {
 			return utils.RunCommandWithOutput(envvars, cmd)
 		},
@@ -135,6 +138,9 @@ func (cmd *initCmd) Run(c *cobra.Command, _ []string) error {
 	} else {
 		// if name was not sent we ask for input, otherwise info.Name already has the value
 		if cmd.name == "" {
+			if cmd.f.NonInteractive {
+				return &utils.NonInteractiveError{Flag: "--name"}
+			}
 			projName, err := askForInput(msg.InitProjectQuestion, thoth.GenerateName())
 			if err != nil {
 				return err
@@ -143,12 +149,23 @@ func (cmd *initCmd) Run(c *cobra.Command, _ []string) error {
 		}
 	}
 
+	// vulcan asks for the template when it isn't sent
+	if cmd.f.NonInteractive && cmd.template == "" {
+		return &utils.NonInteractiveError{Flag: "--template"}
+	}
+
 	cmd.pathWorkingDir = cmd.pathWorkingDir + "/" + cmd.name
 	err = cmd.selectVulcanTemplates()
 	if err != nil {
 		return err
 	}
 
+	// templates may declare their own hooks in azion/config.json
+	initHooks := cmd.hooks(cmd.f)
+	if err := initHooks.Run(cmd.pathWorkingDir, "azion", hooks.PreInit, &msgs); err != nil {
+		return err
+	}
+
 	if err = cmd.createTemplateAzion(); err != nil {
 		return err
 	}
@@ -167,7 +184,7 @@ func (cmd *initCmd) Run(c *cobra.Command, _ []string) error {
 	if err != nil {
 		return msg.ErrorReadingGitignore
 	}
-	if !gitignore && (cmd.auto || cmd.f.GlobalFlagAll || utils.Confirm(cmd.f.GlobalFlagAll, msg.AskGitignore, true)) {
+	if !gitignore && (cmd.auto || cmd.f.GlobalFlagAll || cmd.f.NonInteractive || utils.Confirm(cmd.f.GlobalFlagAll, msg.AskGitignore, true)) {
 		if err := git.WriteGitignore(cmd.pathWorkingDir); err != nil {
 			return msg.ErrorWritingGitignore
 		}
@@ -175,7 +192,11 @@ func (cmd *initCmd) Run(c *cobra.Command, _ []string) error {
 		msgs = append(msgs, msg.WrittenGitignore)
 	}
 
-	if cmd.auto || !cmd.shouldDevDeploy(msg.AskLocalDev, cmd.globalFlagAll, false) {
+	if err := initHooks.Run(cmd.pathWorkingDir, "azion", hooks.PostInit, &msgs); err != nil {
+		return err
+	}
+
+	if cmd.auto || cmd.f.NonInteractive || !cmd.shouldDevDeploy(msg.AskLocalDev, cmd.globalFlagAll, false) {
 		logger.FInfoFlags(cmd.io.Out, msg.InitDevCommand, cmd.f.Format, cmd.f.Out)
 		msgs = append(msgs, msg.InitDevCommand)
 	} else {
@@ -191,7 +212,7 @@ func (cmd *initCmd) Run(c *cobra.Command, _ []string) error {
 		}
 	}
 
-	if cmd.auto || !cmd.shouldDevDeploy(msg.AskDeploy, cmd.globalFlagAll, false) {
+	if cmd.auto || cmd.f.NonInteractive || !cmd.shouldDevDeploy(msg.AskDeploy, cmd.globalFlagAll, false) {
 		logger.FInfoFlags(cmd.io.Out, msg.InitDeployCommand, cmd.f.Format, cmd.f.Out)
 		msgs = append(msgs, msg.InitDeployCommand)
 		msgEdgeAppInitSuccessFul := fmt.Sprintf(msg.EdgeApplicationsInitSuccessful, cmd.name)
@@ -14,6 +14,7 @@ import (
 	"github.com/aziontech/azion-cli/pkg/cmd/dev"
 	"github.com/aziontech/azion-cli/pkg/cmdutil"
 	"github.com/aziontech/azion-cli/pkg/github"
+	"github.com/aziontech/azion-cli/pkg/hooks"
 	"github.com/aziontech/azion-cli/pkg/iostreams"
 	"github.com/aziontech/azion-cli/pkg/logger"
 	"github.com/aziontech/azion-cli/pkg/node"
@@ -32,6 +33,7 @@ type LinkInfo struct {
 	packageManager string
 	PathWorkingDir string
 	GlobalFlagAll  bool
+	NonInteractive bool
 	remote         string
 	Auto           bool
 	projectPath    string
@@ -59,6 +61,7 @@ type LinkCmd struct {
 	ShouldDevDeploy       func(info *LinkInfo, msg string, defaultYes bool) bool
 	DeployCmd             func(f *cmdutil.Factory) *deploy.DeployCmd
 	DevCmd                func(f *cmdutil.Factory) *dev.DevCmd
+	Hooks                 func(f *cmdutil.Factory) *hooks.Hooks
 	F                     *cmdutil.Factory
 }
 
@@ -84,6 +87,7 @@ func NewLinkCmd(f *cmdutil.Factory) *LinkCmd {
 		ShouldDevDeploy: shouldDevDeploy,
 		DevCmd:          dev.NewDevCmd,
 		DeployCmd:       deploy.NewDeployCmd,
+		Hooks:           hooks.NewHooks,
 		CommandRunner: func(f *cmdutil.Factory, comm string, envVars []string) (string, error) {
 			return utils.CommandRunInteractiveWithOutput(f, comm, envVars)
 		},
@@ -108,9 +112,11 @@ func NewCobraCmd(link *LinkCmd, f *cmdutil.Factory) *cobra.Command {
 		$ azion link --preset astro --mode deliver
 		$ azion link --name "thisisatest" --preset nextjs
 		$ azion link --name "thisisatest" --preset static
+		$ azion link --auto --name "thisisatest" --preset astro --mode deliver --non-interactive
 		`),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			info.GlobalFlagAll = f.GlobalFlagAll
+			info.NonInteractive = f.NonInteractive
 			return link.run(cmd, info)
 		},
 	}
@@ -164,6 +170,11 @@ func (cmd *LinkCmd) run(c *cobra.Command, info *LinkInfo) error {
 		}
 	}
 
+	// linking has no default answer, so non-interactive mode needs it confirmed up front
+	if info.NonInteractive && !info.Auto && !info.GlobalFlagAll {
+		return &utils.NonInteractiveError{Flag: "--auto"}
+	}
+
 	shouldLink := cmd.ShouldConfigure(info)
 	if !shouldLink {
 		return nil
@@ -175,12 +186,20 @@ func (cmd *LinkCmd) run(c *cobra.Command, info *LinkInfo) error {
 	}
 
 	if shouldFetchTemplates {
+		initHooks := cmd.Hooks(cmd.F)
+		if err := initHooks.Run(info.PathWorkingDir, info.projectPath, hooks.PreInit, &msgs); err != nil {
+			return err
+		}
+
 		// Checks for global --yes flag and that name flag was not sent
 		if (info.GlobalFlagAll || info.Auto) && info.Name == "" {
 			info.Name = thoth.GenerateName()
 		} else {
 			// if name was not sent we ask for input, otherwise info.Name already has the value
 			if info.Name == "" {
+				if info.NonInteractive {
+					return &utils.NonInteractiveError{Flag: "--name"}
+				}
 				projName, err := askForInput(msg.LinkProjectQuestion, thoth.GenerateName())
 				if err != nil {
 					return err
@@ -190,6 +209,9 @@ func (cmd *LinkCmd) run(c *cobra.Command, info *LinkInfo) error {
 		}
 
 		if info.Preset == "" || info.Mode == "" {
+			if info.NonInteractive && info.Preset != "nextjs" {
+				return &utils.NonInteractiveError{Flag: missingPresetFlag(info)}
+			}
 			err = cmd.selectVulcanMode(info)
 			if err != nil {
 				return err
@@ -209,7 +231,7 @@ func (cmd *LinkCmd) run(c *cobra.Command, info *LinkInfo) error {
 			return msg.ErrorReadingGitignore
 		}
 
-		if !gitignore && (info.Auto || info.GlobalFlagAll || utils.Confirm(info.GlobalFlagAll, msg.AskGitignore, true)) {
+		if !gitignore && (info.Auto || info.GlobalFlagAll || info.NonInteractive || utils.Confirm(info.GlobalFlagAll, msg.AskGitignore, true)) {
 			if err := git.WriteGitignore(info.PathWorkingDir); err != nil {
 				return msg.ErrorWritingGitignore
 			}
@@ -217,7 +239,11 @@ func (cmd *LinkCmd) run(c *cobra.Command, info *LinkInfo) error {
 			msgs = append(msgs, msg.WrittenGitignore)
 		}
 
-		if !info.Auto {
+		if err := initHooks.Run(info.PathWorkingDir, info.projectPath, hooks.PostInit, &msgs); err != nil {
+			return err
+		}
+
+		if !info.Auto && !info.NonInteractive {
 			if cmd.ShouldDevDeploy(info, msg.AskLocalDev, false) {
 				if err := deps(c, cmd, info, msg.AskInstallDepsDev); err != nil {
 					return err
@@ -46,6 +46,14 @@ func shouldFetch(cmd *LinkCmd, info *LinkInfo) (bool, error) {
 	return true, nil
 }
 
+// missingPresetFlag names the flag non-interactive mode needs in place of the preset and mode prompt
+func missingPresetFlag(info *LinkInfo) string {
+	if info.Preset == "" {
+		return "--preset"
+	}
+	return "--mode"
+}
+
 func askForInput(msg string, defaultIn string) (string, error) {
 	var userInput string
 	prompt := &survey.Input{
@@ -2,6 +2,7 @@ package ruleengine
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -29,6 +30,7 @@ func NewCmd(f *cmdutil.Factory) *cobra.Command {
 		Long:          msg.RulesEngineListLongDescription,
 		SilenceUsage:  true,
 		SilenceErrors: true, Example: heredoc.Doc(`
+		$ azion list rules-engine --application-id 1673635839
 		$ azion list rules-engine --application-id 1673635839 --phase request
 		$ azion list rules-engine --application-id 1673635839 --phase response --details
 		`),
@@ -50,16 +52,6 @@ func NewCmd(f *cmdutil.Factory) *cobra.Command {
 				edgeApplicationID = num
 			}
 
-			if !cmd.Flags().Changed("phase") {
-
-				answer, err := utils.AskInput(msg.AskInputPhase)
-				if err != nil {
-					return err
-				}
-
-				phase = answer
-			}
-
 			if err := PrintTable(cmd, f, opts); err != nil {
 				return fmt.Errorf(msg.ErrorGetRulesEngines.Error(), err)
 			}
@@ -70,7 +62,7 @@ func NewCmd(f *cmdutil.Factory) *cobra.Command {
 	cmdutil.AddAzionApiFlags(cmd, opts)
 	cmd.Flags().BoolP("help", "h", false, msg.RulesEngineListHelpFlag)
 	cmd.Flags().Int64Var(&edgeApplicationID, "application-id", 0, msg.ApplicationFlagId)
-	cmd.Flags().StringVar(&phase, "phase", "request", msg.RulesEnginePhase)
+	cmd.Flags().StringVar(&phase, "phase", "", msg.RulesEnginePhase)
 	return cmd
 }
 
@@ -78,9 +70,10 @@ func PrintTable(cmd *cobra.Command, f *cmdutil.Factory, opts *contracts.ListOpti
 	client := api.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
 	ctx := context.Background()
 
-	rules, err := client.ListRulesEngine(ctx, opts, edgeApplicationID, phase)
-	if err != nil {
-		return err
+	// without a phase, the rules of every phase are listed
+	phases := []string{phase}
+	if phase == "" {
+		phases = api.RulesEnginePhases
 	}
 
 	listOut := output.ListOutput{}
@@ -88,19 +81,35 @@ func PrintTable(cmd *cobra.Command, f *cmdutil.Factory, opts *contracts.ListOpti
 	listOut.Out = f.IOStreams.Out
 	listOut.Flags = f.Flags
 
-	if cmd.Flags().Changed("details") {
+	details := cmd.Flags().Changed("details")
+	if details {
 		listOut.Columns = []string{"ID", "NAME", "ORDER", "PHASE", "ACTIVE"}
+	} else if len(phases) > 1 {
+		listOut.Columns = []string{"ID", "NAME", "PHASE"}
 	}
 
-	for _, v := range rules.Results {
-		ln := []string{
-			fmt.Sprintf("%d", v.Id),
-			v.Name,
-			fmt.Sprintf("%d", v.Order),
-			v.Phase,
-			fmt.Sprintf("%v", v.IsActive),
+	for _, p := range phases {
+		rules, err := client.ListRulesEngine(ctx, opts, edgeApplicationID, p)
+		if err != nil {
+			if len(phases) > 1 && errors.Is(err, utils.ErrorNotFound404) {
+				continue
+			}
+			return err
+		}
+
+		for _, v := range rules.Results {
+			ln := []string{
+				fmt.Sprintf("%d", v.Id),
+				v.Name,
+				fmt.Sprintf("%d", v.Order),
+				v.Phase,
+				fmt.Sprintf("%v", v.IsActive),
+			}
+			if !details && len(phases) > 1 {
+				ln = []string{ln[0], ln[1], ln[3]}
+			}
+			listOut.Lines = append(listOut.Lines, ln)
 		}
-		listOut.Lines = append(listOut.Lines, ln)
 	}
 	return output.Print(&listOut)
 }
@@ -1,6 +1,7 @@
 package ruleengine
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/aziontech/azion-cli/pkg/logger"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/aziontech/azion-cli/pkg/httpmock"
 	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/aziontech/tablecli"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,4 +48,29 @@ func TestNewCmd(t *testing.T) {
 		_, err := cmd.ExecuteC()
 		require.NoError(t, err)
 	})
+
+	t.Run("list the rules of every phase", func(t *testing.T) {
+		// the widths of the previous tables have fewer columns
+		tablecli.WidthPersist = nil
+		mock := &httpmock.Registry{}
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1678743802/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1678743802/rules_engine/response/rules"),
+			httpmock.StatusStringResponse(http.StatusNotFound, `{"detail": "Not found."}`),
+		)
+
+		f, stdout, _ := testutils.NewFactory(mock)
+		cmd := NewCmd(f)
+
+		cmd.SetArgs([]string{"--application-id", "1678743802"})
+
+		_, err := cmd.ExecuteC()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "PHASE")
+		mock.Verify(t)
+	})
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/aziontech/azion-cli/pkg/logger"
 	"github.com/aziontech/azion-cli/pkg/output"
 	"github.com/aziontech/azion-cli/pkg/token"
+	"github.com/aziontech/azion-cli/utils"
 	"github.com/pelletier/go-toml/v2"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -31,7 +32,7 @@ func NewCmd(f *cmdutil.Factory) *cobra.Command {
         `),
 		RunE: func(cmd *cobra.Command, args []string) error {
 
-			answer, err := selectLoginMode()
+			answer, err := selectLoginMode(cmd, f)
 			if err != nil {
 				return err
 			}
@@ -83,7 +84,17 @@ func NewCmd(f *cmdutil.Factory) *cobra.Command {
 	return cmd
 }
 
-func selectLoginMode() (string, error) {
+// selectLoginMode asks for the login method. Non-interactive mode logs in via terminal, the one its flags answer
+func selectLoginMode(cmd *cobra.Command, f *cmdutil.Factory) (string, error) {
+	if f.NonInteractive {
+		for _, flag := range []string{"username", "password"} {
+			if !cmd.Flags().Changed(flag) {
+				return "", &utils.NonInteractiveError{Flag: "--" + flag}
+			}
+		}
+		return "Log in via terminal", nil
+	}
+
 	answer := ""
 	prompt := &survey.Select{
 		Message: "Choose a login method:",
@@ -0,0 +1,113 @@
+package validate
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc"
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
+	"github.com/aziontech/azion-cli/pkg/output"
+	"github.com/aziontech/azion-cli/utils"
+	"github.com/spf13/cobra"
+)
+
+type ValidateCmd struct {
+	F                   *cmdutil.Factory
+	Interpreter         func() *manifestInt.ManifestInterpreter
+	GetWorkDir          func() (string, error)
+	EnvironmentConfPath func(confPath, environment string) (string, error)
+}
+
+var (
+	Path        string
+	ProjectConf string
+	Environment string
+	Env         string
+)
+
+func NewValidateCmd(f *cmdutil.Factory) *ValidateCmd {
+	return &ValidateCmd{
+		F:                   f,
+		Interpreter:         manifestInt.NewManifestInterpreter,
+		GetWorkDir:          utils.GetWorkingDir,
+		EnvironmentConfPath: utils.EnvironmentConfPath,
+	}
+}
+
+func NewCobraCmd(validate *ValidateCmd) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:           msg.ValidateUsage,
+		Short:         msg.ValidateShortDescription,
+		Long:          msg.ValidateLongDescription,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Example: heredoc.Doc(`
+		$ azion manifest validate
+		$ azion manifest validate --path ./manifest.yaml
+		$ azion manifest validate --env .edge/.env.staging
+		$ azion manifest validate --environment staging
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return validate.Run()
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&Path, "path", "", msg.FlagPath)
+	cobraCmd.Flags().StringVar(&Env, "env", filepath.Join(".edge", ".env"), msg.FlagEnv)
+	cobraCmd.Flags().StringVar(&ProjectConf, "config-dir", "azion", msg.FlagConfigDir)
+	cobraCmd.Flags().StringVar(&Environment, "environment", "", msg.FlagEnvironment)
+	cobraCmd.Flags().BoolP("help", "h", false, msg.ValidateFlagHelp)
+	return cobraCmd
+}
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	return NewCobraCmd(NewValidateCmd(f))
+}
+
+func (cmd *ValidateCmd) Run() error {
+	workDir, err := cmd.GetWorkDir()
+	if err != nil {
+		return err
+	}
+
+	interpreter := cmd.Interpreter()
+	if Environment != "" {
+		confPath, err := cmd.EnvironmentConfPath(ProjectConf, Environment)
+		if err != nil {
+			return err
+		}
+		// the environment the project was created with has no overrides
+		if confPath != ProjectConf {
+			interpreter.OverridesPath = manifestInt.FindManifest(filepath.Join(workDir, confPath))
+		}
+	}
+
+	interpreter.EnvPath = Env
+	path := Path
+	if path == "" {
+		path, err = interpreter.ManifestPath()
+		if err != nil {
+			return err
+		}
+	} else if !filepath.IsAbs(path) {
+		path = filepath.Join(workDir, path)
+	}
+	if err := interpreter.ValidateManifest(path); err != nil {
+		return err
+	}
+
+	msgf := fmt.Sprintf(msg.ManifestValid, path)
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+
+	outSlice := output.SliceOutput{
+		Messages: []string{msgf},
+		GeneralOutput: output.GeneralOutput{
+			Out:   cmd.F.IOStreams.Out,
+			Flags: cmd.F.Flags,
+		},
+	}
+	return output.Print(&outSlice)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/aziontech/azion-cli/pkg/cmdutil"
 	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -46,6 +47,9 @@ func runE(f *cmdutil.Factory) func(cmd *cobra.Command, _ []string) error {
 	return func(cmd *cobra.Command, _ []string) error {
 
 		if !cmd.Flags().Changed("urls") && !cmd.Flags().Changed("wildcard") && !cmd.Flags().Changed("cache-key") {
+			if f.NonInteractive {
+				return &utils.NonInteractiveError{Flag: "--urls, --wildcard or --cache-key"}
+			}
 			answer, err := getPurgeType()
 			if err != nil {
 				return err
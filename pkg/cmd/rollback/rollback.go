@@ -0,0 +1,307 @@
+package rollback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/MakeNowJust/heredoc"
+	msg "github.com/aziontech/azion-cli/messages/rollback"
+	apidom "github.com/aziontech/azion-cli/pkg/api/domain"
+	apiapp "github.com/aziontech/azion-cli/pkg/api/edge_applications"
+	apifunc "github.com/aziontech/azion-cli/pkg/api/edge_function"
+	apiori "github.com/aziontech/azion-cli/pkg/api/origin"
+	apipurge "github.com/aziontech/azion-cli/pkg/api/realtime_purge"
+	"github.com/aziontech/azion-cli/pkg/cmd/deploy"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/output"
+	"github.com/aziontech/azion-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+type RollbackCmd struct {
+	F                     *cmdutil.Factory
+	FileReader            func(path string) ([]byte, error)
+	GetAzionJsonContent   func(confPath string) (*contracts.AzionApplicationOptions, error)
+	WriteAzionJsonContent func(conf *contracts.AzionApplicationOptions, confPath string) error
+	GetDeploymentHistory  func(confPath string) (*contracts.DeploymentHistory, error)
+	Unmarshal             func(data []byte, v interface{}) error
+	EnvironmentConfPath   func(confPath, environment string) (string, error)
+}
+
+var (
+	Version     string
+	ProjectConf string
+	Environment string
+)
+
+func NewRollbackCmd(f *cmdutil.Factory) *RollbackCmd {
+	return &RollbackCmd{
+		F:                     f,
+		FileReader:            os.ReadFile,
+		GetAzionJsonContent:   utils.GetAzionJsonContent,
+		WriteAzionJsonContent: utils.WriteAzionJsonContent,
+		GetDeploymentHistory:  utils.GetDeploymentHistory,
+		Unmarshal:             json.Unmarshal,
+		EnvironmentConfPath:   utils.EnvironmentConfPath,
+	}
+}
+
+func NewCobraCmd(rollback *RollbackCmd) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:           msg.Usage,
+		Short:         msg.ShortDescription,
+		Long:          msg.LongDescription,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Example: heredoc.Doc(`
+		$ azion rollback --version 20240521143012
+		$ azion rollback --version 20240521143012 --config-dir azion
+		$ azion rollback --version 20240521143012 --environment staging
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return rollback.Run()
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&Version, "version", "", msg.FlagVersion)
+	cobraCmd.Flags().StringVar(&ProjectConf, "config-dir", "azion", msg.FlagConfigDir)
+	cobraCmd.Flags().StringVar(&Environment, "environment", "", msg.FlagEnvironment)
+	cobraCmd.Flags().BoolP("help", "h", false, msg.FlagHelp)
+	return cobraCmd
+}
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	return NewCobraCmd(NewRollbackCmd(f))
+}
+
+func (cmd *RollbackCmd) Run() error {
+	msgs := []string{}
+	ctx := context.Background()
+
+	if Version == "" {
+		return msg.ErrorMissingVersion
+	}
+
+	baseConf := ProjectConf
+	confPath, err := cmd.EnvironmentConfPath(baseConf, Environment)
+	if err != nil {
+		return err
+	}
+	ProjectConf = confPath
+	defer func() { ProjectConf = baseConf }()
+
+	conf, err := cmd.GetAzionJsonContent(ProjectConf)
+	if err != nil {
+		logger.Debug("Failed to get Azion JSON content", zap.Error(err))
+		return err
+	}
+
+	if conf.Function.ID == 0 {
+		return msg.ErrorNotDeployed
+	}
+
+	history, err := cmd.GetDeploymentHistory(ProjectConf)
+	if err != nil {
+		return err
+	}
+
+	record, err := findRecord(history, Version)
+	if err != nil {
+		return err
+	}
+
+	code, args, err := cmd.readSnapshot(record)
+	if err != nil {
+		return err
+	}
+
+	bucket := record.Bucket
+	if bucket == "" {
+		bucket = conf.Bucket
+	}
+
+	httpClient := cmd.F.HttpClient
+	apiURL := cmd.F.Config.GetString("api_url")
+	token := cmd.F.Config.GetString("token")
+
+	reqFunc := apifunc.UpdateRequest{}
+	reqFunc.SetCode(string(deploy.InjectStorage(code, bucket, record.StoragePrefix())))
+	reqFunc.SetJsonArgs(args)
+	if _, err := apifunc.NewClient(httpClient, apiURL, token).Update(ctx, &reqFunc, conf.Function.ID); err != nil {
+		logger.Debug("Error while updating Edge Function", zap.Error(err))
+		return fmt.Errorf(msg.ErrorUpdateFunction.Error(), err)
+	}
+	msgf := fmt.Sprintf(msg.RollbackFunction, conf.Function.ID)
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+	msgs = append(msgs, msgf)
+
+	if conf.Function.InstanceID > 0 {
+		reqIns := apiapp.UpdateInstanceRequest{}
+		reqIns.SetEdgeFunctionId(conf.Function.ID)
+		reqIns.SetArgs(args)
+		appID := strconv.FormatInt(conf.Application.ID, 10)
+		instID := strconv.FormatInt(conf.Function.InstanceID, 10)
+		if _, err := apiapp.NewClient(httpClient, apiURL, token).UpdateInstance(ctx, &reqIns, appID, instID); err != nil {
+			logger.Debug("Error while updating Edge Function Instance", zap.Error(err))
+			return fmt.Errorf(msg.ErrorUpdateInstance.Error(), err)
+		}
+		msgf := fmt.Sprintf(msg.RollbackInstance, conf.Function.InstanceID)
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+		msgs = append(msgs, msgf)
+	}
+
+	if err := cmd.rollbackOrigins(ctx, conf, record, &msgs); err != nil {
+		return err
+	}
+
+	conf.Prefix = record.StoragePrefix()
+	if err := cmd.WriteAzionJsonContent(conf, ProjectConf); err != nil {
+		logger.Debug("Error while writing azion.json file", zap.Error(err))
+		return err
+	}
+
+	if err := cmd.purgeDomain(ctx, conf, &msgs); err != nil {
+		return err
+	}
+
+	msgf = fmt.Sprintf(msg.RollbackSuccessful, record.VersionID)
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+	msgs = append(msgs, msgf)
+
+	outSlice := output.SliceOutput{
+		Messages: msgs,
+		GeneralOutput: output.GeneralOutput{
+			Out:   cmd.F.IOStreams.Out,
+			Flags: cmd.F.Flags,
+		},
+	}
+	return output.Print(&outSlice)
+}
+
+func findRecord(history *contracts.DeploymentHistory, version string) (contracts.DeploymentRecord, error) {
+	for _, record := range history.Deployments {
+		if record.VersionID == version {
+			if record.Pruned {
+				return contracts.DeploymentRecord{}, fmt.Errorf(msg.ErrorVersionPruned.Error(), version)
+			}
+			return record, nil
+		}
+	}
+	return contracts.DeploymentRecord{}, fmt.Errorf(msg.ErrorVersionNotFound.Error(), version)
+}
+
+// readSnapshot loads the function code and args saved by the deploy, refusing code that no longer matches the recorded hash
+func (cmd *RollbackCmd) readSnapshot(record contracts.DeploymentRecord) ([]byte, map[string]interface{}, error) {
+	dir := deploy.SnapshotDir(ProjectConf, record.VersionID)
+
+	code, err := cmd.FileReader(filepath.Join(dir, deploy.SnapshotCode))
+	if err != nil {
+		logger.Debug("Error while reading deployment snapshot", zap.Error(err))
+		return nil, nil, fmt.Errorf(msg.ErrorSnapshotNotFound.Error(), record.VersionID, err)
+	}
+
+	if deploy.HashCode(code) != record.FunctionHash {
+		return nil, nil, fmt.Errorf(msg.ErrorSnapshotMismatch.Error(), record.VersionID)
+	}
+
+	marshalledArgs, err := cmd.FileReader(filepath.Join(dir, deploy.SnapshotArgs))
+	if err != nil {
+		logger.Debug("Error while reading deployment snapshot", zap.Error(err))
+		return nil, nil, fmt.Errorf(msg.ErrorSnapshotNotFound.Error(), record.VersionID, err)
+	}
+
+	args := make(map[string]interface{})
+	if err := cmd.Unmarshal(marshalledArgs, &args); err != nil {
+		logger.Debug("Error while unmarshalling args of the snapshot", zap.Error(err))
+		return nil, nil, msg.ErrorParseArgs
+	}
+
+	return code, args, nil
+}
+
+// rollbackOrigins points the object storage origins that follow the deploy prefix to the restored version.
+// Origins with a prefix of their own, set in the manifest, are left untouched.
+func (cmd *RollbackCmd) rollbackOrigins(
+	ctx context.Context,
+	conf *contracts.AzionApplicationOptions,
+	record contracts.DeploymentRecord,
+	msgs *[]string) error {
+	if conf.Application.ID == 0 || conf.Prefix == "" {
+		return nil
+	}
+
+	client := apiori.NewClient(cmd.F.HttpClient, cmd.F.Config.GetString("api_url"), cmd.F.Config.GetString("token"))
+	for _, origin := range conf.Origin {
+		if origin.OriginKey == "" {
+			continue
+		}
+
+		remote, err := client.Get(ctx, conf.Application.ID, origin.OriginKey)
+		if err != nil {
+			if errors.Is(err, utils.ErrorNotFound404) {
+				continue
+			}
+			logger.Debug("Error while reading origin", zap.String("origin", origin.Name), zap.Error(err))
+			return err
+		}
+
+		if remote.GetOriginType() != "object_storage" || remote.GetPrefix() != conf.Prefix || conf.Prefix == record.StoragePrefix() {
+			continue
+		}
+
+		req := apiori.UpdateRequest{}
+		req.SetPrefix(record.StoragePrefix())
+		if _, err := client.Update(ctx, conf.Application.ID, origin.OriginKey, &req); err != nil {
+			logger.Debug("Error while updating origin", zap.String("origin", origin.Name), zap.Error(err))
+			return err
+		}
+
+		msgf := fmt.Sprintf(msg.RollbackOrigin, origin.Name, record.StoragePrefix())
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+		*msgs = append(*msgs, msgf)
+	}
+
+	return nil
+}
+
+func (cmd *RollbackCmd) purgeDomain(ctx context.Context, conf *contracts.AzionApplicationOptions, msgs *[]string) error {
+	if conf.Domain.Id == 0 {
+		return nil
+	}
+
+	httpClient := cmd.F.HttpClient
+	apiURL := cmd.F.Config.GetString("api_url")
+	token := cmd.F.Config.GetString("token")
+
+	domain, err := apidom.NewClient(httpClient, apiURL, token).Get(ctx, strconv.FormatInt(conf.Domain.Id, 10))
+	if err != nil {
+		logger.Debug("Error while reading domain", zap.Error(err))
+		return err
+	}
+
+	listURLsDomains := domain.GetCnames()
+	if !domain.GetCnameAccessOnly() {
+		listURLsDomains = append(listURLsDomains, domain.GetDomainName())
+	}
+
+	clipurge := apipurge.NewClient(httpClient, apiURL, token)
+	for _, url := range listURLsDomains {
+		if err := clipurge.PurgeWildcard(ctx, []string{url + "/*"}); err != nil {
+			logger.Debug("Error while purging wildcard domain", zap.String("domain", url), zap.Error(err))
+			return err
+		}
+		msgf := fmt.Sprintf(msg.RollbackPurge, url)
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+		*msgs = append(*msgs, msgf)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package rollback
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	msg "github.com/aziontech/azion-cli/messages/rollback"
+	"github.com/aziontech/azion-cli/pkg/cmd/deploy"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+var successResponseFunction = `
+{
+    "results":{
+        "id":1337,
+        "name":"SUUPA_FUNCTION",
+        "language":"javascript",
+        "code":"async function handleRequest(request) {return new Response(\"Hello World!\",{status:200})}",
+        "json_args":{"a":1},
+        "function_to_run":"",
+        "initiator_type":"edge_application",
+        "active":true,
+        "last_editor":"testando@azion.com",
+        "modified":"2022-01-26T12:31:09.865515Z",
+        "reference_count":0
+    },
+    "schema_version":3
+}
+`
+
+var successResponseInstance = `
+{
+    "results":{
+        "edge_function_id":1337,
+        "name":"SUUPA_INSTANCE",
+        "args":{"a":1},
+        "id":42
+    },
+    "schema_version":3
+}
+`
+
+func TestRollback(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	code := []byte("async function handleRequest(request) {}")
+	history := &contracts.DeploymentHistory{
+		Deployments: []contracts.DeploymentRecord{
+			{VersionID: "20231201000000", FunctionHash: deploy.HashCode(code), Bucket: "bucket", Pruned: true},
+			{VersionID: "20240101000000", FunctionHash: deploy.HashCode(code), Bucket: "bucket"},
+			{VersionID: "20240202000000", FunctionHash: deploy.HashCode(code), Bucket: "bucket"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		version       string
+		code          []byte
+		expectedError error
+	}{
+		{
+			name:    "rollback to a recorded version",
+			version: "20240101000000",
+			code:    code,
+		},
+		{
+			name:          "version not recorded",
+			version:       "20230101000000",
+			code:          code,
+			expectedError: fmt.Errorf(msg.ErrorVersionNotFound.Error(), "20230101000000"),
+		},
+		{
+			name:          "static files of the version were pruned",
+			version:       "20231201000000",
+			code:          code,
+			expectedError: fmt.Errorf(msg.ErrorVersionPruned.Error(), "20231201000000"),
+		},
+		{
+			name:          "snapshot modified after deploy",
+			version:       "20240101000000",
+			code:          []byte("changed"),
+			expectedError: fmt.Errorf(msg.ErrorSnapshotMismatch.Error(), "20240101000000"),
+		},
+		{
+			name:          "missing version flag",
+			version:       "",
+			expectedError: msg.ErrorMissingVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &httpmock.Registry{}
+			mock.Register(
+				httpmock.REST("PATCH", "edge_functions/1337"),
+				httpmock.JSONFromString(successResponseFunction),
+			)
+			mock.Register(
+				httpmock.REST("PATCH", "edge_applications/1697666970/functions_instances/42"),
+				httpmock.JSONFromString(successResponseInstance),
+			)
+
+			f, _, _ := testutils.NewFactory(mock)
+
+			var written *contracts.AzionApplicationOptions
+			rollbackCmd := NewRollbackCmd(f)
+			rollbackCmd.GetAzionJsonContent = func(confPath string) (*contracts.AzionApplicationOptions, error) {
+				return &contracts.AzionApplicationOptions{
+					Bucket:      "bucket",
+					Prefix:      "20240202000000",
+					Function:    contracts.AzionJsonDataFunction{ID: 1337, InstanceID: 42},
+					Application: contracts.AzionJsonDataApplication{ID: 1697666970},
+				}, nil
+			}
+			rollbackCmd.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+				written = conf
+				return nil
+			}
+			rollbackCmd.GetDeploymentHistory = func(confPath string) (*contracts.DeploymentHistory, error) {
+				return history, nil
+			}
+			rollbackCmd.FileReader = func(path string) ([]byte, error) {
+				switch filepath.Base(path) {
+				case deploy.SnapshotCode:
+					return tt.code, nil
+				case deploy.SnapshotArgs:
+					return json.Marshal(map[string]interface{}{"a": 1})
+				}
+				return nil, errors.New("unexpected file")
+			}
+
+			cmd := NewCobraCmd(rollbackCmd)
+			cmd.SetArgs([]string{"--version", tt.version})
+
+			err := cmd.Execute()
+			if tt.expectedError != nil {
+				require.EqualError(t, err, tt.expectedError.Error())
+				require.Nil(t, written)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.version, written.Prefix)
+		})
+	}
+}
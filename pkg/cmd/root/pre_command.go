@@ -56,8 +56,11 @@ func doPreCommandCheck(cmd *cobra.Command, f *cmdutil.Factory, pre PreCmd) error
 		return err
 	}
 
-	if err := checkAuthorizeMetricsCollection(cmd, f.GlobalFlagAll, globalSettings); err != nil {
-		return err
+	// left unanswered in non-interactive mode, so it is asked on the next interactive run
+	if !f.NonInteractive {
+		if err := checkAuthorizeMetricsCollection(cmd, f.GlobalFlagAll, globalSettings); err != nil {
+			return err
+		}
 	}
 
 	//both verifications occurs if 24 hours have passed since the last execution
@@ -12,16 +12,23 @@ import (
 	"github.com/aziontech/azion-cli/pkg/cmd/completion"
 	"github.com/aziontech/azion-cli/pkg/cmd/create"
 	"github.com/aziontech/azion-cli/pkg/cmd/delete"
+	"github.com/aziontech/azion-cli/pkg/cmd/deployments"
 	"github.com/aziontech/azion-cli/pkg/cmd/describe"
+	"github.com/aziontech/azion-cli/pkg/cmd/diff"
+	"github.com/aziontech/azion-cli/pkg/cmd/export"
 	"github.com/aziontech/azion-cli/pkg/cmd/list"
 	"github.com/aziontech/azion-cli/pkg/cmd/login"
 	"github.com/aziontech/azion-cli/pkg/cmd/logout"
 	logcmd "github.com/aziontech/azion-cli/pkg/cmd/logs"
+	"github.com/aziontech/azion-cli/pkg/cmd/manifest"
 	"github.com/aziontech/azion-cli/pkg/cmd/purge"
 	"github.com/aziontech/azion-cli/pkg/cmd/reset"
+	"github.com/aziontech/azion-cli/pkg/cmd/rollback"
+	"github.com/aziontech/azion-cli/pkg/cmd/storage"
 	"github.com/aziontech/azion-cli/pkg/cmd/sync"
 	"github.com/aziontech/azion-cli/pkg/cmd/unlink"
 	"github.com/aziontech/azion-cli/pkg/cmd/update"
+	"github.com/aziontech/azion-cli/pkg/cmd/variables"
 	"github.com/aziontech/azion-cli/pkg/cmd/whoami"
 	"github.com/aziontech/azion-cli/pkg/metric"
 	"github.com/aziontech/azion-cli/pkg/output"
@@ -37,6 +44,7 @@ import (
 	"github.com/aziontech/azion-cli/pkg/iostreams"
 	"github.com/aziontech/azion-cli/pkg/logger"
 	"github.com/aziontech/azion-cli/pkg/token"
+	"github.com/aziontech/azion-cli/utils"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -78,6 +86,12 @@ func NewCobraCmd(rootCmd *RootCmd, f *cmdutil.Factory) *cobra.Command {
 			startTime = time.Now()
 			logger.LogLevel(f.Logger)
 
+			// CI jobs rarely have a terminal attached; waiting on a prompt there would hang them
+			if !f.NonInteractive && !utils.StdinIsTerminal() {
+				f.NonInteractive = true
+			}
+			utils.NonInteractive = f.NonInteractive
+
 			if strings.HasPrefix(configFlag, PREFIX_FLAG) {
 				return msg.ErrorPrefix
 			}
@@ -126,6 +140,7 @@ func NewCobraCmd(rootCmd *RootCmd, f *cmdutil.Factory) *cobra.Command {
 	cobraCmd.PersistentFlags().StringVar(&f.Out, "out", "", msg.RootFlagOut)
 	cobraCmd.PersistentFlags().StringVar(&f.Format, "format", "", msg.RootFlagFormat)
 	cobraCmd.PersistentFlags().BoolVar(&f.NoColor, "no-color", false, msg.RootFlagFormat)
+	cobraCmd.PersistentFlags().BoolVar(&f.NonInteractive, "non-interactive", false, msg.RootFlagNonInteractive)
 
 	// other flags
 	cobraCmd.Flags().BoolP("help", "h", false, msg.RootHelpFlag)
@@ -153,6 +168,13 @@ func NewCobraCmd(rootCmd *RootCmd, f *cmdutil.Factory) *cobra.Command {
 	cobraCmd.AddCommand(purge.NewCmd(f))
 	cobraCmd.AddCommand(reset.NewCmd(f))
 	cobraCmd.AddCommand(sync.NewCmd(f))
+	cobraCmd.AddCommand(deployments.NewCmd(f))
+	cobraCmd.AddCommand(rollback.NewCmd(f))
+	cobraCmd.AddCommand(storage.NewCmd(f))
+	cobraCmd.AddCommand(manifest.NewCmd(f))
+	cobraCmd.AddCommand(export.NewCmd(f))
+	cobraCmd.AddCommand(diff.NewCmd(f))
+	cobraCmd.AddCommand(variables.NewCmd(f))
 
 	return cobraCmd
 }
@@ -0,0 +1,306 @@
+package prune
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	msg "github.com/aziontech/azion-cli/messages/storage"
+	apifunc "github.com/aziontech/azion-cli/pkg/api/edge_function"
+	"github.com/aziontech/azion-cli/pkg/api/storage"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/output"
+	"github.com/aziontech/azion-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// prefixLayout is the layout of the version IDs deploys store their static files under
+const prefixLayout = "20060102150405"
+
+var (
+	deployPrefix   = regexp.MustCompile(`^\d{14}$`)
+	functionPrefix = regexp.MustCompile(`(?m)^//\s+prefix:\s*(\S+)\s*$`)
+)
+
+type PruneCmd struct {
+	F                   *cmdutil.Factory
+	GetAzionJsonContent func(confPath string) (*contracts.AzionApplicationOptions, error)
+	Now                 func() time.Time
+}
+
+var (
+	Bucket      string
+	Keep        int
+	OlderThan   time.Duration
+	DryRun      bool
+	ProjectConf string
+	FunctionID  int64
+)
+
+// Prefix is a deploy prefix of a bucket with the keys of the objects stored under it
+type Prefix struct {
+	Name       string
+	DeployedAt time.Time
+	Keys       []string
+}
+
+// Options chooses the prefixes to delete. Keep is the number of newest prefixes kept, or -1 to keep them
+// regardless of their number; prefixes deployed less than OlderThan ago are always kept.
+type Options struct {
+	Keep      int
+	OlderThan time.Duration
+	Protected map[string]bool
+	Now       time.Time
+}
+
+func NewPruneCmd(f *cmdutil.Factory) *PruneCmd {
+	return &PruneCmd{
+		F:                   f,
+		GetAzionJsonContent: utils.GetAzionJsonContent,
+		Now:                 time.Now,
+	}
+}
+
+func NewCobraCmd(prune *PruneCmd) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:           msg.PruneUsage,
+		Short:         msg.PruneShortDescription,
+		Long:          msg.PruneLongDescription,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Example: heredoc.Doc(`
+		$ azion storage prune --bucket my-bucket --keep 5
+		$ azion storage prune --bucket my-bucket --older-than 720h --dry-run
+		$ azion storage prune --bucket my-bucket --keep 3 --function-id 1234
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := Options{Keep: -1, OlderThan: OlderThan}
+			if cmd.Flags().Changed("keep") {
+				if Keep < 0 {
+					return msg.ErrorInvalidKeep
+				}
+				opts.Keep = Keep
+			}
+			return prune.Run(opts)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&Bucket, "bucket", "", msg.FlagBucket)
+	cobraCmd.Flags().IntVar(&Keep, "keep", 0, msg.FlagKeep)
+	cobraCmd.Flags().DurationVar(&OlderThan, "older-than", 0, msg.FlagOlderThan)
+	cobraCmd.Flags().BoolVar(&DryRun, "dry-run", false, msg.FlagDryRun)
+	cobraCmd.Flags().StringVar(&ProjectConf, "config-dir", "azion", msg.FlagConfigDir)
+	cobraCmd.Flags().Int64Var(&FunctionID, "function-id", 0, msg.FlagFunctionID)
+	cobraCmd.Flags().BoolP("help", "h", false, msg.PruneFlagHelp)
+	return cobraCmd
+}
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	return NewCobraCmd(NewPruneCmd(f))
+}
+
+func (cmd *PruneCmd) Run(opts Options) error {
+	ctx := context.Background()
+
+	if Bucket == "" {
+		return msg.ErrorMissingBucket
+	}
+	if opts.Keep < 0 && opts.OlderThan <= 0 {
+		return msg.ErrorMissingRule
+	}
+
+	protected, err := cmd.protectedPrefixes(ctx)
+	if err != nil {
+		return err
+	}
+	opts.Protected = protected
+	opts.Now = cmd.Now()
+
+	client := storage.NewClient(cmd.F.HttpClient, cmd.F.Config.GetString("storage_url"), cmd.F.Config.GetString("token"))
+	prefixes, err := ListPrefixes(ctx, client, Bucket)
+	if err != nil {
+		return err
+	}
+	actions := Decide(prefixes, opts)
+
+	if DryRun {
+		listOut := output.ListOutput{}
+		listOut.Columns = []string{"PREFIX", "DEPLOYED AT", "OBJECTS", "ACTION"}
+		listOut.Out = cmd.F.IOStreams.Out
+		listOut.Flags = cmd.F.Flags
+		for i, prefix := range prefixes {
+			listOut.Lines = append(listOut.Lines, []string{
+				prefix.Name,
+				prefix.DeployedAt.Format(time.RFC3339),
+				strconv.Itoa(len(prefix.Keys)),
+				actions[i],
+			})
+		}
+		return output.Print(&listOut)
+	}
+
+	msgs := []string{}
+	deleted := 0
+	for i, prefix := range prefixes {
+		if actions[i] != msg.ActionDelete {
+			continue
+		}
+		if err := Delete(ctx, client, Bucket, prefix); err != nil {
+			return err
+		}
+		deleted++
+		msgf := fmt.Sprintf(msg.PruneDeleted, len(prefix.Keys), prefix.Name)
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+		msgs = append(msgs, msgf)
+	}
+
+	msgf := fmt.Sprintf(msg.PruneSummary, deleted, Bucket)
+	if deleted == 0 {
+		msgf = fmt.Sprintf(msg.PruneNothing, Bucket)
+	}
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+	msgs = append(msgs, msgf)
+
+	outSlice := output.SliceOutput{
+		Messages: msgs,
+		GeneralOutput: output.GeneralOutput{
+			Out:   cmd.F.IOStreams.Out,
+			Flags: cmd.F.Flags,
+		},
+	}
+	return output.Print(&outSlice)
+}
+
+// protectedPrefixes finds the prefixes currently served: the one recorded in azion.json when the project uses the bucket,
+// and the ones injected into the code of the project's edge function and of --function-id. Pruning without knowing
+// any of them is refused, since it could delete the files of the live deploy.
+func (cmd *PruneCmd) protectedPrefixes(ctx context.Context) (map[string]bool, error) {
+	protected := make(map[string]bool)
+	functions := []int64{}
+	if FunctionID > 0 {
+		functions = append(functions, FunctionID)
+	}
+
+	conf, err := cmd.GetAzionJsonContent(ProjectConf)
+	if err != nil {
+		logger.Debug("No azion.json to read the prefix in use from", zap.Error(err))
+	} else if conf.Bucket == Bucket {
+		if conf.Prefix != "" {
+			protected[conf.Prefix] = true
+		}
+		if conf.Function.ID > 0 {
+			functions = append(functions, conf.Function.ID)
+		}
+	}
+
+	client := apifunc.NewClient(cmd.F.HttpClient, cmd.F.Config.GetString("api_url"), cmd.F.Config.GetString("token"))
+	for _, id := range functions {
+		function, err := client.Get(ctx, id)
+		if err != nil {
+			logger.Debug("Error while reading edge function", zap.Int64("id", id), zap.Error(err))
+			return nil, fmt.Errorf(msg.ErrorGetFunction.Error(), id, err)
+		}
+		if prefix := FunctionPrefix(function.GetCode()); prefix != "" {
+			protected[prefix] = true
+		}
+	}
+
+	if len(protected) == 0 {
+		return nil, msg.ErrorProtectedPrefix
+	}
+	return protected, nil
+}
+
+// FunctionPrefix returns the storage prefix deploy injected into the code of an edge function
+func FunctionPrefix(code string) string {
+	match := functionPrefix.FindStringSubmatch(code)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// ListPrefixes groups the objects of the bucket by deploy prefix, newest first. Objects outside a deploy prefix
+// are not returned, so they are never pruned.
+func ListPrefixes(ctx context.Context, client *storage.Client, bucket string) ([]Prefix, error) {
+	byName := make(map[string]*Prefix)
+	opts := &contracts.ListOptions{PageSize: 1000}
+	for {
+		resp, err := client.ListObject(ctx, bucket, opts)
+		if err != nil {
+			logger.Debug("Error while listing objects", zap.String("bucket", bucket), zap.Error(err))
+			return nil, fmt.Errorf(msg.ErrorListObjects.Error(), bucket, err)
+		}
+		for _, object := range resp.GetResults() {
+			name, _, found := strings.Cut(object.GetKey(), "/")
+			if !found || !deployPrefix.MatchString(name) {
+				continue
+			}
+			prefix, ok := byName[name]
+			if !ok {
+				deployedAt, err := time.ParseInLocation(prefixLayout, name, time.Local)
+				if err != nil {
+					continue
+				}
+				prefix = &Prefix{Name: name, DeployedAt: deployedAt}
+				byName[name] = prefix
+			}
+			prefix.Keys = append(prefix.Keys, object.GetKey())
+		}
+		if resp.GetContinuationToken() == "" || len(resp.GetResults()) == 0 {
+			break
+		}
+		opts.ContinuationToken = resp.GetContinuationToken()
+	}
+
+	prefixes := make([]Prefix, 0, len(byName))
+	for _, prefix := range byName {
+		prefixes = append(prefixes, *prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return prefixes[i].Name > prefixes[j].Name })
+	return prefixes, nil
+}
+
+// Decide returns the action taken on each of the prefixes, which must be sorted newest first.
+// Protected prefixes are always kept and count towards Keep.
+func Decide(prefixes []Prefix, opts Options) []string {
+	actions := make([]string, len(prefixes))
+	kept := 0
+	for i, prefix := range prefixes {
+		switch {
+		case opts.Protected[prefix.Name]:
+			actions[i] = msg.ActionInUse
+			kept++
+		case opts.Keep >= 0 && kept < opts.Keep:
+			actions[i] = msg.ActionKeep
+			kept++
+		case opts.Keep < 0 && opts.OlderThan <= 0:
+			actions[i] = msg.ActionKeep
+		case opts.OlderThan > 0 && opts.Now.Sub(prefix.DeployedAt) < opts.OlderThan:
+			actions[i] = msg.ActionTooRecent
+		default:
+			actions[i] = msg.ActionDelete
+		}
+	}
+	return actions
+}
+
+// Delete removes every object stored under the prefix
+func Delete(ctx context.Context, client *storage.Client, bucket string, prefix Prefix) error {
+	for _, key := range prefix.Keys {
+		if err := client.DeleteObject(ctx, bucket, key); err != nil && !errors.Is(err, utils.ErrorNotFound404) {
+			logger.Debug("Error while deleting object", zap.String("key", key), zap.Error(err))
+			return fmt.Errorf(msg.ErrorDeleteObject.Error(), key, err)
+		}
+	}
+	return nil
+}
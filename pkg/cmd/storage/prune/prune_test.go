@@ -0,0 +1,160 @@
+package prune
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	msg "github.com/aziontech/azion-cli/messages/storage"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+var objectsResponse = `
+{
+	"continuation_token": null,
+	"results": [
+		{"key": "20240101000000/index.html", "last_modified": "2024-01-01T00:00:00Z", "size": 120},
+		{"key": "20240101000000/main.js", "last_modified": "2024-01-01T00:00:00Z", "size": 120},
+		{"key": "20240301000000/index.html", "last_modified": "2024-03-01T00:00:00Z", "size": 120},
+		{"key": "20240201000000/index.html", "last_modified": "2024-02-01T00:00:00Z", "size": 120},
+		{"key": "assets/logo.png", "last_modified": "2023-01-01T00:00:00Z", "size": 120}
+	]
+}
+`
+
+var functionResponse = `
+{
+	"results": {
+		"id": 1337,
+		"name": "func",
+		"language": "javascript",
+		"code": "\n//---\n//storages:\n//   - name: assets\n//     bucket: bucket\n//     prefix: 20240101000000\n//---\n\naddEventListener()",
+		"json_args": {},
+		"active": true
+	},
+	"schema_version": 3
+}
+`
+
+func prefixes(names ...string) []Prefix {
+	result := []Prefix{}
+	for _, name := range names {
+		deployedAt, _ := time.ParseInLocation(prefixLayout, name, time.Local)
+		result = append(result, Prefix{Name: name, DeployedAt: deployedAt})
+	}
+	return result
+}
+
+func TestDecide(t *testing.T) {
+	now, _ := time.ParseInLocation(prefixLayout, "20240401000000", time.Local)
+	list := prefixes("20240301000000", "20240201000000", "20240101000000", "20231201000000")
+
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "keep the newest",
+			opts: Options{Keep: 2, Protected: map[string]bool{"20240301000000": true}},
+			want: []string{msg.ActionInUse, msg.ActionKeep, msg.ActionDelete, msg.ActionDelete},
+		},
+		{
+			name: "old prefix in use",
+			opts: Options{Keep: 1, Protected: map[string]bool{"20240101000000": true}},
+			want: []string{msg.ActionKeep, msg.ActionDelete, msg.ActionInUse, msg.ActionDelete},
+		},
+		{
+			name: "older than",
+			opts: Options{Keep: -1, OlderThan: 70 * 24 * time.Hour},
+			want: []string{msg.ActionTooRecent, msg.ActionTooRecent, msg.ActionDelete, msg.ActionDelete},
+		},
+		{
+			name: "keep and older than",
+			opts: Options{Keep: 1, OlderThan: 100 * 24 * time.Hour},
+			want: []string{msg.ActionKeep, msg.ActionTooRecent, msg.ActionTooRecent, msg.ActionDelete},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Now = now
+			require.Equal(t, tt.want, Decide(list, tt.opts))
+		})
+	}
+}
+
+func TestFunctionPrefix(t *testing.T) {
+	require.Equal(t, "20240101000000", FunctionPrefix("//---\n//     prefix: 20240101000000\n//---\ncode"))
+	require.Equal(t, "", FunctionPrefix("addEventListener()"))
+}
+
+func TestPrune(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	reset := func() {
+		Bucket, Keep, OlderThan, DryRun, ProjectConf, FunctionID = "", 0, 0, false, "azion", 0
+	}
+
+	t.Run("refuses to prune without the prefix in use", func(t *testing.T) {
+		defer reset()
+		Bucket = "bucket"
+
+		f, _, _ := testutils.NewFactory(&httpmock.Registry{})
+		cmd := NewPruneCmd(f)
+		cmd.GetAzionJsonContent = func(confPath string) (*contracts.AzionApplicationOptions, error) {
+			return nil, errors.New("no azion.json")
+		}
+
+		err := cmd.Run(Options{Keep: 1})
+		require.ErrorIs(t, err, msg.ErrorProtectedPrefix)
+	})
+
+	t.Run("dry-run lists the prefixes", func(t *testing.T) {
+		defer reset()
+		Bucket = "bucket"
+		DryRun = true
+
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST("GET", "edge_functions/1337"), httpmock.JSONFromString(functionResponse))
+		mock.Register(httpmock.REST("GET", "v4/storage/buckets/bucket/objects"), httpmock.JSONFromString(objectsResponse))
+
+		f, stdout, _ := testutils.NewFactory(mock)
+		cmd := NewPruneCmd(f)
+		cmd.GetAzionJsonContent = func(confPath string) (*contracts.AzionApplicationOptions, error) {
+			return &contracts.AzionApplicationOptions{Bucket: "bucket", Function: contracts.AzionJsonDataFunction{ID: 1337}}, nil
+		}
+
+		err := cmd.Run(Options{Keep: 1})
+		require.NoError(t, err)
+		mock.Verify(t)
+		require.Contains(t, stdout.String(), "20240201000000")
+		require.NotContains(t, stdout.String(), "assets")
+	})
+
+	t.Run("deletes the old prefixes", func(t *testing.T) {
+		defer reset()
+		Bucket = "bucket"
+
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST("GET", "v4/storage/buckets/bucket/objects"), httpmock.JSONFromString(objectsResponse))
+		mock.Register(httpmock.REST("DELETE", "v4/storage/buckets/bucket/objects/20240101000000/index.html"), httpmock.JSONFromString(`{"state": "executed"}`))
+		mock.Register(httpmock.REST("DELETE", "v4/storage/buckets/bucket/objects/20240101000000/main.js"), httpmock.JSONFromString(`{"state": "executed"}`))
+
+		f, stdout, _ := testutils.NewFactory(mock)
+		cmd := NewPruneCmd(f)
+		cmd.GetAzionJsonContent = func(confPath string) (*contracts.AzionApplicationOptions, error) {
+			return &contracts.AzionApplicationOptions{Bucket: "bucket", Prefix: "20240301000000"}, nil
+		}
+
+		err := cmd.Run(Options{Keep: 2})
+		require.NoError(t, err)
+		mock.Verify(t)
+		require.Contains(t, stdout.String(), "Deleted 2 objects under prefix 20240101000000")
+	})
+}
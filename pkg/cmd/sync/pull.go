@@ -0,0 +1,91 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	msg "github.com/aziontech/azion-cli/messages/sync"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
+	"go.uber.org/zap"
+)
+
+// pull adds the definitions of the remote resources azion.json tracks to the manifest, so the next deploy doesn't
+// delete them, and reports the declared resources whose local definition differs from the remote one. Local
+// definitions always win: they are reported, never overwritten. The resources deploy manages by itself, such as the
+// origin and cache setting of the project's function, are left out of the manifest.
+func (synch *SyncCmd) pull(conf *contracts.AzionApplicationOptions) error {
+	interpreter := synch.interpreter()
+	path, err := interpreter.ManifestPath()
+	if err != nil {
+		return err
+	}
+
+	declared := &contracts.Manifest{}
+	raw, err := interpreter.FileReader(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		// a project without a manifest gets one with the remote definitions
+		raw = nil
+	case err != nil:
+		return fmt.Errorf(msg.ERRORPULLMANIFEST, path, err)
+	default:
+		declared, err = interpreter.LoadManifest(path)
+		if err != nil {
+			return fmt.Errorf(msg.ERRORPULLMANIFEST, path, err)
+		}
+	}
+
+	remote, err := synch.ReadRemote(context.Background(), synch.F, conf.Application.ID)
+	if err != nil {
+		return err
+	}
+	conflicts := manifestInt.Diff(declared, conf, remote, false)
+	additions, pulled := manifestInt.Pull(declared, conf, remote)
+
+	if len(pulled) > 0 {
+		// the definitions are added to the manifest as it is written, so its formatting, comments and the
+		// environment variables it names stay in place
+		data, err := manifestInt.PatchManifest(path, raw, additions)
+		if err != nil {
+			logger.Debug("Error while adding the pulled definitions to the manifest", zap.Error(err))
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return fmt.Errorf(msg.ERRORPULLWRITE, path, err)
+		}
+		if err := synch.WriteFile(path, data, 0644); err != nil {
+			logger.Debug("Error while writing the manifest", zap.Error(err))
+			return fmt.Errorf(msg.ERRORPULLWRITE, path, err)
+		}
+		// the device groups pulled along are tracked too
+		if err := synch.WriteAzionJsonContent(conf, ProjectConf); err != nil {
+			logger.Debug("Error while writing azion.json file", zap.Error(err))
+			return err
+		}
+	}
+
+	for _, resource := range pulled {
+		synch.info(fmt.Sprintf(msg.SYNCMESSAGEPULLED, resource.Resource, resource.Name, resource.Id, filepath.Base(path)))
+	}
+	for _, conflict := range conflicts {
+		if conflict.Field == "" {
+			synch.info(fmt.Sprintf(msg.SYNCMESSAGEMISSING, conflict.Resource, conflict.Name, conflict.Id, conflict.Details))
+			continue
+		}
+		synch.info(fmt.Sprintf(msg.SYNCMESSAGECONFLICT,
+			conflict.Resource, conflict.Name, conflict.Field, conflict.Manifest, conflict.Remote))
+	}
+	if len(pulled) == 0 && len(conflicts) == 0 {
+		synch.info(msg.SYNCMESSAGEPULLNOTHING)
+	}
+	return nil
+}
+
+func (synch *SyncCmd) info(message string) {
+	logger.FInfoFlags(synch.Io.Out, message, synch.F.Format, synch.F.Out)
+}
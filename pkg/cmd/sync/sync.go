@@ -1,12 +1,16 @@
 package sync
 
 import (
+	"context"
+	"os"
+
 	"github.com/MakeNowJust/heredoc"
 	msg "github.com/aziontech/azion-cli/messages/sync"
 	"github.com/aziontech/azion-cli/pkg/cmdutil"
 	"github.com/aziontech/azion-cli/pkg/contracts"
 	"github.com/aziontech/azion-cli/pkg/iostreams"
 	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
 	"github.com/aziontech/azion-cli/utils"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -14,6 +18,7 @@ import (
 
 var (
 	ProjectConf string
+	Pull        bool
 )
 
 type SyncCmd struct {
@@ -23,6 +28,9 @@ type SyncCmd struct {
 	F                     *cmdutil.Factory
 	SyncResources         func(f *cmdutil.Factory, info contracts.SyncOpts, synch *SyncCmd) error
 	EnvPath               string
+	GetWorkDir            func() (string, error)
+	WriteFile             func(filename string, data []byte, perm os.FileMode) error
+	ReadRemote            func(ctx context.Context, f *cmdutil.Factory, applicationID int64) (*manifestInt.Remote, error)
 }
 
 func NewSync(f *cmdutil.Factory) *SyncCmd {
@@ -32,6 +40,9 @@ func NewSync(f *cmdutil.Factory) *SyncCmd {
 		GetAzionJsonContent:   utils.GetAzionJsonContent,
 		WriteAzionJsonContent: utils.WriteAzionJsonContent,
 		SyncResources:         SyncLocalResources,
+		GetWorkDir:            utils.GetWorkingDir,
+		WriteFile:             os.WriteFile,
+		ReadRemote:            manifestInt.ReadRemote,
 	}
 }
 
@@ -45,6 +56,7 @@ func NewCmd(f *cmdutil.Factory) *cobra.Command {
 		SilenceErrors: true,
 		Example: heredoc.Doc(`       
         $ azion sync
+        $ azion sync --pull
         $ azion sync --help
         `),
 		RunE: func(_ *cobra.Command, _ []string) error {
@@ -54,6 +66,7 @@ func NewCmd(f *cmdutil.Factory) *cobra.Command {
 	syncCmd.Flags().BoolP("help", "h", false, msg.HELPFLAG)
 	syncCmd.Flags().StringVar(&ProjectConf, "config-dir", "azion", msg.CONFDIRFLAG)
 	syncCmd.Flags().StringVar(&cmdFactory.EnvPath, "env", ".edge/.env", msg.ENVFLAG)
+	syncCmd.Flags().BoolVar(&Pull, "pull", false, msg.PULLFLAG)
 	return syncCmd
 }
 
@@ -67,8 +80,9 @@ func Sync(cmdFac *SyncCmd) error {
 
 	ruleIds := make(map[string]contracts.RuleIdsStruct)
 	for _, ruleConf := range conf.RulesEngine.Rules {
-		ruleIds[ruleConf.Name] = contracts.RuleIdsStruct{
+		ruleIds[manifestInt.RuleKey(ruleConf.Phase, ruleConf.Name)] = contracts.RuleIdsStruct{
 			Id:    ruleConf.Id,
+			Name:  ruleConf.Name,
 			Phase: ruleConf.Phase,
 		}
 	}
@@ -103,5 +117,9 @@ func Sync(cmdFac *SyncCmd) error {
 		return err
 	}
 
+	if Pull {
+		return cmdFac.pull(info.Conf)
+	}
+
 	return nil
 }
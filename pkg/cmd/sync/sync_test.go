@@ -1,8 +1,12 @@
 package sync
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	msg "github.com/aziontech/azion-cli/messages/sync"
@@ -10,7 +14,9 @@ import (
 	"github.com/aziontech/azion-cli/pkg/contracts"
 	"github.com/aziontech/azion-cli/pkg/httpmock"
 	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
 	"github.com/aziontech/azion-cli/pkg/testutils"
+	sdk "github.com/aziontech/azionapi-go-sdk/edgeapplications"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zapcore"
@@ -95,3 +101,101 @@ func TestSync(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncRules(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+	rules := func(results string) httpmock.Responder {
+		return httpmock.JSONFromString(`{"count": 1, "total_pages": 1, "schema_version": 3, "links": {}, "results": [` + results + `]}`)
+	}
+
+	mock := &httpmock.Registry{}
+	mock.Register(
+		httpmock.REST("GET", "edge_applications/1673635841/rules_engine/request/rules"),
+		rules(`{"id": 1, "name": "Default Rule", "phase": "default", "criteria": [], "is_active": true, "order": 0},
+			{"id": 10, "name": "api", "phase": "request", "criteria": [], "is_active": true, "order": 1}`),
+	)
+	mock.Register(
+		httpmock.REST("GET", "edge_applications/1673635841/rules_engine/response/rules"),
+		rules(`{"id": 21, "name": "api", "phase": "response", "criteria": [], "is_active": true, "order": 1},
+			{"id": 20, "name": "enable gzip", "phase": "response", "criteria": [], "is_active": true, "order": 2}`),
+	)
+
+	f, stdout, _ := testutils.NewFactory(mock)
+	syncCmd := NewSync(f)
+	syncCmd.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+		return nil
+	}
+
+	conf := &contracts.AzionApplicationOptions{
+		Application: contracts.AzionJsonDataApplication{ID: 1673635841},
+		RulesEngine: contracts.AzionJsonDataRulesEngine{
+			Rules: []contracts.AzionJsonDataRules{{Id: 10, Name: "api"}},
+		},
+	}
+	info := contracts.SyncOpts{
+		RuleIds: map[string]contracts.RuleIdsStruct{manifestInt.RuleKey("", "api"): {Id: 10, Name: "api"}},
+		Conf:    conf,
+	}
+
+	err := syncCmd.syncRules(info, f)
+	require.NoError(t, err)
+	require.Equal(t, []contracts.AzionJsonDataRules{
+		{Id: 10, Name: "api", Phase: "request"},
+		// a rule of another phase may share the name of a tracked one
+		{Id: 21, Name: "api", Phase: "response"},
+		{Id: 20, Name: "enable gzip", Phase: "response"},
+	}, conf.RulesEngine.Rules)
+	require.Equal(t, contracts.RuleIdsStruct{Id: 10, Name: "api", Phase: "request"}, info.RuleIds[manifestInt.RuleKey("request", "api")])
+	require.Contains(t, stdout.String(), fmt.Sprintf(msg.SYNCMESSAGERULE, "enable gzip", "response"))
+	mock.Verify(t)
+}
+
+func TestSyncPull(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+	t.Setenv("PULL_HOST", "api.example.com")
+
+	workDir := t.TempDir()
+	manifestPath := filepath.Join(workDir, ".edge", "manifest.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(manifestPath), os.ModePerm))
+	declared := `origin:
+  - name: api # served by the API team
+    origin_type: single_origin
+    addresses:
+      - address: ${PULL_HOST}
+`
+	require.NoError(t, os.WriteFile(manifestPath, []byte(declared), 0644))
+
+	f, stdout, _ := testutils.NewFactory(&httpmock.Registry{})
+	syncCmd := NewSync(f)
+	syncCmd.EnvPath = ""
+	syncCmd.GetWorkDir = func() (string, error) { return workDir, nil }
+	syncCmd.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+		return nil
+	}
+	syncCmd.ReadRemote = func(ctx context.Context, f *cmdutil.Factory, applicationID int64) (*manifestInt.Remote, error) {
+		require.Equal(t, int64(1673635841), applicationID)
+		return &manifestInt.Remote{
+			Origins: []manifestInt.RemoteOrigin{
+				{ID: 42, Origin: contracts.Origin{Name: "api", OriginType: "single_origin",
+					Addresses: []sdk.CreateOriginsRequestAddresses{{Address: "old.example.com"}}}},
+				{ID: 43, Origin: contracts.Origin{Name: "console-origin", OriginType: "single_origin",
+					Addresses: []sdk.CreateOriginsRequestAddresses{{Address: "console.example.com"}}}},
+			},
+		}, nil
+	}
+
+	err := syncCmd.pull(&contracts.AzionApplicationOptions{
+		Application: contracts.AzionJsonDataApplication{ID: 1673635841},
+		Origin:      []contracts.AzionJsonDataOrigin{{OriginId: 42, Name: "api"}, {OriginId: 43, Name: "console-origin"}},
+	})
+	require.NoError(t, err)
+	require.Contains(t, stdout.String(), fmt.Sprintf(msg.SYNCMESSAGEPULLED, "origin", "console-origin", "43", "manifest.yaml"))
+	require.Contains(t, stdout.String(), fmt.Sprintf(msg.SYNCMESSAGECONFLICT,
+		"origin", "api", "addresses", `[{"address":"api.example.com"}]`, `[{"address":"old.example.com"}]`))
+
+	written, err := os.ReadFile(manifestPath)
+	require.NoError(t, err)
+	// the declared origin is kept as written and the pulled one follows it
+	require.True(t, strings.HasPrefix(string(written), declared))
+	require.Contains(t, string(written), "console.example.com")
+}
@@ -2,7 +2,9 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 
 	msg "github.com/aziontech/azion-cli/messages/sync"
 	edgeApp "github.com/aziontech/azion-cli/pkg/api/edge_applications"
@@ -11,6 +13,7 @@ import (
 	"github.com/aziontech/azion-cli/pkg/cmdutil"
 	"github.com/aziontech/azion-cli/pkg/contracts"
 	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
 )
@@ -107,28 +110,60 @@ func (synch *SyncCmd) syncCache(info contracts.SyncOpts, f *cmdutil.Factory) err
 
 func (synch *SyncCmd) syncRules(info contracts.SyncOpts, f *cmdutil.Factory) error {
 	client := edgeApp.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
-	resp, err := client.ListRulesEngine(context.Background(), opts, info.Conf.Application.ID, "request")
-	if err != nil {
-		return err
+	for _, phase := range edgeApp.RulesEnginePhases {
+		rules, err := manifestInt.ListRules(context.Background(), client, info.Conf.Application.ID, phase)
+		if err != nil {
+			return err
+		}
+
+		for _, rule := range rules {
+			if rule.Name == "Default Rule" {
+				continue
+			}
+			if r := info.RuleIds[manifestInt.RuleKey(phase, rule.Name)]; r.Id > 0 {
+				continue
+			}
+			// rules tracked before their phase was recorded only need it filled in
+			if r := info.RuleIds[manifestInt.RuleKey("", rule.Name)]; r.Id > 0 && r.Id == rule.GetId() {
+				if err := synch.recordRulePhase(info, rule.GetId(), phase); err != nil {
+					return err
+				}
+				continue
+			}
+			newRule := contracts.AzionJsonDataRules{
+				Id:    rule.GetId(),
+				Name:  rule.GetName(),
+				Phase: phase,
+			}
+			info.Conf.RulesEngine.Rules = append(info.Conf.RulesEngine.Rules, newRule)
+			err := synch.WriteAzionJsonContent(info.Conf, ProjectConf)
+			if err != nil {
+				logger.Debug("Error while writing azion.json file", zap.Error(err))
+				return err
+			}
+			logger.FInfoFlags(
+				synch.Io.Out, fmt.Sprintf(msg.SYNCMESSAGERULE, rule.Name, phase), synch.F.Format, synch.F.Out)
+		}
 	}
+	return nil
+}
 
-	for _, rule := range resp.Results {
-		if r := info.RuleIds[rule.Name]; r.Id > 0 || rule.Name == "Default Rule" {
-			// if remote rule is also on local environment, no action is needed
+// recordRulePhase fills in the phase of a rule azion.json already tracks
+func (synch *SyncCmd) recordRulePhase(info contracts.SyncOpts, id int64, phase string) error {
+	for i, rule := range info.Conf.RulesEngine.Rules {
+		if rule.Id != id {
 			continue
 		}
-		newRule := contracts.AzionJsonDataRules{
-			Id:   rule.GetId(),
-			Name: rule.GetName(),
-		}
-		info.Conf.RulesEngine.Rules = append(info.Conf.RulesEngine.Rules, newRule)
+		info.Conf.RulesEngine.Rules[i].Phase = phase
+		delete(info.RuleIds, manifestInt.RuleKey("", rule.Name))
+		info.RuleIds[manifestInt.RuleKey(phase, rule.Name)] = contracts.RuleIdsStruct{Id: id, Name: rule.Name, Phase: phase}
 		err := synch.WriteAzionJsonContent(info.Conf, ProjectConf)
 		if err != nil {
 			logger.Debug("Error while writing azion.json file", zap.Error(err))
 			return err
 		}
 		logger.FInfoFlags(
-			synch.Io.Out, fmt.Sprintf(msg.SYNCMESSAGERULE, rule.Name), synch.F.Format, synch.F.Out)
+			synch.Io.Out, fmt.Sprintf(msg.SYNCMESSAGERULEPHASE, phase, rule.Name), synch.F.Format, synch.F.Out)
 	}
 	return nil
 }
@@ -154,6 +189,15 @@ func (synch *SyncCmd) syncEnv(f *cmdutil.Factory) error {
 		}
 	}
 
+	// deploy converges the variables declared in the manifest, secrets included
+	declared, err := synch.manifestVariables()
+	if err != nil {
+		return err
+	}
+	for key := range declared {
+		delete(envs, key)
+	}
+
 	for key, value := range envs {
 		createReq := &varApi.Request{}
 		createReq.Key = key
@@ -168,3 +212,34 @@ func (synch *SyncCmd) syncEnv(f *cmdutil.Factory) error {
 	}
 	return nil
 }
+
+// interpreter reads the manifest of the project with its .env file
+func (synch *SyncCmd) interpreter() *manifestInt.ManifestInterpreter {
+	interpreter := manifestInt.NewManifestInterpreter()
+	interpreter.EnvPath = synch.EnvPath
+	interpreter.GetWorkDir = synch.GetWorkDir
+	return interpreter
+}
+
+// manifestVariables returns the keys of the variables declared in the manifest of the project, if it has one
+func (synch *SyncCmd) manifestVariables() (map[string]bool, error) {
+	keys := make(map[string]bool)
+	interpreter := synch.interpreter()
+	path, err := interpreter.ManifestPath()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := interpreter.LoadManifest(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return keys, nil
+		}
+		logger.Debug("Error while reading the variables of the manifest", zap.Error(err))
+		return nil, err
+	}
+	for _, variable := range manifest.Variables {
+		keys[variable.Key] = true
+	}
+	return keys, nil
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc"
 	msg "github.com/aziontech/azion-cli/messages/update/rules_engine"
+	apiEdgeApplications "github.com/aziontech/azion-cli/pkg/api/edge_applications"
 	api "github.com/aziontech/azion-cli/pkg/api/rules_engine"
 	"github.com/aziontech/azion-cli/pkg/cmdutil"
 	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/manifest"
 	"github.com/aziontech/azion-cli/pkg/output"
 	"github.com/aziontech/azion-cli/utils"
 	sdk "github.com/aziontech/azionapi-go-sdk/edgeapplications"
@@ -22,6 +25,9 @@ type Fields struct {
 	RuleID        int64
 	Phase         string
 	Path          string
+	Reorder       bool
+	Order         []int64
+	DryRun        bool
 }
 
 func NewCmd(f *cmdutil.Factory) *cobra.Command {
@@ -36,8 +42,13 @@ func NewCmd(f *cmdutil.Factory) *cobra.Command {
 		Example: heredoc.Doc(`
 		$ azion update rules-engine -h"
 		$ azion update rules-engine --rule-id 1234 --application-id 1673635839 --phase request --file ruleengine.json"
+		$ azion update rules-engine --reorder --application-id 1673635839 --phase request --order 4321,1234 --dry-run"
         `),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if fields.Reorder {
+				return reorder(cmd, f, fields)
+			}
+
 			if err := validateUserInput(cmd, fields); err != nil {
 				return err
 			}
@@ -80,10 +91,83 @@ func NewCmd(f *cmdutil.Factory) *cobra.Command {
 	flags.Int64Var(&fields.RuleID, "rule-id", 0, msg.FlagRulesEngineID)
 	flags.StringVar(&fields.Phase, "phase", "", msg.RulesEnginePhase)
 	flags.StringVar(&fields.Path, "file", "", msg.FlagFile)
+	flags.BoolVar(&fields.Reorder, "reorder", false, msg.FlagReorder)
+	flags.Int64SliceVar(&fields.Order, "order", []int64{}, msg.FlagOrder)
+	flags.BoolVar(&fields.DryRun, "dry-run", false, msg.FlagDryRun)
 	flags.BoolP("help", "h", false, msg.FlagHelp)
 	return cmd
 }
 
+// reorder moves the rules given with --order to the top of the phase, in that order, followed by the other rules in
+// the order they already run. The moves are shown before they are applied.
+func reorder(cmd *cobra.Command, f *cmdutil.Factory, fields *Fields) error {
+	if err := validateReorderInput(cmd, f, fields); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	client := apiEdgeApplications.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+	rules, err := manifest.ListRules(ctx, client, fields.ApplicationID, fields.Phase)
+	if err != nil {
+		return fmt.Errorf(msg.ErrorReorder.Error(), err)
+	}
+	if unknown := manifest.UnknownRules(rules, fields.Order); len(unknown) > 0 {
+		ids := make([]string, len(unknown))
+		for i, id := range unknown {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		return fmt.Errorf(msg.ErrorReorderUnknown.Error(), strings.Join(ids, ", "), fields.Phase)
+	}
+	changes := manifest.ReorderRules(rules, fields.Order)
+
+	if fields.DryRun {
+		listOut := output.ListOutput{}
+		listOut.Columns = []string{"ID", "NAME", "FROM", "TO"}
+		listOut.Out = f.IOStreams.Out
+		listOut.Flags = f.Flags
+		for _, change := range changes {
+			listOut.Lines = append(listOut.Lines, []string{
+				strconv.FormatInt(change.Id, 10),
+				change.Name,
+				strconv.FormatInt(change.From, 10),
+				strconv.FormatInt(change.To, 10),
+			})
+		}
+		return output.Print(&listOut)
+	}
+
+	if len(changes) == 0 {
+		reorderOut := output.GeneralOutput{
+			Msg:   fmt.Sprintf(msg.OutputReorderNothing, fields.Phase),
+			Out:   f.IOStreams.Out,
+			Flags: f.Flags,
+		}
+		return output.Print(&reorderOut)
+	}
+
+	for _, change := range changes {
+		msgf := fmt.Sprintf(msg.ReorderPreview, change.Name, change.Id, change.From, change.To)
+		logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+	}
+	if f.NonInteractive && !f.GlobalFlagAll {
+		return &utils.NonInteractiveError{Flag: "--yes"}
+	}
+	if !utils.Confirm(f.GlobalFlagAll, msg.AskReorder, false) {
+		return msg.ErrorReorderCanceled
+	}
+
+	if err := manifest.ApplyRuleOrder(ctx, client, fields.ApplicationID, changes); err != nil {
+		return err
+	}
+
+	reorderOut := output.GeneralOutput{
+		Msg:   fmt.Sprintf(msg.OutputReorderSuccess, len(changes), fields.Phase),
+		Out:   f.IOStreams.Out,
+		Flags: f.Flags,
+	}
+	return output.Print(&reorderOut)
+}
+
 func validateRequest(request api.UpdateRulesEngineRequest) error {
 	if request.GetCriteria() != nil {
 		for _, itemCriteria := range request.GetCriteria() {
@@ -234,3 +318,73 @@ func validateUserInput(cmd *cobra.Command, fields *Fields) error {
 
 	return nil
 }
+
+func validateReorderInput(cmd *cobra.Command, f *cmdutil.Factory, fields *Fields) error {
+	if !cmd.Flags().Changed("application-id") {
+		if f.NonInteractive {
+			return &utils.NonInteractiveError{Flag: "--application-id"}
+		}
+		answer, err := utils.AskInput(msg.AskInputApplicationID)
+		if err != nil {
+			return err
+		}
+
+		num, err := strconv.ParseInt(answer, 10, 64)
+		if err != nil {
+			logger.Debug("Error while converting answer to int64", zap.Error(err))
+			return msg.ErrorConvertApplicationID
+		}
+
+		fields.ApplicationID = num
+	}
+
+	if !cmd.Flags().Changed("phase") {
+		if f.NonInteractive {
+			return &utils.NonInteractiveError{Flag: "--phase"}
+		}
+		answer, err := utils.AskInput(msg.AskInputPhase)
+		if err != nil {
+			return err
+		}
+
+		fields.Phase = answer
+	}
+
+	if fields.Phase != "request" && fields.Phase != "response" {
+		return msg.ErrorReorderPhase
+	}
+
+	if !cmd.Flags().Changed("order") {
+		if f.NonInteractive {
+			return &utils.NonInteractiveError{Flag: "--order"}
+		}
+		answer, err := utils.AskInput(msg.AskInputOrder)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range strings.Split(answer, ",") {
+			num, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+			if err != nil {
+				logger.Debug("Error while converting answer to int64", zap.Error(err))
+				return msg.ErrorConvertRulesID
+			}
+			fields.Order = append(fields.Order, num)
+		}
+	}
+
+	// an empty order would leave every rule where it is
+	if len(fields.Order) == 0 {
+		return msg.ErrorReorderOrder
+	}
+
+	given := make(map[int64]bool)
+	for _, id := range fields.Order {
+		if given[id] {
+			return fmt.Errorf(msg.ErrorReorderDuplicate.Error(), id)
+		}
+		given[id] = true
+	}
+
+	return nil
+}
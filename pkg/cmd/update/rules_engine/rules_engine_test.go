@@ -58,4 +58,105 @@ func TestUpdate(t *testing.T) {
 		err := cmd.Execute()
 		require.ErrorIs(t, err, utils.ErrorUnmarshalReader)
 	})
+
+	t.Run("reorder", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+		mock.Register(
+			httpmock.REST(http.MethodGet, "edge_applications/1673635839/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+		mock.Register(
+			httpmock.REST(http.MethodPatch, "edge_applications/1673635839/rules_engine/request/rules/4321"),
+			httpmock.JSONFromFile("./fixtures/response.json"),
+		)
+		mock.Register(
+			httpmock.REST(http.MethodPatch, "edge_applications/1673635839/rules_engine/request/rules/1234"),
+			httpmock.JSONFromFile("./fixtures/response.json"),
+		)
+
+		f, stdout, _ := testutils.NewFactory(mock)
+		f.GlobalFlagAll = true
+		cmd := NewCmd(f)
+
+		cmd.SetArgs([]string{
+			"--application-id", "1673635839",
+			"--phase", "request",
+			"--reorder",
+			"--order", "4321",
+		})
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "Rule redirects (ID 4321) will move from position 2 to 1")
+		require.Contains(t, stdout.String(), "Reordered 2 rules of the request phase")
+		mock.Verify(t)
+	})
+
+	t.Run("reorder dry-run", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+		mock.Register(
+			httpmock.REST(http.MethodGet, "edge_applications/1673635839/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+
+		f, stdout, _ := testutils.NewFactory(mock)
+		cmd := NewCmd(f)
+
+		cmd.SetArgs([]string{
+			"--application-id", "1673635839",
+			"--phase", "request",
+			"--reorder",
+			"--order", "4321",
+			"--dry-run",
+		})
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "redirects")
+		mock.Verify(t)
+	})
+
+	t.Run("reorder unknown rules", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+		mock.Register(
+			httpmock.REST(http.MethodGet, "edge_applications/1673635839/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+
+		f, _, _ := testutils.NewFactory(mock)
+		f.GlobalFlagAll = true
+		cmd := NewCmd(f)
+
+		cmd.SetArgs([]string{"--application-id", "1673635839", "--phase", "request", "--reorder", "--order", "4321,404,1"})
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "The rules 404, 1 given with '--order'")
+		require.Len(t, mock.Requests, 1)
+	})
+
+	t.Run("reorder duplicated rules", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+
+		f, _, _ := testutils.NewFactory(mock)
+		cmd := NewCmd(f)
+
+		cmd.SetArgs([]string{"--application-id", "1673635839", "--phase", "request", "--reorder", "--order", "4321,1234,4321"})
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "The rule 4321 is given more than once")
+		require.Empty(t, mock.Requests)
+	})
+
+	t.Run("reorder requires --order", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+
+		f, _, _ := testutils.NewFactory(mock)
+		f.NonInteractive = true
+		cmd := NewCmd(f)
+
+		cmd.SetArgs([]string{"--application-id", "1673635839", "--phase", "request", "--reorder"})
+		err := cmd.Execute()
+		var nonInteractive *utils.NonInteractiveError
+		require.ErrorAs(t, err, &nonInteractive)
+		require.Equal(t, "--order", nonInteractive.Flag)
+		require.Empty(t, mock.Requests)
+	})
 }
@@ -0,0 +1,247 @@
+package pull
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	msg "github.com/aziontech/azion-cli/messages/variables"
+	api "github.com/aziontech/azion-cli/pkg/api/variables"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/output"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+type PullCmd struct {
+	F         *cmdutil.Factory
+	WriteFile func(filename string, data []byte, perm os.FileMode) error
+}
+
+var (
+	Out    string
+	DryRun bool
+)
+
+// Change is what pulling does to a key of the .env file
+type Change struct {
+	Key    string
+	Action string
+}
+
+func NewPullCmd(f *cmdutil.Factory) *PullCmd {
+	return &PullCmd{
+		F:         f,
+		WriteFile: os.WriteFile,
+	}
+}
+
+func NewCobraCmd(pull *PullCmd) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:           msg.PullUsage,
+		Short:         msg.PullShortDescription,
+		Long:          msg.PullLongDescription,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Example: heredoc.Doc(`
+		$ azion variables pull
+		$ azion variables pull --out .edge/.env.production
+		$ azion variables pull --dry-run
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pull.Run()
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&Out, "out", filepath.Join(".edge", ".env"), msg.PullFlagOut)
+	cobraCmd.Flags().BoolVar(&DryRun, "dry-run", false, msg.PullFlagDryRun)
+	cobraCmd.Flags().BoolP("help", "h", false, msg.PullHelpFlag)
+	return cobraCmd
+}
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	return NewCobraCmd(NewPullCmd(f))
+}
+
+func (cmd *PullCmd) Run() error {
+	client := api.NewClient(cmd.F.HttpClient, cmd.F.Config.GetString("api_url"), cmd.F.Config.GetString("token"))
+	remote, err := client.List(context.Background())
+	if err != nil {
+		logger.Debug("Error while listing variables", zap.Error(err))
+		return fmt.Errorf(msg.ErrorListVariables.Error(), err)
+	}
+
+	raw, err := os.ReadFile(Out)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Debug("Error while reading the .env file", zap.Error(err))
+		return fmt.Errorf(msg.ErrorReadEnvFile.Error(), Out, err)
+	}
+	local, err := godotenv.Unmarshal(string(raw))
+	if err != nil {
+		logger.Debug("Error while reading the .env file", zap.Error(err))
+		return fmt.Errorf(msg.ErrorReadEnvFile.Error(), Out, err)
+	}
+
+	// the values of secrets can't be read, so the ones the file has are kept and the others are left out
+	values := make(map[string]string)
+	secrets := []string{}
+	for _, variable := range remote {
+		if !variable.GetSecret() {
+			values[variable.GetKey()] = variable.GetValue()
+		} else if _, ok := local[variable.GetKey()]; !ok {
+			secrets = append(secrets, variable.GetKey())
+		}
+	}
+	sort.Strings(secrets)
+	changes := Diff(local, values, secrets)
+
+	if DryRun {
+		listOut := output.ListOutput{}
+		listOut.Columns = []string{"KEY", "ACTION"}
+		listOut.Out = cmd.F.IOStreams.Out
+		listOut.Flags = cmd.F.Flags
+		for _, change := range changes {
+			listOut.Lines = append(listOut.Lines, []string{change.Key, change.Action})
+		}
+		return output.Print(&listOut)
+	}
+
+	content, err := Merge(string(raw), local, values, secrets)
+	if err != nil {
+		return fmt.Errorf(msg.ErrorWriteEnvFile.Error(), Out, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(Out), os.ModePerm); err != nil {
+		return fmt.Errorf(msg.ErrorWriteEnvFile.Error(), Out, err)
+	}
+	if err := cmd.WriteFile(Out, []byte(content), 0600); err != nil {
+		logger.Debug("Error while writing the .env file", zap.Error(err))
+		return fmt.Errorf(msg.ErrorWriteEnvFile.Error(), Out, err)
+	}
+
+	pullOut := output.GeneralOutput{
+		Msg:   fmt.Sprintf(msg.PullOutputSuccess, len(values), Out, len(secrets)),
+		Out:   cmd.F.IOStreams.Out,
+		Flags: cmd.F.Flags,
+	}
+	return output.Print(&pullOut)
+}
+
+// Diff compares the keys of the .env file with the values of the remote variables. Secrets without a value are
+// written as comments, and keys the remote variables don't have are kept in the file as they are.
+func Diff(local, remote map[string]string, secrets []string) []Change {
+	changes := []Change{}
+	keys := make([]string, 0, len(remote))
+	for key := range remote {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, ok := local[key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Key: key, Action: msg.ActionAdd})
+		case value != remote[key]:
+			changes = append(changes, Change{Key: key, Action: msg.ActionUpdate})
+		}
+	}
+
+	for _, key := range secrets {
+		changes = append(changes, Change{Key: key, Action: msg.ActionSecret})
+	}
+
+	kept := []string{}
+	for key := range local {
+		if _, ok := remote[key]; !ok {
+			kept = append(kept, key)
+		}
+	}
+	sort.Strings(kept)
+	for _, key := range kept {
+		changes = append(changes, Change{Key: key, Action: msg.ActionLocal})
+	}
+	return changes
+}
+
+// envLine matches the lines of a .env file that set a key, along with what comes before the key and the quote the
+// value opens
+var envLine = regexp.MustCompile(`^(\s*(?:export\s+)?)([A-Za-z_][A-Za-z0-9_.-]*)\s*[=:]\s*(["'` + "`" + `]?)`)
+
+// Merge writes the values of the remote variables into the content of the .env file. The keys whose value changed are
+// rewritten where they are; comments, blank lines and the keys the remote variables don't have are kept as they are.
+// Keys the file doesn't have yet are added at its end, followed by the secrets without a value as comments.
+func Merge(content string, local, values map[string]string, secrets []string) (string, error) {
+	lines := strings.SplitAfter(content, "\n")
+	merged := strings.Builder{}
+	for i := 0; i < len(lines); i++ {
+		match := envLine.FindStringSubmatch(lines[i])
+		if match == nil {
+			merged.WriteString(lines[i])
+			continue
+		}
+		value, ok := values[match[2]]
+		if !ok || local[match[2]] == value {
+			merged.WriteString(lines[i])
+			continue
+		}
+
+		// a quoted value may go on up to the line that closes it
+		if quote := match[3]; quote != "" && !strings.Contains(lines[i][len(match[0]):], quote) {
+			for i+1 < len(lines) && !strings.Contains(lines[i+1], quote) {
+				i++
+			}
+			i++
+		}
+		line, err := godotenv.Marshal(map[string]string{match[2]: value})
+		if err != nil {
+			return "", err
+		}
+		merged.WriteString(match[1] + line + "\n")
+	}
+
+	added := make(map[string]string)
+	for key, value := range values {
+		if _, ok := local[key]; !ok {
+			added[key] = value
+		}
+	}
+	appended := []string{}
+	if len(added) > 0 {
+		lines, err := godotenv.Marshal(added)
+		if err != nil {
+			return "", err
+		}
+		appended = append(appended, lines)
+	}
+
+	// secrets are kept as comments, so the file tells they exist without holding a value
+	commented := []string{}
+	for _, key := range secrets {
+		if !regexp.MustCompile(`(?m)^#\s*` + regexp.QuoteMeta(key) + `=`).MatchString(content) {
+			commented = append(commented, fmt.Sprintf("# %s=%s", key, api.SecretMask))
+		}
+	}
+	if len(commented) > 0 {
+		header := msg.PullSecretsComment
+		if strings.Contains(content, header) {
+			header = ""
+		}
+		appended = append(appended, strings.TrimPrefix(header+"\n"+strings.Join(commented, "\n"), "\n"))
+	}
+
+	result := merged.String()
+	if len(appended) == 0 {
+		return result, nil
+	}
+	if result != "" {
+		result = strings.TrimRight(result, "\n") + "\n\n"
+	}
+	return result + strings.Join(appended, "\n\n") + "\n", nil
+}
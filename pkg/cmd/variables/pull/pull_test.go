@@ -0,0 +1,132 @@
+package pull
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	msg "github.com/aziontech/azion-cli/messages/variables"
+	api "github.com/aziontech/azion-cli/pkg/api/variables"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func variable(uuid, key, value string, secret bool) string {
+	return fmt.Sprintf(`{"uuid": "%s", "key": "%s", "value": "%s", "secret": %t, "last_editor": "user@azion.com",
+		"created_at": "2024-06-13T13:17:13.145625Z", "updated_at": "2024-06-13T13:17:13.145666Z"}`, uuid, key, value, secret)
+}
+
+var remoteVariables = fmt.Sprintf(`[%s, %s, %s]`,
+	variable("uuid-api", "API_URL", "https://api.example.com", false),
+	variable("uuid-token", "TOKEN", "", true),
+	variable("uuid-signing", "SIGNING_KEY", "", true),
+)
+
+func TestDiff(t *testing.T) {
+	local := map[string]string{"API_URL": "https://old.example.com", "TOKEN": "abc", "OLD": "1"}
+	remote := map[string]string{"API_URL": "https://api.example.com", "TOKEN": "abc", "NEW": "2"}
+
+	require.Equal(t, []Change{
+		{Key: "API_URL", Action: msg.ActionUpdate},
+		{Key: "NEW", Action: msg.ActionAdd},
+		{Key: "SIGNING_KEY", Action: msg.ActionSecret},
+		{Key: "OLD", Action: msg.ActionLocal},
+	}, Diff(local, remote, []string{"SIGNING_KEY"}))
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		values   map[string]string
+		secrets  []string
+		expected string
+	}{
+		{
+			name:     "new file",
+			values:   map[string]string{"API_URL": "https://api.example.com", "PORT": "8080"},
+			secrets:  []string{"TOKEN"},
+			expected: fmt.Sprintf("API_URL=\"https://api.example.com\"\nPORT=8080\n\n%s\n# TOKEN=%s\n", msg.PullSecretsComment, api.SecretMask),
+		},
+		{
+			name:     "changed values are rewritten where they are",
+			content:  "# endpoints\nexport API_URL=https://old.example.com # staging\nCERT=\"-----BEGIN\nold\n-----END\"\nLOCAL=1\n",
+			values:   map[string]string{"API_URL": "https://api.example.com", "CERT": "new"},
+			expected: "# endpoints\nexport API_URL=\"https://api.example.com\"\nCERT=\"new\"\nLOCAL=1\n",
+		},
+		{
+			name:     "unchanged values and commented secrets are kept",
+			content:  fmt.Sprintf("PORT=\"8080\"\n\n%s\n# TOKEN=%s\n", msg.PullSecretsComment, api.SecretMask),
+			values:   map[string]string{"PORT": "8080", "NEW": "1"},
+			secrets:  []string{"TOKEN"},
+			expected: fmt.Sprintf("PORT=\"8080\"\n\n%s\n# TOKEN=%s\n\nNEW=1\n", msg.PullSecretsComment, api.SecretMask),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local, err := godotenv.Unmarshal(tt.content)
+			require.NoError(t, err)
+			merged, err := Merge(tt.content, local, tt.values, tt.secrets)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, merged)
+		})
+	}
+}
+
+func TestPull(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	t.Run("pull into a .env file", func(t *testing.T) {
+		Out, DryRun = filepath.Join(t.TempDir(), ".edge", ".env"), false
+		require.NoError(t, os.MkdirAll(filepath.Dir(Out), os.ModePerm))
+		require.NoError(t, os.WriteFile(Out, []byte("# local settings\nTOKEN=abc\nAPI_URL=http://localhost\nOLD=1\n"), 0600))
+
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST(http.MethodGet, "variables"), httpmock.JSONFromString(remoteVariables))
+
+		f, stdout, _ := testutils.NewFactory(mock)
+		require.NoError(t, NewPullCmd(f).Run())
+		mock.Verify(t)
+
+		content, err := os.ReadFile(Out)
+		require.NoError(t, err)
+		// the secret and the key only the file has are kept along with the comment
+		require.Equal(t, fmt.Sprintf("# local settings\nTOKEN=abc\nAPI_URL=\"https://api.example.com\"\nOLD=1\n\n%s\n# SIGNING_KEY=%s\n",
+			msg.PullSecretsComment, api.SecretMask), string(content))
+		require.Contains(t, stdout.String(), fmt.Sprintf(msg.PullOutputSuccess, 1, Out, 1))
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		Out, DryRun = filepath.Join(t.TempDir(), ".env"), true
+		defer func() { DryRun = false }()
+
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST(http.MethodGet, "variables"), httpmock.JSONFromString(remoteVariables))
+
+		f, stdout, _ := testutils.NewFactory(mock)
+		require.NoError(t, NewPullCmd(f).Run())
+		mock.Verify(t)
+
+		require.NoFileExists(t, Out)
+		require.Contains(t, stdout.String(), "API_URL")
+		require.Contains(t, stdout.String(), msg.ActionSecret)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		Out, DryRun = filepath.Join(t.TempDir(), ".env"), false
+
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST(http.MethodGet, "variables"), httpmock.StatusStringResponse(http.StatusInternalServerError, "error"))
+
+		f, _, _ := testutils.NewFactory(mock)
+		require.Error(t, NewPullCmd(f).Run())
+		require.NoFileExists(t, Out)
+	})
+}
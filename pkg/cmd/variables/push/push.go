@@ -0,0 +1,289 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/MakeNowJust/heredoc"
+	msg "github.com/aziontech/azion-cli/messages/variables"
+	api "github.com/aziontech/azion-cli/pkg/api/variables"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	manifestInt "github.com/aziontech/azion-cli/pkg/manifest"
+	"github.com/aziontech/azion-cli/pkg/output"
+	"github.com/aziontech/azion-cli/utils"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+type PushCmd struct {
+	F          *cmdutil.Factory
+	Confirm    func(globalFlagAll bool, msg string, defaultYes bool) bool
+	GetWorkDir func() (string, error)
+}
+
+var (
+	EnvPath string
+	Prune   bool
+	DryRun  bool
+	Secrets []string
+)
+
+// Change is what pushing does to a remote variable
+type Change struct {
+	Key     string
+	Uuid    string
+	Value   string
+	Secret  bool
+	Action  string
+	Details string
+}
+
+func NewPushCmd(f *cmdutil.Factory) *PushCmd {
+	return &PushCmd{
+		F:          f,
+		Confirm:    utils.Confirm,
+		GetWorkDir: utils.GetWorkingDir,
+	}
+}
+
+func NewCobraCmd(push *PushCmd) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:           msg.PushUsage,
+		Short:         msg.PushShortDescription,
+		Long:          msg.PushLongDescription,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Example: heredoc.Doc(`
+		$ azion variables push
+		$ azion variables push --env .edge/.env.production
+		$ azion variables push --prune --dry-run
+		$ azion variables push --prune --yes
+		$ azion variables push --secrets API_TOKEN,SIGNING_KEY
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return push.Run()
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&EnvPath, "env", filepath.Join(".edge", ".env"), msg.PushFlagEnv)
+	cobraCmd.Flags().BoolVar(&Prune, "prune", false, msg.PushFlagPrune)
+	cobraCmd.Flags().StringSliceVar(&Secrets, "secrets", nil, msg.PushFlagSecrets)
+	cobraCmd.Flags().BoolVar(&DryRun, "dry-run", false, msg.PushFlagDryRun)
+	cobraCmd.Flags().BoolP("help", "h", false, msg.PushHelpFlag)
+	return cobraCmd
+}
+
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	return NewCobraCmd(NewPushCmd(f))
+}
+
+func (cmd *PushCmd) Run() error {
+	ctx := context.Background()
+
+	local, err := godotenv.Read(EnvPath)
+	if err != nil {
+		logger.Debug("Error while reading the .env file", zap.Error(err))
+		return fmt.Errorf(msg.ErrorReadEnvFile.Error(), EnvPath, err)
+	}
+
+	client := api.NewClient(cmd.F.HttpClient, cmd.F.Config.GetString("api_url"), cmd.F.Config.GetString("token"))
+	remote, err := client.List(ctx)
+	if err != nil {
+		logger.Debug("Error while listing variables", zap.Error(err))
+		return fmt.Errorf(msg.ErrorListVariables.Error(), err)
+	}
+	declared, err := cmd.declared()
+	if err != nil {
+		return err
+	}
+	secrets := make(map[string]bool)
+	for _, key := range Secrets {
+		secrets[key] = true
+	}
+	for key, variable := range declared {
+		if variable.Secret {
+			secrets[key] = true
+		}
+	}
+	changes := Plan(local, remote, Prune, declared, secrets)
+
+	if DryRun {
+		listOut := output.ListOutput{}
+		listOut.Columns = []string{"KEY", "ACTION", "DETAILS"}
+		listOut.Out = cmd.F.IOStreams.Out
+		listOut.Flags = cmd.F.Flags
+		for _, change := range changes {
+			listOut.Lines = append(listOut.Lines, []string{change.Key, change.Action, change.Details})
+		}
+		return output.Print(&listOut)
+	}
+
+	deletions := 0
+	for _, change := range changes {
+		if change.Action == msg.ActionDelete {
+			deletions++
+		}
+	}
+	if deletions > 0 && cmd.F.NonInteractive && !cmd.F.GlobalFlagAll {
+		return &utils.NonInteractiveError{Flag: "--yes"}
+	}
+	confirmed := deletions == 0 || cmd.Confirm(cmd.F.GlobalFlagAll, fmt.Sprintf(msg.AskPruneVariables, deletions), false)
+
+	msgs := []string{}
+	pushed := 0
+	for _, change := range changes {
+		var msgf string
+		switch change.Action {
+		case msg.ActionCreate:
+			request := api.Request{}
+			request.Key = change.Key
+			request.Value = change.Value
+			request.SetSecret(change.Secret)
+			if _, err := client.Create(ctx, request); err != nil {
+				return fmt.Errorf(msg.ErrorCreateItem.Error(), err)
+			}
+			msgf = fmt.Sprintf(msg.PushCreated, change.Key)
+		case msg.ActionUpdate:
+			request := &api.Request{Uuid: change.Uuid}
+			request.Key = change.Key
+			request.Value = change.Value
+			request.SetSecret(change.Secret)
+			if _, err := client.Update(ctx, request); err != nil {
+				return fmt.Errorf(msg.ErrorUpdateVariable.Error(), err)
+			}
+			msgf = fmt.Sprintf(msg.PushUpdated, change.Key, change.Details)
+		case msg.ActionDelete:
+			if !confirmed {
+				msgf = fmt.Sprintf(msg.PushNotDeleted, change.Key)
+				logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+				msgs = append(msgs, msgf)
+				continue
+			}
+			if err := client.Delete(ctx, change.Uuid); err != nil {
+				return fmt.Errorf(msg.ErrorFailToDeleteVariable.Error(), err)
+			}
+			msgf = fmt.Sprintf(msg.PushDeleted, change.Key)
+		default:
+			continue
+		}
+		pushed++
+		logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+		msgs = append(msgs, msgf)
+	}
+
+	msgf := fmt.Sprintf(msg.PushSummary, pushed, EnvPath)
+	if pushed == 0 {
+		msgf = fmt.Sprintf(msg.PushNothing, EnvPath)
+	}
+	logger.FInfoFlags(cmd.F.IOStreams.Out, msgf, cmd.F.Format, cmd.F.Out)
+	msgs = append(msgs, msgf)
+
+	outSlice := output.SliceOutput{
+		Messages: msgs,
+		GeneralOutput: output.GeneralOutput{
+			Out:   cmd.F.IOStreams.Out,
+			Flags: cmd.F.Flags,
+		},
+	}
+	return output.Print(&outSlice)
+}
+
+// Plan compares the .env file with the remote variables. Missing keys are created, and changed values updated; the
+// values of secrets can't be read, so secrets in the file are always updated. Keys listed in secrets are created and
+// updated as secrets. With prune, remote keys absent from the file are deleted, except secrets, which a pulled file
+// can't hold a value for, and the variables the manifest declares, which deploy converges.
+func Plan(
+	local map[string]string,
+	remote []api.Response,
+	prune bool,
+	declared map[string]contracts.Variable,
+	secrets map[string]bool) []Change {
+	changes := []Change{}
+	found := make(map[string]bool)
+	for _, variable := range remote {
+		key := variable.GetKey()
+		found[key] = true
+		value, ok := local[key]
+		if ok {
+			secret := variable.GetSecret() || secrets[key]
+			if secret || variable.GetValue() != value {
+				changes = append(changes, Change{
+					Key:     key,
+					Uuid:    variable.GetUuid(),
+					Value:   value,
+					Secret:  secret,
+					Action:  msg.ActionUpdate,
+					Details: fmt.Sprintf(msg.PushValueDiff, api.MaskValue(variable.GetValue(), variable.GetSecret()), api.MaskValue(value, secret)),
+				})
+			}
+			continue
+		}
+		if !prune {
+			continue
+		}
+		if variable.GetSecret() {
+			changes = append(changes, Change{Key: key, Uuid: variable.GetUuid(), Secret: true, Action: msg.ActionKeep, Details: msg.PushSecretKept})
+			continue
+		}
+		if _, ok := declared[key]; ok {
+			changes = append(changes, Change{Key: key, Uuid: variable.GetUuid(), Action: msg.ActionKeep, Details: msg.PushDeclared})
+			continue
+		}
+		changes = append(changes, Change{Key: key, Uuid: variable.GetUuid(), Action: msg.ActionDelete, Details: msg.PushNotInFile})
+	}
+
+	keys := make([]string, 0, len(local))
+	for key := range local {
+		if !found[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		changes = append(changes, Change{
+			Key:     key,
+			Value:   local[key],
+			Secret:  secrets[key],
+			Action:  msg.ActionCreate,
+			Details: api.MaskValue(local[key], secrets[key]),
+		})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// declared returns the variables the manifest of the project declares, by key. A project without a manifest declares
+// none.
+func (cmd *PushCmd) declared() (map[string]contracts.Variable, error) {
+	interpreter := manifestInt.NewManifestInterpreter()
+	interpreter.GetWorkDir = cmd.GetWorkDir
+	interpreter.EnvPath = EnvPath
+	path, err := interpreter.ManifestPath()
+	if err != nil {
+		return nil, err
+	}
+
+	declared := make(map[string]contracts.Variable)
+	manifest, err := interpreter.LoadManifest(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return declared, nil
+	}
+	if err != nil {
+		logger.Debug("Error while reading the manifest", zap.Error(err))
+		return nil, fmt.Errorf(msg.ErrorReadManifest.Error(), path, err)
+	}
+	for _, variable := range manifest.Variables {
+		declared[variable.Key] = variable
+	}
+	return declared, nil
+}
@@ -0,0 +1,227 @@
+package push
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	msg "github.com/aziontech/azion-cli/messages/variables"
+	api "github.com/aziontech/azion-cli/pkg/api/variables"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/aziontech/azion-cli/utils"
+	sdk "github.com/aziontech/azionapi-go-sdk/variables"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func variable(uuid, key, value string, secret bool) string {
+	return fmt.Sprintf(`{"uuid": "%s", "key": "%s", "value": "%s", "secret": %t, "last_editor": "user@azion.com",
+		"created_at": "2024-06-13T13:17:13.145625Z", "updated_at": "2024-06-13T13:17:13.145666Z"}`, uuid, key, value, secret)
+}
+
+var remoteVariables = fmt.Sprintf(`[%s, %s, %s, %s]`,
+	variable("uuid-api", "API_URL", "https://old.example.com", false),
+	variable("uuid-token", "TOKEN", "", true),
+	variable("uuid-stale", "STALE", "1", false),
+	variable("uuid-same", "SAME", "x", false),
+)
+
+const envFile = `API_URL=https://new.example.com
+TOKEN=abc
+SAME=x
+NEW=1
+`
+
+func writeEnv(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte(envFile), 0600))
+	return path
+}
+
+func TestPlan(t *testing.T) {
+	local := map[string]string{"API_URL": "https://new.example.com", "TOKEN": "abc", "SAME": "x", "NEW": "1"}
+	remote := []api.Response{}
+	for _, v := range []struct {
+		uuid, key, value string
+		secret           bool
+	}{
+		{"uuid-api", "API_URL", "https://old.example.com", false},
+		{"uuid-token", "TOKEN", "", true},
+		{"uuid-stale", "STALE", "1", false},
+		{"uuid-secret", "SIGNING_KEY", "", true},
+		{"uuid-same", "SAME", "x", false},
+	} {
+		remote = append(remote, sdk.NewVariable(v.uuid, v.key, v.value, v.secret, "user@azion.com", time.Now(), time.Now()))
+	}
+
+	t.Run("without prune", func(t *testing.T) {
+		changes := Plan(local, remote, false, nil, nil)
+		require.Equal(t, []Change{
+			{Key: "API_URL", Uuid: "uuid-api", Value: "https://new.example.com", Action: msg.ActionUpdate,
+				Details: fmt.Sprintf(msg.PushValueDiff, `"https://old.example.com"`, `"https://new.example.com"`)},
+			{Key: "NEW", Value: "1", Action: msg.ActionCreate, Details: `"1"`},
+			{Key: "TOKEN", Uuid: "uuid-token", Value: "abc", Secret: true, Action: msg.ActionUpdate,
+				Details: fmt.Sprintf(msg.PushValueDiff, api.SecretMask, api.SecretMask)},
+		}, changes)
+	})
+
+	t.Run("with secrets", func(t *testing.T) {
+		changes := Plan(local, remote, false, nil, map[string]bool{"NEW": true, "SAME": true})
+		require.Equal(t, []Change{
+			{Key: "API_URL", Uuid: "uuid-api", Value: "https://new.example.com", Action: msg.ActionUpdate,
+				Details: fmt.Sprintf(msg.PushValueDiff, `"https://old.example.com"`, `"https://new.example.com"`)},
+			{Key: "NEW", Value: "1", Secret: true, Action: msg.ActionCreate, Details: api.SecretMask},
+			{Key: "SAME", Uuid: "uuid-same", Value: "x", Secret: true, Action: msg.ActionUpdate,
+				Details: fmt.Sprintf(msg.PushValueDiff, `"x"`, api.SecretMask)},
+			{Key: "TOKEN", Uuid: "uuid-token", Value: "abc", Secret: true, Action: msg.ActionUpdate,
+				Details: fmt.Sprintf(msg.PushValueDiff, api.SecretMask, api.SecretMask)},
+		}, changes)
+	})
+
+	t.Run("with prune", func(t *testing.T) {
+		changes := Plan(local, remote, true, nil, nil)
+		actions := make(map[string]string)
+		for _, change := range changes {
+			actions[change.Key] = change.Action
+		}
+		require.Equal(t, map[string]string{
+			"API_URL":     msg.ActionUpdate,
+			"NEW":         msg.ActionCreate,
+			"SIGNING_KEY": msg.ActionKeep,
+			"STALE":       msg.ActionDelete,
+			"TOKEN":       msg.ActionUpdate,
+		}, actions)
+	})
+
+	t.Run("with prune and variables declared in the manifest", func(t *testing.T) {
+		changes := Plan(local, remote, true, map[string]contracts.Variable{"STALE": {Key: "STALE"}}, nil)
+		require.Contains(t, changes, Change{Key: "STALE", Uuid: "uuid-stale", Action: msg.ActionKeep, Details: msg.PushDeclared})
+	})
+}
+
+func TestPush(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	t.Run("push and prune", func(t *testing.T) {
+		EnvPath, Prune, DryRun = writeEnv(t), true, false
+		defer func() { Prune = false }()
+
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST(http.MethodGet, "variables"), httpmock.JSONFromString(remoteVariables))
+		mock.Register(httpmock.REST(http.MethodPut, "variables/uuid-api"),
+			httpmock.JSONFromString(variable("uuid-api", "API_URL", "https://new.example.com", false)))
+		mock.Register(httpmock.REST(http.MethodPut, "variables/uuid-token"),
+			httpmock.JSONFromString(variable("uuid-token", "TOKEN", "", true)))
+		mock.Register(httpmock.REST(http.MethodPost, "variables"),
+			httpmock.JSONFromString(variable("uuid-new", "NEW", "1", false)))
+		mock.Register(httpmock.REST(http.MethodDelete, "variables/uuid-stale"), httpmock.StatusStringResponse(204, ""))
+
+		f, stdout, _ := testutils.NewFactory(mock)
+		push := NewPushCmd(f)
+		push.Confirm = func(bool, string, bool) bool { return true }
+
+		require.NoError(t, push.Run())
+		mock.Verify(t)
+		require.Contains(t, stdout.String(), fmt.Sprintf(msg.PushDeleted, "STALE"))
+		require.Contains(t, stdout.String(), fmt.Sprintf(msg.PushSummary, 4, EnvPath))
+	})
+
+	t.Run("prune not confirmed", func(t *testing.T) {
+		EnvPath, Prune, DryRun = writeEnv(t), true, false
+		defer func() { Prune = false }()
+
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST(http.MethodGet, "variables"), httpmock.JSONFromString(remoteVariables))
+		mock.Register(httpmock.REST(http.MethodPut, "variables/uuid-api"),
+			httpmock.JSONFromString(variable("uuid-api", "API_URL", "https://new.example.com", false)))
+		mock.Register(httpmock.REST(http.MethodPut, "variables/uuid-token"),
+			httpmock.JSONFromString(variable("uuid-token", "TOKEN", "", true)))
+		mock.Register(httpmock.REST(http.MethodPost, "variables"),
+			httpmock.JSONFromString(variable("uuid-new", "NEW", "1", false)))
+
+		f, stdout, _ := testutils.NewFactory(mock)
+		push := NewPushCmd(f)
+		push.Confirm = func(bool, string, bool) bool { return false }
+
+		require.NoError(t, push.Run())
+		mock.Verify(t)
+		require.Contains(t, stdout.String(), fmt.Sprintf(msg.PushNotDeleted, "STALE"))
+	})
+
+	t.Run("prune in a non-interactive run", func(t *testing.T) {
+		EnvPath, Prune, DryRun = writeEnv(t), true, false
+		defer func() { Prune = false }()
+
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST(http.MethodGet, "variables"), httpmock.JSONFromString(remoteVariables))
+
+		f, _, _ := testutils.NewFactory(mock)
+		f.NonInteractive = true
+		push := NewPushCmd(f)
+		push.GetWorkDir = func() (string, error) { return t.TempDir(), nil }
+
+		require.ErrorIs(t, push.Run(), utils.ErrorNonInteractive)
+		mock.Verify(t)
+	})
+
+	t.Run("manifest declares secrets and keeps variables", func(t *testing.T) {
+		EnvPath, Prune, DryRun = writeEnv(t), true, false
+		defer func() { Prune = false }()
+		workDir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(workDir, ".edge"), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, ".edge", "manifest.json"),
+			[]byte(`{"variables": [{"key": "NEW", "secret": true}, {"key": "STALE"}]}`), 0644))
+
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST(http.MethodGet, "variables"), httpmock.JSONFromString(remoteVariables))
+		mock.Register(httpmock.REST(http.MethodPut, "variables/uuid-api"),
+			httpmock.JSONFromString(variable("uuid-api", "API_URL", "https://new.example.com", false)))
+		mock.Register(httpmock.REST(http.MethodPut, "variables/uuid-token"),
+			httpmock.JSONFromString(variable("uuid-token", "TOKEN", "", true)))
+		mock.Register(httpmock.REST(http.MethodPost, "variables"),
+			httpmock.WithHeader(httpmock.RESTPayload(http.StatusCreated, variable("uuid-new", "NEW", "", true),
+				func(payload map[string]interface{}) {
+					require.Equal(t, true, payload["secret"])
+				}), "Content-Type", "application/json"))
+
+		f, stdout, _ := testutils.NewFactory(mock)
+		push := NewPushCmd(f)
+		push.GetWorkDir = func() (string, error) { return workDir, nil }
+		push.Confirm = func(bool, string, bool) bool {
+			t.Fatal("nothing should be deleted")
+			return false
+		}
+
+		require.NoError(t, push.Run())
+		mock.Verify(t)
+		require.NotContains(t, stdout.String(), "STALE")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		EnvPath, Prune, DryRun = writeEnv(t), true, true
+		defer func() { Prune, DryRun = false, false }()
+
+		mock := &httpmock.Registry{}
+		mock.Register(httpmock.REST(http.MethodGet, "variables"), httpmock.JSONFromString(remoteVariables))
+
+		f, stdout, _ := testutils.NewFactory(mock)
+		require.NoError(t, NewPushCmd(f).Run())
+		mock.Verify(t)
+		require.Contains(t, stdout.String(), "STALE")
+		require.Contains(t, stdout.String(), msg.ActionDelete)
+		require.NotContains(t, stdout.String(), "abc")
+	})
+
+	t.Run("missing env file", func(t *testing.T) {
+		EnvPath = filepath.Join(t.TempDir(), ".env")
+
+		f, _, _ := testutils.NewFactory(&httpmock.Registry{})
+		require.ErrorContains(t, NewPushCmd(f).Run(), EnvPath)
+	})
+}
@@ -17,8 +17,9 @@ type Factory struct {
 
 type Flags struct {
 	logger.Logger
-	GlobalFlagAll bool   `json:"-" yaml:"-" toml:"-"`
-	Out           string `json:"-" yaml:"-" toml:"-"`
-	Format        string `json:"-" yaml:"-" toml:"-"`
-	NoColor       bool   `json:"-" yaml:"-" toml:"-"`
+	GlobalFlagAll  bool   `json:"-" yaml:"-" toml:"-"`
+	Out            string `json:"-" yaml:"-" toml:"-"`
+	Format         string `json:"-" yaml:"-" toml:"-"`
+	NoColor        bool   `json:"-" yaml:"-" toml:"-"`
+	NonInteractive bool   `json:"-" yaml:"-" toml:"-"`
 }
@@ -21,6 +21,8 @@ type BuildInfo struct {
 	OwnWorker     string
 	ProjectPath   string
 	IsFirewall    bool
+	Force         bool
+	VulcanVersion string
 }
 
 type DevInfo struct {
@@ -52,6 +54,7 @@ type AzionApplicationOptions struct {
 	Env           string                       `json:"env"`
 	Prefix        string                       `json:"prefix"`
 	NotFirstRun   bool                         `json:"not-first-run"`
+	VulcanVersion string                       `json:"vulcan-version,omitempty"` // exact edge-functions version or semver range
 	Function      AzionJsonDataFunction        `json:"function"`
 	Application   AzionJsonDataApplication     `json:"application"`
 	Domain        AzionJsonDataDomain          `json:"domain"`
@@ -59,6 +62,14 @@ type AzionApplicationOptions struct {
 	Origin        []AzionJsonDataOrigin        `json:"origin"`
 	RulesEngine   AzionJsonDataRulesEngine     `json:"rules-engine"`
 	CacheSettings []AzionJsonDataCacheSettings `json:"cache-settings"`
+	DeviceGroups  []AzionJsonDataDeviceGroup   `json:"device-groups,omitempty"`
+	// Functions and FunctionInstances are the ones declared in the manifest, apart from the project's function
+	Functions         []AzionJsonDataManifestFunction `json:"functions,omitempty"`
+	FunctionInstances []AzionJsonDataFunctionInstance `json:"function-instances,omitempty"`
+	// Domains are the ones declared in the manifest, apart from the project's domain
+	Domains []AzionJsonDataManifestDomain `json:"domains,omitempty"`
+	// Variables are the environment variables declared in the manifest, never their values
+	Variables []AzionJsonDataVariable `json:"variables,omitempty"`
 }
 
 type AzionApplicationSimple struct {
@@ -75,21 +86,27 @@ type AzionApplicationConfig struct {
 }
 
 type InitConf struct {
-	Cmd        string `json:"cmd"`
+	Cmd        string `json:"cmd"` // runs before the phase, like pre_cmd, when pre_cmd isn't declared
+	PreCmd     string `json:"pre_cmd"`
+	PostCmd    string `json:"post_cmd"`
 	Env        string `json:"env"`
 	OutputCtrl string `json:"output-ctrl"`
 	Default    string `json:"default"`
 }
 
 type BuildConf struct {
-	Cmd        string `json:"cmd"`
+	Cmd        string `json:"cmd"` // runs before the phase, like pre_cmd, when pre_cmd isn't declared
+	PreCmd     string `json:"pre_cmd"`
+	PostCmd    string `json:"post_cmd"`
 	Env        string `json:"env"`
 	OutputCtrl string `json:"output-ctrl"`
 	Default    string `json:"default"`
 }
 
 type PublishConf struct {
-	Cmd        string `json:"pre_cmd"`
+	Cmd        string `json:"cmd"` // runs before the phase, like pre_cmd, when pre_cmd isn't declared
+	PreCmd     string `json:"pre_cmd"`
+	PostCmd    string `json:"post_cmd"`
 	Env        string `json:"env"`
 	OutputCtrl string `json:"output-ctrl"`
 	Default    string `json:"default"`
@@ -115,10 +132,11 @@ type AzionJsonDataApplication struct {
 }
 
 type AzionJsonDataOrigin struct {
-	OriginId  int64    `json:"origin-id"`
-	OriginKey string   `json:"origin-key"`
-	Name      string   `json:"name"`
-	Address   []string `json:"address,omitempty"`
+	OriginId       int64    `json:"origin-id"`
+	OriginKey      string   `json:"origin-key"`
+	Name           string   `json:"name"`
+	Address        []string `json:"address,omitempty"`
+	PreventDestroy bool     `json:"prevent-destroy,omitempty"`
 }
 
 type AzionJsonDataDomain struct {
@@ -126,6 +144,8 @@ type AzionJsonDataDomain struct {
 	Name       string `json:"name"`
 	DomainName string `json:"domain_name"`
 	Url        string `json:"url"`
+	// DigitalCertificateId is the certificate the domain had on the last deploy, removed once the manifest drops it
+	DigitalCertificateId int64 `json:"digital_certificate_id,omitempty"`
 }
 
 type AzionJsonDataPurge struct {
@@ -138,44 +158,89 @@ type AzionJsonDataRulesEngine struct {
 }
 
 type AzionJsonDataRules struct {
-	Id    int64  `json:"id"`
-	Name  string `json:"name"`
-	Phase string `json:"phase"`
+	Id             int64  `json:"id"`
+	Name           string `json:"name"`
+	Phase          string `json:"phase"`
+	PreventDestroy bool   `json:"prevent-destroy,omitempty"`
 }
 
 type AzionJsonDataCacheSettings struct {
-	Id   int64  `json:"id"`
-	Name string `json:"name"`
+	Id             int64  `json:"id"`
+	Name           string `json:"name"`
+	PreventDestroy bool   `json:"prevent-destroy,omitempty"`
+}
+
+type AzionJsonDataDeviceGroup struct {
+	Id             int64  `json:"id"`
+	Name           string `json:"name"`
+	PreventDestroy bool   `json:"prevent-destroy,omitempty"`
+}
+
+type AzionJsonDataManifestFunction struct {
+	Id             int64  `json:"id"`
+	Name           string `json:"name"`
+	PreventDestroy bool   `json:"prevent-destroy,omitempty"`
+}
+
+type AzionJsonDataFunctionInstance struct {
+	Id             int64  `json:"id"`
+	Name           string `json:"name"`
+	PreventDestroy bool   `json:"prevent-destroy,omitempty"`
+}
+
+// AzionJsonDataManifestDomain keeps the hostnames of a domain, so deploy can purge them
+type AzionJsonDataManifestDomain struct {
+	Id                   int64    `json:"id"`
+	Name                 string   `json:"name"`
+	DomainName           string   `json:"domain_name"`
+	Cnames               []string `json:"cnames,omitempty"`
+	CnameAccessOnly      bool     `json:"cname-access-only,omitempty"`
+	DigitalCertificateId int64    `json:"digital-certificate-id,omitempty"`
+	PreventDestroy       bool     `json:"prevent-destroy,omitempty"`
+}
+
+// AzionJsonDataVariable tracks an environment variable of the manifest by its key
+type AzionJsonDataVariable struct {
+	Uuid           string `json:"uuid"`
+	Key            string `json:"key"`
+	Secret         bool   `json:"secret,omitempty"`
+	PreventDestroy bool   `json:"prevent-destroy,omitempty"`
 }
 
 type Manifest struct {
 	CacheSettings []CacheSetting `json:"cache"`
 	Origins       []Origin       `json:"origin"`
 	Rules         []RuleEngine   `json:"rules"`
+	DeviceGroups  []DeviceGroup  `json:"device_groups,omitempty"`
+	Functions     []Function     `json:"functions,omitempty"`
+	Domains       []Domain       `json:"domains,omitempty"`
+	Variables     []Variable     `json:"variables,omitempty"`
 }
 
 type CacheSetting struct {
-	Name                           *string  `json:"name,omitempty"`
-	BrowserCacheSettings           *string  `json:"browser_cache_settings,omitempty"`
-	BrowserCacheSettingsMaximumTtl *int64   `json:"browser_cache_settings_maximum_ttl,omitempty"`
-	CdnCacheSettings               *string  `json:"cdn_cache_settings,omitempty"`
-	CdnCacheSettingsMaximumTtl     *int64   `json:"cdn_cache_settings_maximum_ttl,omitempty"`
-	CacheByQueryString             *string  `json:"cache_by_query_string,omitempty"`
-	QueryStringFields              []string `json:"query_string_fields,omitempty"`
-	EnableQueryStringSort          *bool    `json:"enable_query_string_sort,omitempty"`
-	CacheByCookies                 *string  `json:"cache_by_cookies,omitempty"`
-	CookieNames                    []string `json:"cookie_names,omitempty"`
-	AdaptiveDeliveryAction         *string  `json:"adaptive_delivery_action,omitempty"`
-	DeviceGroup                    []int32  `json:"device_group,omitempty"`
-	EnableCachingForPost           *bool    `json:"enable_caching_for_post,omitempty"`
-	L2CachingEnabled               *bool    `json:"l2_caching_enabled,omitempty"`
-	IsSliceConfigurationEnabled    *bool    `json:"is_slice_configuration_enabled,omitempty"`
-	IsSliceEdgeCachingEnabled      *bool    `json:"is_slice_edge_caching_enabled,omitempty"`
-	IsSliceL2CachingEnabled        *bool    `json:"is_slice_l2_caching_enabled,omitempty"`
-	SliceConfigurationRange        *int64   `json:"slice_configuration_range,omitempty"`
-	EnableCachingForOptions        *bool    `json:"enable_caching_for_options,omitempty"`
-	EnableStaleCache               *bool    `json:"enable_stale_cache,omitempty"`
-	L2Region                       *string  `json:"l2_region,omitempty"`
+	Name                           *string    `json:"name,omitempty"`
+	BrowserCacheSettings           *string    `json:"browser_cache_settings,omitempty"`
+	BrowserCacheSettingsMaximumTtl *int64     `json:"browser_cache_settings_maximum_ttl,omitempty"`
+	CdnCacheSettings               *string    `json:"cdn_cache_settings,omitempty"`
+	CdnCacheSettingsMaximumTtl     *int64     `json:"cdn_cache_settings_maximum_ttl,omitempty"`
+	CacheByQueryString             *string    `json:"cache_by_query_string,omitempty"`
+	QueryStringFields              []string   `json:"query_string_fields,omitempty"`
+	EnableQueryStringSort          *bool      `json:"enable_query_string_sort,omitempty"`
+	CacheByCookies                 *string    `json:"cache_by_cookies,omitempty"`
+	CookieNames                    []string   `json:"cookie_names,omitempty"`
+	AdaptiveDeliveryAction         *string    `json:"adaptive_delivery_action,omitempty"`
+	DeviceGroup                    []int32    `json:"device_group,omitempty"`
+	DeviceGroups                   []string   `json:"device_groups,omitempty"`
+	EnableCachingForPost           *bool      `json:"enable_caching_for_post,omitempty"`
+	L2CachingEnabled               *bool      `json:"l2_caching_enabled,omitempty"`
+	IsSliceConfigurationEnabled    *bool      `json:"is_slice_configuration_enabled,omitempty"`
+	IsSliceEdgeCachingEnabled      *bool      `json:"is_slice_edge_caching_enabled,omitempty"`
+	IsSliceL2CachingEnabled        *bool      `json:"is_slice_l2_caching_enabled,omitempty"`
+	SliceConfigurationRange        *int64     `json:"slice_configuration_range,omitempty"`
+	EnableCachingForOptions        *bool      `json:"enable_caching_for_options,omitempty"`
+	EnableStaleCache               *bool      `json:"enable_stale_cache,omitempty"`
+	L2Region                       *string    `json:"l2_region,omitempty"`
+	Lifecycle                      *Lifecycle `json:"lifecycle,omitempty"`
 }
 
 type Origin struct {
@@ -185,6 +250,7 @@ type Origin struct {
 	Prefix     string                              `json:"prefix,omitempty"`
 	Addresses  []sdk.CreateOriginsRequestAddresses `json:"addresses,omitempty"`
 	HostHeader string                              `json:"host_header,omitempty"`
+	Lifecycle  *Lifecycle                          `json:"lifecycle,omitempty"`
 }
 
 type RuleEngine struct {
@@ -195,6 +261,62 @@ type RuleEngine struct {
 	IsActive    bool                           `json:"is_active,omitempty"`
 	Criteria    [][]sdk.RulesEngineCriteria    `json:"criteria,omitempty"`
 	Behaviors   []sdk.RulesEngineBehaviorEntry `json:"behaviors,omitempty"`
+	Lifecycle   *Lifecycle                     `json:"lifecycle,omitempty"`
+}
+
+// DeviceGroup tells devices apart by their User-Agent, so cache settings and rules can treat them differently
+type DeviceGroup struct {
+	Name      string     `json:"name"`
+	UserAgent string     `json:"user_agent"`
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty"`
+}
+
+// Function is an edge function deployed along with the project's one, run by the rules through its instances
+type Function struct {
+	Name string `json:"name"`
+	// Path is the file with the code of the function, relative to the project
+	Path string `json:"path"`
+	// Args is a JSON file with the arguments of the function, relative to the project
+	Args   string `json:"args,omitempty"`
+	Active *bool  `json:"active,omitempty"`
+	// Instances are the instances of the function in the edge application. Without any, a single instance is
+	// created with the name of the function.
+	Instances []FunctionInstance `json:"instances,omitempty"`
+	Lifecycle *Lifecycle         `json:"lifecycle,omitempty"`
+}
+
+// FunctionInstance is an instance of a function, named by the run_function behaviors of the rules
+type FunctionInstance struct {
+	Name string `json:"name"`
+	// Args is a JSON file with the arguments of the instance. Without it the instance uses the ones of the function
+	Args string `json:"args,omitempty"`
+}
+
+// Domain serves the edge application under its own hostnames
+type Domain struct {
+	// Name is __DEFAULT__ for the project's domain, which deploy creates with the edge application
+	Name   string   `json:"name"`
+	Cnames []string `json:"cnames,omitempty"`
+	// CnameAccessOnly serves the domain only through its CNAMEs, not through the domain Azion assigns to it
+	CnameAccessOnly *bool `json:"cname_access_only,omitempty"`
+	// DigitalCertificateId is the certificate used for the CNAMEs. Removing it removes the certificate of the domain
+	DigitalCertificateId *int64     `json:"digital_certificate_id,omitempty"`
+	IsActive             *bool      `json:"is_active,omitempty"`
+	Lifecycle            *Lifecycle `json:"lifecycle,omitempty"`
+}
+
+// Variable is an environment variable of the account. The manifest only declares it: its value is read from the .env
+// file of the project or from the environment of the process, which wins
+type Variable struct {
+	Key       string     `json:"key"`
+	Secret    bool       `json:"secret,omitempty"`
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty"`
+}
+
+// Lifecycle controls what deploy may do with a resource of the manifest
+type Lifecycle struct {
+	// PreventDestroy keeps deploy from deleting the resource, even after it is removed from the manifest
+	PreventDestroy bool `json:"prevent_destroy,omitempty"`
 }
 
 type SyncOpts struct {
@@ -206,5 +328,83 @@ type SyncOpts struct {
 
 type RuleIdsStruct struct {
 	Id    int64
+	Name  string
 	Phase string
 }
+
+type ResourcePlan struct {
+	Resource string `json:"resource" yaml:"resource" toml:"resource"`
+	Name     string `json:"name" yaml:"name" toml:"name"`
+	Id       string `json:"id,omitempty" yaml:"id,omitempty" toml:"id,omitempty"`
+	Action   string `json:"action" yaml:"action" toml:"action"`
+	Details  string `json:"details,omitempty" yaml:"details,omitempty" toml:"details,omitempty"`
+}
+
+// ResourceDrift is a difference between a resource declared in the manifest and its remote configuration
+type ResourceDrift struct {
+	Resource string `json:"resource" yaml:"resource" toml:"resource"`
+	Name     string `json:"name" yaml:"name" toml:"name"`
+	Id       string `json:"id,omitempty" yaml:"id,omitempty" toml:"id,omitempty"`
+	Field    string `json:"field,omitempty" yaml:"field,omitempty" toml:"field,omitempty"`
+	Manifest string `json:"manifest,omitempty" yaml:"manifest,omitempty" toml:"manifest,omitempty"`
+	Remote   string `json:"remote,omitempty" yaml:"remote,omitempty" toml:"remote,omitempty"`
+	Details  string `json:"details,omitempty" yaml:"details,omitempty" toml:"details,omitempty"`
+}
+
+type DeploymentHistory struct {
+	Deployments []DeploymentRecord `json:"deployments"`
+}
+
+type DeploymentRecord struct {
+	VersionID    string `json:"version-id"`
+	FunctionHash string `json:"function-hash"`
+	Timestamp    string `json:"timestamp"`
+	GitCommit    string `json:"git-commit,omitempty"`
+	Bucket       string `json:"bucket"`
+	Prefix       string `json:"prefix"`
+	Pruned       bool   `json:"pruned,omitempty"` // the static files were deleted by --keep-versions
+}
+
+// StoragePrefix is the prefix the static files of the deploy were stored under. Deploys without changes
+// to the static files reuse the prefix of a previous one.
+func (r DeploymentRecord) StoragePrefix() string {
+	if r.Prefix == "" {
+		return r.VersionID
+	}
+	return r.Prefix
+}
+
+// JournalEntry is a resource created by a deploy that has not completed yet
+type JournalEntry struct {
+	Resource      string `json:"resource"`
+	ID            int64  `json:"id,omitempty"`
+	Key           string `json:"key,omitempty"`
+	Name          string `json:"name"`
+	ApplicationID int64  `json:"application-id,omitempty"`
+	Phase         string `json:"phase,omitempty"`
+}
+
+// DeployReport is the machine-readable result of a deploy, printed with --format or --out
+type DeployReport struct {
+	Status        string         `json:"status" yaml:"status" toml:"status"`
+	Error         string         `json:"error,omitempty" yaml:"error,omitempty" toml:"error,omitempty"`
+	ApplicationID int64          `json:"application_id" yaml:"application_id" toml:"application_id"`
+	FunctionID    int64          `json:"function_id" yaml:"function_id" toml:"function_id"`
+	InstanceID    int64          `json:"instance_id" yaml:"instance_id" toml:"instance_id"`
+	DomainID      int64          `json:"domain_id" yaml:"domain_id" toml:"domain_id"`
+	Bucket        string         `json:"bucket" yaml:"bucket" toml:"bucket"`
+	URL           string         `json:"url" yaml:"url" toml:"url"`
+	Prefix        string         `json:"prefix" yaml:"prefix" toml:"prefix"`
+	Environment   string         `json:"environment,omitempty" yaml:"environment,omitempty" toml:"environment,omitempty"`
+	FilesUploaded int            `json:"files_uploaded" yaml:"files_uploaded" toml:"files_uploaded"`
+	FilesSkipped  int            `json:"files_skipped" yaml:"files_skipped" toml:"files_skipped"`
+	PurgedURLs    []string       `json:"purged_urls" yaml:"purged_urls" toml:"purged_urls"`
+	Resources     []ResourcePlan `json:"resources" yaml:"resources" toml:"resources"`
+	Phases        []DeployPhase  `json:"phases" yaml:"phases" toml:"phases"`
+}
+
+// DeployPhase is the time a step of the deploy took
+type DeployPhase struct {
+	Name       string `json:"name" yaml:"name" toml:"name"`
+	DurationMs int64  `json:"duration_ms" yaml:"duration_ms" toml:"duration_ms"`
+}
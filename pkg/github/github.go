@@ -25,6 +25,7 @@ type Github struct {
 	GetNameRepo      func(url string) string
 	CheckGitignore   func(path string) (bool, error)
 	WriteGitignore   func(path string) error
+	HeadCommit       func(path string) (string, error)
 }
 
 type Release struct {
@@ -42,6 +43,7 @@ func NewGithub() *Github {
 		GetNameRepo:      getNameRepo,
 		CheckGitignore:   checkGitignore,
 		WriteGitignore:   writeGitignore,
+		HeadCommit:       headCommit,
 	}
 }
 
@@ -88,6 +90,21 @@ func clone(url, path string) error {
 	return nil
 }
 
+// headCommit returns the commit hash checked out in the repository containing path
+func headCommit(path string) (string, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	return head.Hash().String(), nil
+}
+
 func getNameRepo(url string) string {
 	parts := strings.Split(url, "/")
 	repoPart := parts[len(parts)-1]
@@ -0,0 +1,164 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	msg "github.com/aziontech/azion-cli/messages/hooks"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	PreInit     = "pre-init"
+	PostInit    = "post-init"
+	PreBuild    = "pre-build"
+	PostBuild   = "post-build"
+	PrePublish  = "pre-publish"
+	PostPublish = "post-publish"
+
+	// values of output-ctrl; any other value streams the output of the hook
+	OutputDisable = "disable"
+	OutputOnError = "on-error"
+
+	configFile = "config.json"
+	shell      = "/bin/sh"
+)
+
+type Hooks struct {
+	F          *cmdutil.Factory
+	ReadConfig func(path string) (*contracts.AzionApplicationConfig, error)
+	EnvLoader  func(path string) ([]string, error)
+	Runner     func(dir string, envVars []string, comm string, stdout, stderr io.Writer) error
+}
+
+type hook struct {
+	cmd        string
+	env        string
+	outputCtrl string
+}
+
+func NewHooks(f *cmdutil.Factory) *Hooks {
+	return &Hooks{
+		F:          f,
+		ReadConfig: readConfig,
+		EnvLoader:  utils.LoadEnvVarsFromFile,
+		Runner:     runner,
+	}
+}
+
+// Run executes the given hook declared in the config.json of the project's config directory.
+// The command runs from the config directory, which is also where the env file path is resolved from.
+func (h *Hooks) Run(projectDir, confDir, name string, msgs *[]string) error {
+	dir := filepath.Join(projectDir, confDir)
+	configPath := filepath.Join(dir, configFile)
+	config, err := h.ReadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf(msg.ErrorReadingConfig.Error(), configPath, err)
+	}
+
+	hk := selectHook(config, name)
+	if hk.cmd == "" {
+		return nil
+	}
+
+	var envVars []string
+	if hk.env != "" {
+		envVars, err = h.EnvLoader(filepath.Join(dir, hk.env))
+		if err != nil {
+			logger.Debug("Error while loading the env file of the hook", zap.String("hook", name), zap.Error(err))
+			return fmt.Errorf(msg.ErrorLoadingEnv.Error(), hk.env, name, err)
+		}
+	}
+
+	msgf := fmt.Sprintf(msg.HookRunning, name)
+	logger.FInfoFlags(h.F.IOStreams.Out, msgf, h.F.Format, h.F.Out)
+	*msgs = append(*msgs, msgf)
+	msgf = fmt.Sprintf(msg.HookCommand, hk.cmd)
+	logger.FInfoFlags(h.F.IOStreams.Out, msgf, h.F.Format, h.F.Out)
+	*msgs = append(*msgs, msgf)
+
+	var captured bytes.Buffer
+	var stdout, stderr io.Writer = h.F.IOStreams.Out, h.F.IOStreams.Err
+	switch {
+	case hk.outputCtrl == OutputDisable:
+		stdout, stderr = io.Discard, io.Discard
+	case hk.outputCtrl == OutputOnError || h.F.Silent || h.F.Format != "" || h.F.Out != "":
+		// formatted output must not be mixed with the hook's, so it is only shown when the hook fails
+		stdout, stderr = &captured, &captured
+	}
+
+	if err := h.Runner(dir, envVars, hk.cmd, stdout, stderr); err != nil {
+		logger.Debug("Error while running hook", zap.String("hook", name), zap.Error(err))
+		if captured.Len() > 0 {
+			fmt.Fprint(h.F.IOStreams.Err, captured.String())
+		}
+		return fmt.Errorf(msg.ErrorHookFailed.Error(), name, err, configPath)
+	}
+
+	msgf = fmt.Sprintf(msg.HookFinished, name)
+	logger.FInfoFlags(h.F.IOStreams.Out, msgf, h.F.Format, h.F.Out)
+	*msgs = append(*msgs, msgf)
+	return nil
+}
+
+func selectHook(config *contracts.AzionApplicationConfig, name string) hook {
+	switch name {
+	case PreInit:
+		return hook{preCmd(config.InitData.PreCmd, config.InitData.Cmd), config.InitData.Env, config.InitData.OutputCtrl}
+	case PostInit:
+		return hook{config.InitData.PostCmd, config.InitData.Env, config.InitData.OutputCtrl}
+	case PreBuild:
+		return hook{preCmd(config.BuildData.PreCmd, config.BuildData.Cmd), config.BuildData.Env, config.BuildData.OutputCtrl}
+	case PostBuild:
+		return hook{config.BuildData.PostCmd, config.BuildData.Env, config.BuildData.OutputCtrl}
+	case PrePublish:
+		return hook{preCmd(config.PublishData.PreCmd, config.PublishData.Cmd), config.PublishData.Env, config.PublishData.OutputCtrl}
+	case PostPublish:
+		return hook{config.PublishData.PostCmd, config.PublishData.Env, config.PublishData.OutputCtrl}
+	}
+	return hook{}
+}
+
+// preCmd keeps config.json files that declare the command of a phase as cmd working: it runs as the pre hook
+func preCmd(pre, cmd string) string {
+	if pre != "" {
+		return pre
+	}
+	return cmd
+}
+
+// readConfig reads config.json; projects without one simply declare no hooks
+func readConfig(path string) (*contracts.AzionApplicationConfig, error) {
+	config := &contracts.AzionApplicationConfig{}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return config, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func runner(dir string, envVars []string, comm string, stdout, stderr io.Writer) error {
+	command := exec.Command(shell, "-c", comm)
+	command.Dir = dir
+	command.Env = append(os.Environ(), envVars...)
+	command.Stdout = stdout
+	command.Stderr = stderr
+	return command.Run()
+}
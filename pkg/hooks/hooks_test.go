@@ -0,0 +1,124 @@
+package hooks
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRun(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+
+	t.Run("no config declares no hooks", func(t *testing.T) {
+		f, stdout, _ := testutils.NewFactory(&httpmock.Registry{})
+		h := NewHooks(f)
+		h.Runner = func(dir string, envVars []string, comm string, stdout, stderr io.Writer) error {
+			t.Fatal("no hook should run")
+			return nil
+		}
+
+		msgs := []string{}
+		err := h.Run(t.TempDir(), "azion", PreBuild, &msgs)
+		require.NoError(t, err)
+		require.Empty(t, msgs)
+		require.Empty(t, stdout.String())
+	})
+
+	t.Run("runs the declared hook", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "azion"), 0755))
+		config := `{"build":{"pre_cmd":"echo hello > pre.txt"}}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "azion", "config.json"), []byte(config), 0644))
+
+		f, stdout, _ := testutils.NewFactory(&httpmock.Registry{})
+		msgs := []string{}
+		err := NewHooks(f).Run(dir, "azion", PreBuild, &msgs)
+		require.NoError(t, err)
+		require.Len(t, msgs, 3)
+		require.Contains(t, stdout.String(), "Running pre-build hook")
+
+		content, err := os.ReadFile(filepath.Join(dir, "azion", "pre.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "hello\n", string(content))
+
+		// the post-build hook is not declared
+		err = NewHooks(f).Run(dir, "azion", PostBuild, &msgs)
+		require.NoError(t, err)
+		require.Len(t, msgs, 3)
+	})
+
+	t.Run("loads the env file", func(t *testing.T) {
+		f, _, _ := testutils.NewFactory(&httpmock.Registry{})
+		h := NewHooks(f)
+		h.ReadConfig = func(path string) (*contracts.AzionApplicationConfig, error) {
+			return &contracts.AzionApplicationConfig{
+				PublishData: contracts.PublishConf{PostCmd: "notify", Env: ".env"},
+			}, nil
+		}
+		h.EnvLoader = func(path string) ([]string, error) {
+			require.Equal(t, filepath.Join("project", "azion", ".env"), path)
+			return []string{"TOKEN=abc"}, nil
+		}
+		var got []string
+		h.Runner = func(dir string, envVars []string, comm string, stdout, stderr io.Writer) error {
+			require.Equal(t, filepath.Join("project", "azion"), dir)
+			require.Equal(t, "notify", comm)
+			got = envVars
+			return nil
+		}
+
+		msgs := []string{}
+		err := h.Run("project", "azion", PostPublish, &msgs)
+		require.NoError(t, err)
+		require.Equal(t, []string{"TOKEN=abc"}, got)
+	})
+
+	t.Run("cmd runs as the pre hook", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "azion"), 0755))
+		config := `{"build":{"cmd":"echo legacy > cmd.txt"},"publish":{"cmd":"legacy","pre_cmd":"echo pre > pre.txt"}}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "azion", "config.json"), []byte(config), 0644))
+
+		f, _, _ := testutils.NewFactory(&httpmock.Registry{})
+		msgs := []string{}
+		require.NoError(t, NewHooks(f).Run(dir, "azion", PreBuild, &msgs))
+		content, err := os.ReadFile(filepath.Join(dir, "azion", "cmd.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "legacy\n", string(content))
+
+		// pre_cmd takes precedence over cmd
+		require.NoError(t, NewHooks(f).Run(dir, "azion", PrePublish, &msgs))
+		content, err = os.ReadFile(filepath.Join(dir, "azion", "pre.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "pre\n", string(content))
+	})
+
+	t.Run("failing hook shows its output on error", func(t *testing.T) {
+		f, stdout, stderr := testutils.NewFactory(&httpmock.Registry{})
+		h := NewHooks(f)
+		h.ReadConfig = func(path string) (*contracts.AzionApplicationConfig, error) {
+			return &contracts.AzionApplicationConfig{
+				InitData: contracts.InitConf{PreCmd: "npm ci", OutputCtrl: OutputOnError},
+			}, nil
+		}
+		h.Runner = func(dir string, envVars []string, comm string, stdout, stderr io.Writer) error {
+			_, _ = stdout.Write([]byte("npm ERR! missing lockfile\n"))
+			return errors.New("exit status 1")
+		}
+
+		msgs := []string{}
+		err := h.Run("project", "azion", PreInit, &msgs)
+		require.ErrorContains(t, err, "The pre-init hook failed: exit status 1")
+		require.NotContains(t, stdout.String(), "npm ERR!")
+		require.Contains(t, stderr.String(), "npm ERR! missing lockfile")
+	})
+}
@@ -0,0 +1,252 @@
+package manifest
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	sdk "github.com/aziontech/azionapi-go-sdk/edgeapplications"
+)
+
+// Diff compares the origins, device groups, cache settings and rules the manifest declares and azion.json tracks with their remote
+// configuration, field by field. Only the fields the manifest sets are compared, since deploy leaves the other ones
+// untouched. With untracked, remote resources the manifest doesn't declare at all are reported as well.
+func Diff(manifest *contracts.Manifest, conf *contracts.AzionApplicationOptions, remote *Remote, untracked bool) []contracts.ResourceDrift {
+	drifts := []contracts.ResourceDrift{}
+
+	remoteOrigins := make(map[int64]RemoteOrigin)
+	for _, origin := range remote.Origins {
+		remoteOrigins[origin.ID] = origin
+	}
+	trackedOrigins := make(map[string]int64)
+	for _, origin := range conf.Origin {
+		trackedOrigins[origin.Name] = origin.OriginId
+	}
+	declaredOrigins := make(map[string]bool)
+	for _, origin := range manifest.Origins {
+		declaredOrigins[origin.Name] = true
+		id, ok := trackedOrigins[origin.Name]
+		if !ok {
+			continue
+		}
+		found, ok := remoteOrigins[id]
+		if !ok {
+			drifts = append(drifts, notFound(ResourceOrigin, origin.Name, id))
+			continue
+		}
+		drifts = append(drifts, diffFields(ResourceOrigin, origin.Name, id, origin, found.Origin)...)
+	}
+
+	remoteGroups := make(map[int64]RemoteDeviceGroup)
+	for _, group := range remote.DeviceGroups {
+		remoteGroups[group.ID] = group
+	}
+	trackedGroups := make(map[string]int64)
+	for _, group := range conf.DeviceGroups {
+		trackedGroups[group.Name] = group.Id
+	}
+	declaredGroups := make(map[string]bool)
+	for _, group := range manifest.DeviceGroups {
+		declaredGroups[group.Name] = true
+		id, ok := trackedGroups[group.Name]
+		if !ok {
+			continue
+		}
+		found, ok := remoteGroups[id]
+		if !ok {
+			drifts = append(drifts, notFound(ResourceDeviceGroup, group.Name, id))
+			continue
+		}
+		drifts = append(drifts, diffFields(ResourceDeviceGroup, group.Name, id, group, found.Group)...)
+	}
+
+	remoteCaches := make(map[int64]RemoteCache)
+	for _, cache := range remote.CacheSettings {
+		remoteCaches[cache.ID] = cache
+	}
+	trackedCaches := make(map[string]int64)
+	for _, cache := range conf.CacheSettings {
+		trackedCaches[cache.Name] = cache.Id
+	}
+	declaredCaches := make(map[string]bool)
+	for _, cache := range manifest.CacheSettings {
+		if cache.Name == nil {
+			continue
+		}
+		declaredCaches[*cache.Name] = true
+		id, ok := trackedCaches[*cache.Name]
+		if !ok {
+			continue
+		}
+		found, ok := remoteCaches[id]
+		if !ok {
+			drifts = append(drifts, notFound(ResourceCache, *cache.Name, id))
+			continue
+		}
+		drifts = append(drifts, diffFields(ResourceCache, *cache.Name, id, cache, found.Cache)...)
+	}
+
+	instanceNames := make(map[int64]string)
+	for _, instance := range remote.Instances {
+		instanceNames[instance.ID] = instance.Name
+	}
+	remoteRules := make(map[int64]RemoteRule)
+	for _, rule := range remote.Rules {
+		remoteRules[rule.ID] = rule
+	}
+	trackedRules := make(map[string]contracts.RuleIdsStruct)
+	for _, rule := range conf.RulesEngine.Rules {
+		trackedRules[RuleKey(rule.Phase, rule.Name)] = contracts.RuleIdsStruct{Id: rule.Id, Name: rule.Name, Phase: rule.Phase}
+	}
+	declaredRules := make(map[string]bool)
+	for _, rule := range manifest.Rules {
+		declaredRules[RuleKey(rule.Phase, rule.Name)] = true
+		_, tracked := trackedRule(trackedRules, rule)
+		id := tracked.Id
+		if id == 0 {
+			continue
+		}
+		found, ok := remoteRules[id]
+		if !ok {
+			drifts = append(drifts, notFound(ResourceRule, rule.Name, id))
+			continue
+		}
+		drifts = append(drifts, diffFields(ResourceRule, rule.Name, id, withFunctionTarget(rule, conf, instanceNames), found.Rule)...)
+	}
+
+	if untracked {
+		for _, origin := range remote.Origins {
+			if !declaredOrigins[origin.Origin.Name] {
+				drifts = append(drifts, notDeclared(ResourceOrigin, origin.Origin.Name, origin.ID))
+			}
+		}
+		for _, group := range remote.DeviceGroups {
+			if !declaredGroups[group.Group.Name] {
+				drifts = append(drifts, notDeclared(ResourceDeviceGroup, group.Group.Name, group.ID))
+			}
+		}
+		for _, cache := range remote.CacheSettings {
+			if name := cacheName(cache.Cache); !declaredCaches[name] {
+				drifts = append(drifts, notDeclared(ResourceCache, name, cache.ID))
+			}
+		}
+		for _, rule := range remote.Rules {
+			if !declaredRules[RuleKey(rule.Rule.Phase, rule.Rule.Name)] {
+				drifts = append(drifts, notDeclared(ResourceRule, rule.Rule.Name, rule.ID))
+			}
+		}
+	}
+
+	return drifts
+}
+
+func notFound(resource, name string, id int64) contracts.ResourceDrift {
+	return contracts.ResourceDrift{
+		Resource: resource,
+		Name:     name,
+		Id:       strconv.FormatInt(id, 10),
+		Details:  msg.PlanNotFoundRemotely,
+	}
+}
+
+func notDeclared(resource, name string, id int64) contracts.ResourceDrift {
+	return contracts.ResourceDrift{
+		Resource: resource,
+		Name:     name,
+		Id:       strconv.FormatInt(id, 10),
+		Details:  msg.DriftNotDeclared,
+	}
+}
+
+// withFunctionTarget names the instance run_function behaviors run, as the remote rules are read. Rules without a
+// target, and every rule of a manifest without a functions section, run the instance of the project's function.
+func withFunctionTarget(rule contracts.RuleEngine, conf *contracts.AzionApplicationOptions, instanceNames map[int64]string) contracts.RuleEngine {
+	return mapFunctionTarget(rule, func(target string) string {
+		if target == "" || len(conf.FunctionInstances) == 0 {
+			return instanceNames[conf.Function.InstanceID]
+		}
+		return target
+	})
+}
+
+// withoutFunctionTarget blanks the target of run_function behaviors, so the rules run the instance of the project's function
+func withoutFunctionTarget(rule contracts.RuleEngine) contracts.RuleEngine {
+	return mapFunctionTarget(rule, func(string) string { return "" })
+}
+
+func mapFunctionTarget(rule contracts.RuleEngine, target func(string) string) contracts.RuleEngine {
+	behaviors := make([]sdk.RulesEngineBehaviorEntry, 0, len(rule.Behaviors))
+	for _, behavior := range rule.Behaviors {
+		if behavior.RulesEngineBehaviorString != nil && behavior.RulesEngineBehaviorString.Name == "run_function" {
+			behaviorString := *behavior.RulesEngineBehaviorString
+			behaviorString.Target = target(behaviorString.Target)
+			behavior = sdk.RulesEngineBehaviorEntry{RulesEngineBehaviorString: &behaviorString}
+		}
+		behaviors = append(behaviors, behavior)
+	}
+	rule.Behaviors = behaviors
+	return rule
+}
+
+// diffFields compares the JSON fields set in the manifest entry with the same fields of the remote resource
+func diffFields(resource, name string, id int64, declared, remote any) []contracts.ResourceDrift {
+	local, err := fields(declared)
+	if err != nil {
+		return nil
+	}
+	current, err := fields(remote)
+	if err != nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(local))
+	for key := range local {
+		// lifecycle only tells deploy what it may do with the resource, and deploy renumbers the order of the rules
+		// from 1, so only their relative order is converged
+		if key == "lifecycle" || key == "order" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	drifts := []contracts.ResourceDrift{}
+	for _, key := range keys {
+		if reflect.DeepEqual(local[key], current[key]) {
+			continue
+		}
+		drifts = append(drifts, contracts.ResourceDrift{
+			Resource: resource,
+			Name:     name,
+			Id:       strconv.FormatInt(id, 10),
+			Field:    key,
+			Manifest: render(local[key]),
+			Remote:   render(current[key]),
+		})
+	}
+	return drifts
+}
+
+func fields(v any) (map[string]any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoded := make(map[string]any)
+	err = json.Unmarshal(raw, &decoded)
+	return decoded, err
+}
+
+func render(v any) string {
+	if v == nil {
+		return "-"
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "-"
+	}
+	return string(raw)
+}
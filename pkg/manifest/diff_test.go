@@ -0,0 +1,85 @@
+package manifest
+
+import (
+	"testing"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	sdk "github.com/aziontech/azionapi-go-sdk/edgeapplications"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	str := func(s string) *string { return &s }
+	ttl := func(n int64) *int64 { return &n }
+	behavior := func(name, target string) sdk.RulesEngineBehaviorEntry {
+		return sdk.RulesEngineBehaviorEntry{RulesEngineBehaviorString: &sdk.RulesEngineBehaviorString{Name: name, Target: target}}
+	}
+
+	manifest := &contracts.Manifest{
+		CacheSettings: []contracts.CacheSetting{
+			{Name: str("static"), BrowserCacheSettings: str("override"), BrowserCacheSettingsMaximumTtl: ttl(3600)},
+			{Name: str("new")},
+		},
+		Origins: []contracts.Origin{
+			{Name: "api", OriginType: "single_origin", Addresses: []sdk.CreateOriginsRequestAddresses{{Address: "api.example.com"}}},
+		},
+		Rules: []contracts.RuleEngine{
+			{Name: "compute", Phase: "request", Behaviors: []sdk.RulesEngineBehaviorEntry{behavior("run_function", "")}},
+			{Name: "reports", Phase: "request", Behaviors: []sdk.RulesEngineBehaviorEntry{behavior("run_function", "")}},
+			{Name: "gone", Phase: "request"},
+			{Name: "headers", Phase: "request", Order: 10, Description: str("request")},
+			{Name: "headers", Phase: "response", Order: 20, Description: str("response")},
+		},
+		DeviceGroups: []contracts.DeviceGroup{
+			{Name: "mobile", UserAgent: "iPhone", Lifecycle: &contracts.Lifecycle{PreventDestroy: true}},
+		},
+	}
+	conf := &contracts.AzionApplicationOptions{
+		CacheSettings: []contracts.AzionJsonDataCacheSettings{{Id: 7, Name: "static"}},
+		Origin:        []contracts.AzionJsonDataOrigin{{OriginId: 42, Name: "api"}},
+		RulesEngine: contracts.AzionJsonDataRulesEngine{Rules: []contracts.AzionJsonDataRules{
+			{Id: 3, Name: "compute", Phase: "request"},
+			{Id: 4, Name: "reports", Phase: "request"},
+			{Id: 5, Name: "gone", Phase: "request"},
+			{Id: 6, Name: "headers", Phase: "request"},
+			{Id: 7, Name: "headers", Phase: "response"},
+		}},
+		Function:     contracts.AzionJsonDataFunction{ID: 55, InstanceID: 99},
+		DeviceGroups: []contracts.AzionJsonDataDeviceGroup{{Id: 12, Name: "mobile", PreventDestroy: true}},
+	}
+	remote := &Remote{
+		CacheSettings: []RemoteCache{
+			{ID: 7, Cache: contracts.CacheSetting{Name: str("static"), BrowserCacheSettings: str("override"), BrowserCacheSettingsMaximumTtl: ttl(60), CdnCacheSettings: str("honor")}},
+			{ID: 8, Cache: contracts.CacheSetting{}},
+		},
+		Origins: []RemoteOrigin{
+			{ID: 42, Origin: contracts.Origin{Name: "api", OriginType: "single_origin", Addresses: []sdk.CreateOriginsRequestAddresses{{Address: "api.example.com"}}}},
+			{ID: 43, Origin: contracts.Origin{Name: "console-origin", OriginType: "single_origin"}},
+		},
+		Rules: []RemoteRule{
+			{ID: 3, Rule: contracts.RuleEngine{Name: "compute", Phase: "request", Behaviors: []sdk.RulesEngineBehaviorEntry{behavior("run_function", "main")}}, InstanceID: "99"},
+			{ID: 4, Rule: contracts.RuleEngine{Name: "reports", Phase: "request", Behaviors: []sdk.RulesEngineBehaviorEntry{behavior("run_function", "reports")}}, InstanceID: "98"},
+			{ID: 6, Rule: contracts.RuleEngine{Name: "headers", Phase: "request", Order: 4, Description: str("request")}},
+			{ID: 7, Rule: contracts.RuleEngine{Name: "headers", Phase: "response", Order: 1, Description: str("response")}},
+		},
+		Instances: []RemoteInstance{{ID: 99, FunctionID: 55, Name: "main"}, {ID: 98, FunctionID: 54, Name: "reports"}},
+		DeviceGroups: []RemoteDeviceGroup{
+			{ID: 12, Group: contracts.DeviceGroup{Name: "mobile", UserAgent: "iPhone|Android"}},
+		},
+	}
+
+	drifts := Diff(manifest, conf, remote, false)
+	require.Equal(t, []contracts.ResourceDrift{
+		{Resource: ResourceDeviceGroup, Name: "mobile", Id: "12", Field: "user_agent", Manifest: `"iPhone"`, Remote: `"iPhone|Android"`},
+		{Resource: ResourceCache, Name: "static", Id: "7", Field: "browser_cache_settings_maximum_ttl", Manifest: "3600", Remote: "60"},
+		{Resource: ResourceRule, Name: "reports", Id: "4", Field: "behaviors",
+			Manifest: `[{"name":"run_function","target":"main"}]`, Remote: `[{"name":"run_function","target":"reports"}]`},
+		{Resource: ResourceRule, Name: "gone", Id: "5", Details: msg.PlanNotFoundRemotely},
+	}, drifts)
+
+	drifts = Diff(manifest, conf, remote, true)
+	require.Len(t, drifts, 6)
+	require.Equal(t, contracts.ResourceDrift{Resource: ResourceOrigin, Name: "console-origin", Id: "43", Details: msg.DriftNotDeclared}, drifts[4])
+	require.Equal(t, contracts.ResourceDrift{Resource: ResourceCache, Name: "", Id: "8", Details: msg.DriftNotDeclared}, drifts[5])
+}
@@ -0,0 +1,161 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	apiDomain "github.com/aziontech/azion-cli/pkg/api/domain"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+)
+
+// DefaultDomain is the name a domain of the manifest takes to configure the project's domain, the one deploy creates
+// for the edge application
+const DefaultDomain = "__DEFAULT__"
+
+// noCertificate is sent to remove the certificate of a domain
+const noCertificate = "0"
+
+// doDomains creates or updates the domains of the manifest, converging their CNAMEs and certificates, and tracks
+// their hostnames in azion.json
+func (man *ManifestInterpreter) doDomains(
+	ctx context.Context,
+	f *cmdutil.Factory,
+	conf *contracts.AzionApplicationOptions,
+	manifest *contracts.Manifest,
+	msgs *[]string) error {
+	client := apiDomain.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+
+	recorded := make(map[string]int64)
+	for _, domain := range conf.Domains {
+		recorded[domain.Name] = domain.DigitalCertificateId
+	}
+
+	domainConf := []contracts.AzionJsonDataManifestDomain{}
+	for _, domain := range manifest.Domains {
+		// the project's domain is converged by deploy along with the edge application
+		if domain.Name == DefaultDomain {
+			continue
+		}
+
+		var response apiDomain.DomainResponse
+		if id := DomainIds[domain.Name]; id > 0 {
+			updated, err := client.Update(ctx, NewDomainUpdateRequest(id, domain.Name, &domain, conf.Application.ID, recorded[domain.Name]))
+			if err != nil {
+				return fmt.Errorf("%w: %s", msg.ErrorUpdateDomain, err.Error())
+			}
+			response = updated
+			man.changed(ResourceDomain, domain.Name, strconv.FormatInt(updated.GetId(), 10), PlanUpdate)
+			msgf := fmt.Sprintf(msg.ManifestUpdateDomain, domain.Name, updated.GetId())
+			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+			*msgs = append(*msgs, msgf)
+		} else {
+			created, err := client.Create(ctx, NewDomainCreateRequest(domain.Name, &domain, conf.Application.ID))
+			if err != nil {
+				return fmt.Errorf("%w: %s", msg.ErrorCreateDomain, err.Error())
+			}
+			response = created
+			DomainIds[domain.Name] = created.GetId()
+			if err := man.created(contracts.JournalEntry{
+				Resource: ResourceDomain,
+				ID:       created.GetId(),
+				Name:     domain.Name,
+			}); err != nil {
+				return err
+			}
+			man.changed(ResourceDomain, domain.Name, strconv.FormatInt(created.GetId(), 10), PlanCreate)
+			msgf := fmt.Sprintf(msg.ManifestCreateDomain, domain.Name, created.GetId())
+			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+			*msgs = append(*msgs, msgf)
+		}
+
+		domainConf = append(domainConf, contracts.AzionJsonDataManifestDomain{
+			Id:                   response.GetId(),
+			Name:                 domain.Name,
+			DomainName:           response.GetDomainName(),
+			Cnames:               response.GetCnames(),
+			CnameAccessOnly:      response.GetCnameAccessOnly(),
+			DigitalCertificateId: response.GetDigitalCertificateId(),
+			PreventDestroy:       preventDestroy(domain.Lifecycle),
+		})
+	}
+
+	conf.Domains = domainConf
+	return nil
+}
+
+// DeclaredDomain returns the domain of the manifest with the given name, or nil when it isn't declared
+func DeclaredDomain(manifest *contracts.Manifest, name string) *contracts.Domain {
+	if manifest == nil {
+		return nil
+	}
+	for i := range manifest.Domains {
+		if manifest.Domains[i].Name == name {
+			return &manifest.Domains[i]
+		}
+	}
+	return nil
+}
+
+// NewDomainCreateRequest builds the request creating a domain with the settings the manifest declares for it.
+// A domain the manifest doesn't declare is created active, with no CNAMEs and no certificate.
+func NewDomainCreateRequest(name string, domain *contracts.Domain, applicationID int64) *apiDomain.CreateRequest {
+	if domain == nil {
+		domain = &contracts.Domain{}
+	}
+	request := &apiDomain.CreateRequest{}
+	request.SetName(name)
+	request.SetCnames(cnames(domain))
+	request.SetEdgeApplicationId(applicationID)
+	request.SetCnameAccessOnly(domain.CnameAccessOnly != nil && *domain.CnameAccessOnly)
+	if domain.DigitalCertificateId != nil {
+		request.SetDigitalCertificateId(strconv.FormatInt(*domain.DigitalCertificateId, 10))
+	}
+	request.SetIsActive(domain.IsActive == nil || *domain.IsActive)
+	return request
+}
+
+// NewDomainUpdateRequest builds the request converging a domain to the settings the manifest declares for it.
+// certificate is the one the previous deploy recorded for the domain, which is removed once the manifest no longer
+// declares one. A domain the manifest doesn't declare only has its name and edge application updated.
+func NewDomainUpdateRequest(id int64, name string, domain *contracts.Domain, applicationID, certificate int64) *apiDomain.UpdateRequest {
+	request := &apiDomain.UpdateRequest{Id: id}
+	request.SetName(name)
+	request.SetEdgeApplicationId(applicationID)
+	if domain == nil {
+		return request
+	}
+	request.SetCnames(cnames(domain))
+	if domain.CnameAccessOnly != nil {
+		request.SetCnameAccessOnly(*domain.CnameAccessOnly)
+	}
+	if domain.DigitalCertificateId != nil {
+		request.SetDigitalCertificateId(strconv.FormatInt(*domain.DigitalCertificateId, 10))
+	} else if certificate != 0 {
+		request.SetDigitalCertificateId(noCertificate)
+	}
+	if domain.IsActive != nil {
+		request.SetIsActive(*domain.IsActive)
+	}
+	return request
+}
+
+func cnames(domain *contracts.Domain) []string {
+	if domain.Cnames == nil {
+		return []string{}
+	}
+	return domain.Cnames
+}
+
+// Hostnames returns every hostname a domain of the manifest serves: its CNAMEs, and the domain Azion assigned to it
+// unless it is only served through them
+func Hostnames(domain contracts.AzionJsonDataManifestDomain) []string {
+	hostnames := append([]string{}, domain.Cnames...)
+	if !domain.CnameAccessOnly && domain.DomainName != "" {
+		hostnames = append(hostnames, domain.DomainName)
+	}
+	return hostnames
+}
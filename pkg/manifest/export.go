@@ -0,0 +1,393 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	apiCache "github.com/aziontech/azion-cli/pkg/api/cache_setting"
+	apiEdgeApplications "github.com/aziontech/azion-cli/pkg/api/edge_applications"
+	apiOrigin "github.com/aziontech/azion-cli/pkg/api/origin"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	sdk "github.com/aziontech/azionapi-go-sdk/edgeapplications"
+	"go.uber.org/zap"
+)
+
+// defaultRule is created along with every edge application and managed by deploy itself, so it is not exported
+const defaultRule = "Default Rule"
+
+// Export describes the origins, device groups, cache settings and rules of an existing edge application as a manifest, along with
+// the azion.json that tracks them, so the next deploy updates these resources instead of creating new ones.
+// Rule targets are turned from IDs back into the names of the cache settings and origins, as the manifest expects.
+func Export(ctx context.Context, f *cmdutil.Factory, applicationID int64) (*contracts.Manifest, *contracts.AzionApplicationOptions, error) {
+	client := apiEdgeApplications.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+
+	application, err := client.Get(ctx, strconv.FormatInt(applicationID, 10))
+	if err != nil {
+		logger.Debug("Error while reading the edge application", zap.Int64("id", applicationID), zap.Error(err))
+		return nil, nil, fmt.Errorf(msg.ErrorExportApplication.Error(), applicationID, err)
+	}
+
+	remote, err := ReadRemote(ctx, f, applicationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manifest := &contracts.Manifest{
+		CacheSettings: []contracts.CacheSetting{},
+		Origins:       []contracts.Origin{},
+		Rules:         []contracts.RuleEngine{},
+	}
+	conf := &contracts.AzionApplicationOptions{
+		Name:        application.GetName(),
+		NotFirstRun: true,
+		Application: contracts.AzionJsonDataApplication{ID: applicationID, Name: application.GetName()},
+		RulesEngine: contracts.AzionJsonDataRulesEngine{Created: true},
+	}
+
+	for _, origin := range remote.Origins {
+		manifest.Origins = append(manifest.Origins, origin.Origin)
+		conf.Origin = append(conf.Origin, contracts.AzionJsonDataOrigin{
+			OriginId:  origin.ID,
+			OriginKey: origin.Key,
+			Name:      origin.Origin.Name,
+		})
+		// the bucket served by the application is the one deploy uploads to
+		if origin.Origin.OriginType == "object_storage" && conf.Bucket == "" {
+			conf.Bucket = origin.Origin.Bucket
+		}
+	}
+
+	for _, group := range remote.DeviceGroups {
+		manifest.DeviceGroups = append(manifest.DeviceGroups, group.Group)
+		conf.DeviceGroups = append(conf.DeviceGroups, contracts.AzionJsonDataDeviceGroup{
+			Id:   group.ID,
+			Name: group.Group.Name,
+		})
+	}
+
+	for _, cache := range remote.CacheSettings {
+		manifest.CacheSettings = append(manifest.CacheSettings, cache.Cache)
+		conf.CacheSettings = append(conf.CacheSettings, contracts.AzionJsonDataCacheSettings{
+			Id:   cache.ID,
+			Name: *cache.Cache.Name,
+		})
+	}
+
+	for _, rule := range remote.Rules {
+		if rule.InstanceID != "" && conf.Function.InstanceID == 0 {
+			adoptInstance(conf, remote.Instances, rule.InstanceID)
+		}
+		// the manifest has no functions section, so its rules run the instance of the project's function
+		if rule.InstanceID != "" && rule.InstanceID == strconv.FormatInt(conf.Function.InstanceID, 10) {
+			rule.Rule = withoutFunctionTarget(rule.Rule)
+		}
+		manifest.Rules = append(manifest.Rules, rule.Rule)
+		conf.RulesEngine.Rules = append(conf.RulesEngine.Rules, contracts.AzionJsonDataRules{
+			Id:    rule.ID,
+			Name:  rule.Rule.Name,
+			Phase: rule.Rule.Phase,
+		})
+	}
+
+	return manifest, conf, nil
+}
+
+// Remote is the configuration of an edge application in the shape of the manifest, along with the IDs of its resources
+type Remote struct {
+	Origins       []RemoteOrigin
+	CacheSettings []RemoteCache
+	Rules         []RemoteRule
+	DeviceGroups  []RemoteDeviceGroup
+	Instances     []RemoteInstance
+}
+
+type RemoteOrigin struct {
+	ID     int64
+	Key    string
+	Origin contracts.Origin
+}
+
+type RemoteCache struct {
+	ID    int64
+	Cache contracts.CacheSetting
+}
+
+type RemoteDeviceGroup struct {
+	ID    int64
+	Group contracts.DeviceGroup
+}
+
+type RemoteInstance struct {
+	ID         int64
+	FunctionID int64
+	Name       string
+}
+
+type RemoteRule struct {
+	ID   int64
+	Rule contracts.RuleEngine
+	// InstanceID is the function instance a run_function behavior of the rule points to
+	InstanceID string
+}
+
+// ReadRemote reads the origins, device groups, cache settings, function instances and rules of an edge application,
+// leaving the default rule out
+func ReadRemote(ctx context.Context, f *cmdutil.Factory, applicationID int64) (*Remote, error) {
+	client := apiEdgeApplications.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+	clientCache := apiCache.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+	clientOrigin := apiOrigin.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+	remote := &Remote{}
+
+	origins, err := listOrigins(ctx, clientOrigin, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf(msg.ErrorExportResources.Error(), ResourceOrigin, err)
+	}
+	originNames := make(map[string]string)
+	for _, origin := range origins {
+		remote.Origins = append(remote.Origins, RemoteOrigin{
+			ID:     origin.GetOriginId(),
+			Key:    origin.GetOriginKey(),
+			Origin: exportOrigin(origin),
+		})
+		originNames[strconv.FormatInt(origin.GetOriginId(), 10)] = origin.GetName()
+	}
+
+	groupNames := make(map[int64]string)
+	opts := &contracts.ListOptions{PageSize: 100, Page: 1}
+	for {
+		groups, err := client.DeviceGroupsList(ctx, opts, applicationID)
+		if err != nil {
+			return nil, fmt.Errorf(msg.ErrorExportResources.Error(), ResourceDeviceGroup, err)
+		}
+		for _, group := range groups.GetResults() {
+			remote.DeviceGroups = append(remote.DeviceGroups, RemoteDeviceGroup{
+				ID:    group.GetId(),
+				Group: contracts.DeviceGroup{Name: group.GetName(), UserAgent: group.GetUserAgent()},
+			})
+			groupNames[group.GetId()] = group.GetName()
+		}
+		if opts.Page >= groups.GetTotalPages() {
+			break
+		}
+		opts.Page++
+	}
+
+	cacheNames := make(map[string]string)
+	opts = &contracts.ListOptions{PageSize: 100, Page: 1}
+	for {
+		caches, err := clientCache.List(ctx, opts, applicationID)
+		if err != nil {
+			return nil, fmt.Errorf(msg.ErrorExportResources.Error(), ResourceCache, err)
+		}
+		for _, cache := range caches.GetResults() {
+			remote.CacheSettings = append(remote.CacheSettings, RemoteCache{ID: cache.GetId(), Cache: exportCache(cache, groupNames)})
+			cacheNames[strconv.FormatInt(cache.GetId(), 10)] = cache.GetName()
+		}
+		if opts.Page >= caches.GetTotalPages() {
+			break
+		}
+		opts.Page++
+	}
+
+	instances, err := listInstances(ctx, client, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf(msg.ErrorExportResources.Error(), "function_instance", err)
+	}
+	instanceNames := make(map[string]string)
+	for _, instance := range instances {
+		remote.Instances = append(remote.Instances, RemoteInstance{
+			ID:         instance.GetId(),
+			FunctionID: instance.GetEdgeFunctionId(),
+			Name:       instance.GetName(),
+		})
+		instanceNames[strconv.FormatInt(instance.GetId(), 10)] = instance.GetName()
+	}
+
+	for _, phase := range apiEdgeApplications.RulesEnginePhases {
+		opts := &contracts.ListOptions{PageSize: 100, Page: 1}
+		for {
+			rules, err := client.ListRulesEngine(ctx, opts, applicationID, phase)
+			if err != nil {
+				return nil, fmt.Errorf(msg.ErrorExportResources.Error(), ResourceRule, err)
+			}
+			for _, rule := range rules.GetResults() {
+				if rule.GetName() == defaultRule {
+					continue
+				}
+				exported, instanceID := exportRule(rule, cacheNames, originNames, instanceNames, groupNames)
+				remote.Rules = append(remote.Rules, RemoteRule{ID: rule.GetId(), Rule: exported, InstanceID: instanceID})
+			}
+			if opts.Page >= rules.GetTotalPages() {
+				break
+			}
+			opts.Page++
+		}
+	}
+
+	return remote, nil
+}
+
+// listOrigins reads the origins of an edge application from every page
+func listOrigins(ctx context.Context, client *apiOrigin.Client, applicationID int64) ([]sdk.OriginsResultResponse, error) {
+	origins := []sdk.OriginsResultResponse{}
+	opts := &contracts.ListOptions{PageSize: 100, Page: 1}
+	for {
+		resp, err := client.ListOrigins(ctx, opts, applicationID)
+		if err != nil {
+			return nil, err
+		}
+		origins = append(origins, resp.GetResults()...)
+		if opts.Page >= resp.GetTotalPages() {
+			return origins, nil
+		}
+		opts.Page++
+	}
+}
+
+// listInstances reads the function instances of an edge application from every page
+func listInstances(ctx context.Context, client *apiEdgeApplications.Client, applicationID int64) ([]sdk.ApplicationInstancesResults, error) {
+	instances := []sdk.ApplicationInstancesResults{}
+	opts := &contracts.ListOptions{PageSize: 100, Page: 1}
+	for {
+		resp, err := client.EdgeFuncInstancesList(ctx, opts, applicationID)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, resp.GetResults()...)
+		if opts.Page >= resp.GetTotalPages() {
+			return instances, nil
+		}
+		opts.Page++
+	}
+}
+
+func exportOrigin(origin sdk.OriginsResultResponse) contracts.Origin {
+	exported := contracts.Origin{
+		Name:       origin.GetName(),
+		OriginType: origin.GetOriginType(),
+	}
+	switch origin.GetOriginType() {
+	case "object_storage":
+		exported.Bucket = origin.GetBucket()
+		exported.Prefix = origin.GetPrefix()
+	default:
+		exported.HostHeader = origin.GetHostHeader()
+		for _, address := range origin.GetAddresses() {
+			exported.Addresses = append(exported.Addresses, sdk.CreateOriginsRequestAddresses{Address: address.GetAddress()})
+		}
+	}
+	return exported
+}
+
+// exportCache names the device groups of the cache setting, keeping the IDs of the ones it could not find
+func exportCache(cache sdk.ApplicationCacheResults, groupNames map[int64]string) contracts.CacheSetting {
+	exported := contracts.CacheSetting{
+		Name:                           &cache.Name,
+		BrowserCacheSettings:           &cache.BrowserCacheSettings,
+		BrowserCacheSettingsMaximumTtl: &cache.BrowserCacheSettingsMaximumTtl,
+		CdnCacheSettings:               &cache.CdnCacheSettings,
+		CdnCacheSettingsMaximumTtl:     &cache.CdnCacheSettingsMaximumTtl,
+		CacheByQueryString:             &cache.CacheByQueryString,
+		QueryStringFields:              cache.QueryStringFields,
+		EnableQueryStringSort:          &cache.EnableQueryStringSort,
+		CacheByCookies:                 &cache.CacheByCookies,
+		EnableCachingForPost:           &cache.EnableCachingForPost,
+		L2CachingEnabled:               &cache.L2CachingEnabled,
+		IsSliceConfigurationEnabled:    cache.IsSliceConfigurationEnabled,
+		IsSliceEdgeCachingEnabled:      cache.IsSliceEdgeCachingEnabled,
+		IsSliceL2CachingEnabled:        cache.IsSliceL2CachingEnabled,
+		SliceConfigurationRange:        cache.SliceConfigurationRange,
+		EnableCachingForOptions:        &cache.EnableCachingForOptions,
+		EnableStaleCache:               &cache.EnableStaleCache,
+	}
+	if cache.AdaptiveDeliveryAction != "" {
+		exported.AdaptiveDeliveryAction = &cache.AdaptiveDeliveryAction
+	}
+	if cache.L2Region.IsSet() && cache.L2Region.Get() != nil {
+		exported.L2Region = cache.L2Region.Get()
+	}
+	for _, id := range cache.DeviceGroup {
+		if name, ok := groupNames[int64(id)]; ok {
+			exported.DeviceGroups = append(exported.DeviceGroups, name)
+			continue
+		}
+		exported.DeviceGroup = append(exported.DeviceGroup, id)
+	}
+	for _, name := range cache.CookieNames {
+		if name != nil {
+			exported.CookieNames = append(exported.CookieNames, *name)
+		}
+	}
+	return exported
+}
+
+// exportRule is the inverse of makeRuleRequestCreate: the IDs set_cache_policy, set_origin, run_function and
+// ${device_group} criteria point to are replaced by names. It also returns the function instance run_function points to, if any.
+func exportRule(
+	rule sdk.RulesEngineResultResponse,
+	cacheNames, originNames, instanceNames map[string]string,
+	groupNames map[int64]string) (contracts.RuleEngine, string) {
+	exported := contracts.RuleEngine{
+		Name:        rule.GetName(),
+		Description: rule.Description,
+		Phase:       rule.GetPhase(),
+		Order:       rule.GetOrder(),
+		IsActive:    rule.GetIsActive(),
+	}
+
+	for _, group := range rule.GetCriteria() {
+		criteria := make([]sdk.RulesEngineCriteria, 0, len(group))
+		for _, criterion := range group {
+			if criterion.Variable == deviceGroupVariable && criterion.InputValue != nil {
+				id, err := strconv.ParseInt(*criterion.InputValue, 10, 64)
+				if name, ok := groupNames[id]; err == nil && ok {
+					criterion.InputValue = &name
+				}
+			}
+			criteria = append(criteria, criterion)
+		}
+		exported.Criteria = append(exported.Criteria, criteria)
+	}
+
+	instanceID := ""
+	for _, behavior := range rule.GetBehaviors() {
+		if behavior.RulesEngineBehaviorString != nil {
+			behaviorString := *behavior.RulesEngineBehaviorString
+			switch behaviorString.Name {
+			case "set_cache_policy":
+				if name, ok := cacheNames[behaviorString.Target]; ok {
+					behaviorString.Target = name
+				}
+			case "set_origin":
+				if name, ok := originNames[behaviorString.Target]; ok {
+					behaviorString.Target = name
+				}
+			case "run_function":
+				instanceID = behaviorString.Target
+				if name, ok := instanceNames[behaviorString.Target]; ok {
+					behaviorString.Target = name
+				}
+			}
+			behavior = sdk.RulesEngineBehaviorEntry{RulesEngineBehaviorString: &behaviorString}
+		}
+		exported.Behaviors = append(exported.Behaviors, behavior)
+	}
+	return exported, instanceID
+}
+
+// adoptInstance tracks the function instance a rule runs as the one of the project, so deploy updates its function
+func adoptInstance(conf *contracts.AzionApplicationOptions, instances []RemoteInstance, instanceID string) {
+	for _, instance := range instances {
+		if strconv.FormatInt(instance.ID, 10) != instanceID {
+			continue
+		}
+		conf.Function.ID = instance.FunctionID
+		conf.Function.InstanceID = instance.ID
+		conf.Function.InstanceName = instance.Name
+		return
+	}
+}
@@ -0,0 +1,324 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// manifestFiles are the names a manifest may have, in the order they are looked up
+	manifestFiles = []string{"manifest.json", "manifest.yaml", "manifest.yml", "manifest.toml"}
+
+	// interpolation matches ${VAR}, ${VAR:-default} and the escaped $${VAR}. Only upper case names are replaced, so the
+	// variables of the rules engine, such as ${uri}, are left alone
+	interpolation = regexp.MustCompile(`(\$)?\$\{([A-Z_][A-Z0-9_]*)(:-([^}]*))?\}`)
+
+	pathSegment = regexp.MustCompile(`\.([^.\[]+)|\[(\d+)\]`)
+)
+
+// FindManifest returns the path of the manifest in dir, in any of the formats it may be written in. Without one, it
+// returns the path of manifest.json.
+func FindManifest(dir string) string {
+	for _, name := range manifestFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dir, manifestFiles[0])
+}
+
+// decodeManifest turns a manifest into JSON, whatever its format, replacing the environment variables its values name
+func (man *ManifestInterpreter) decodeManifest(path string, raw []byte) ([]byte, error) {
+	decoded, _, err := man.decode(path, raw)
+	return decoded, err
+}
+
+// decode is decodeManifest along with the variables replaced in a JSON manifest, which locate the problems found in
+// it in its source. YAML and TOML manifests are replaced value by value once parsed, so values need no quoting.
+func (man *ManifestInterpreter) decode(path string, raw []byte) ([]byte, edits, error) {
+	lookupEnv, err := man.environment()
+	if err != nil {
+		return nil, nil, err
+	}
+	r := &replacer{lookupEnv: lookupEnv}
+
+	var content interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		doc := &yaml.Node{}
+		if err = yaml.Unmarshal(raw, doc); err == nil {
+			r.node(doc)
+			err = doc.Decode(&content)
+		}
+	case ".toml":
+		table := map[string]interface{}{}
+		if err = toml.Unmarshal(raw, &table); err == nil {
+			r.value(table)
+			content = table
+		}
+	default:
+		raw, changes := r.json(raw)
+		if err := r.err(); err != nil {
+			return nil, nil, fmt.Errorf(msg.ErrorInterpolateManifest.Error(), path, err)
+		}
+		return raw, changes, nil
+	}
+	if err != nil {
+		logger.Debug("Error while decoding the manifest", zap.String("path", path), zap.Error(err))
+		return nil, nil, fmt.Errorf(msg.ErrorDecodeManifest.Error(), path, err)
+	}
+	if err := r.err(); err != nil {
+		return nil, nil, fmt.Errorf(msg.ErrorInterpolateManifest.Error(), path, err)
+	}
+
+	converted, err := json.Marshal(content)
+	if err != nil {
+		logger.Debug("Error while converting the manifest to JSON", zap.String("path", path), zap.Error(err))
+		return nil, nil, fmt.Errorf(msg.ErrorDecodeManifest.Error(), path, err)
+	}
+	return converted, nil, nil
+}
+
+// environment looks names up in the environment of the process and then in the .env file of the project
+func (man *ManifestInterpreter) environment() (func(key string) (string, bool), error) {
+	envs := make(map[string]string)
+	if man.EnvPath != "" {
+		read, err := godotenv.Read(man.EnvPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Debug("Error while loading .env file", zap.Error(err))
+			return nil, fmt.Errorf(msg.ErrorReadEnvFile.Error(), man.EnvPath, err)
+		}
+		if err == nil {
+			envs = read
+		}
+	}
+
+	lookupEnv := man.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	return func(key string) (string, bool) {
+		if value, ok := lookupEnv(key); ok {
+			return value, true
+		}
+		value, ok := envs[key]
+		return value, ok
+	}, nil
+}
+
+// replacer replaces ${VAR} with the value of VAR, and ${VAR:-default} with the default when VAR is unset or empty.
+// $${VAR} is kept as ${VAR}. Variables that are unset and have no default are reported together.
+type replacer struct {
+	lookupEnv func(key string) (string, bool)
+	missing   []string
+}
+
+func (r *replacer) replace(match []string) string {
+	if match[1] != "" {
+		return match[0][1:]
+	}
+	value, ok := r.lookupEnv(match[2])
+	if match[3] != "" && value == "" {
+		value, ok = match[4], true
+	}
+	if !ok {
+		r.missing = append(r.missing, match[2])
+		return match[0]
+	}
+	return value
+}
+
+func (r *replacer) string(s string) string {
+	return interpolation.ReplaceAllStringFunc(s, func(match string) string {
+		return r.replace(interpolation.FindStringSubmatch(match))
+	})
+}
+
+// node replaces the variables of the values of a YAML document. Plain values are typed again once replaced, so
+// port: ${PORT} is still a number.
+func (r *replacer) node(n *yaml.Node) {
+	switch n.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range n.Content {
+			r.node(child)
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			r.node(n.Content[i])
+		}
+	case yaml.ScalarNode:
+		value := r.string(n.Value)
+		if value != n.Value && n.Style == 0 {
+			n.Tag = ""
+		}
+		n.Value = value
+	}
+}
+
+// value replaces the variables of the strings of a decoded document
+func (r *replacer) value(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, item := range t {
+			t[key] = r.value(item)
+		}
+	case []interface{}:
+		for i, item := range t {
+			t[i] = r.value(item)
+		}
+	case string:
+		return r.string(t)
+	}
+	return v
+}
+
+// json replaces the variables of a JSON document. Values end up inside JSON strings, so they are escaped the way the
+// strings are.
+func (r *replacer) json(raw []byte) ([]byte, edits) {
+	result := []byte{}
+	changes := edits{}
+	last := 0
+	for _, loc := range interpolation.FindAllSubmatchIndex(raw, -1) {
+		match := make([]string, len(loc)/2)
+		for i := range match {
+			if loc[2*i] >= 0 {
+				match[i] = string(raw[loc[2*i]:loc[2*i+1]])
+			}
+		}
+		value := r.replace(match)
+		if match[1] == "" {
+			quoted, _ := json.Marshal(value)
+			value = string(quoted[1 : len(quoted)-1])
+		}
+
+		result = append(result, raw[last:loc[0]]...)
+		changes = append(changes, edit{
+			source:    int64(loc[0]),
+			sourceLen: int64(loc[1] - loc[0]),
+			result:    int64(len(result)),
+			resultLen: int64(len(value)),
+		})
+		result = append(result, value...)
+		last = loc[1]
+	}
+	return append(result, raw[last:]...), changes
+}
+
+func (r *replacer) err() error {
+	if len(r.missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf(msg.ErrorMissingManifestVariables.Error(), strings.Join(r.missing, ", "))
+}
+
+// edit is a variable replaced in a JSON manifest, by where it starts and how long it is in the source and in the result
+type edit struct {
+	source, sourceLen int64
+	result, resultLen int64
+}
+
+type edits []edit
+
+// source returns where an offset of the replaced manifest is in its source. Offsets inside a value point to the
+// variable it replaced.
+func (e edits) source(offset int64) int64 {
+	shift := int64(0)
+	for _, change := range e {
+		if offset < change.result {
+			break
+		}
+		if offset < change.result+change.resultLen {
+			return change.source
+		}
+		shift = change.source + change.sourceLen - change.result - change.resultLen
+	}
+	return offset + shift
+}
+
+// relocate points the problems found in a manifest converted to JSON back to where they are in its source. Only
+// YAML keeps track of positions; problems in a TOML manifest are located by their path alone.
+func relocate(err error, path string, source []byte) error {
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		return err
+	}
+
+	format := strings.ToLower(filepath.Ext(path))
+	if format != ".yaml" && format != ".yml" && format != ".toml" {
+		return err
+	}
+
+	var root *yaml.Node
+	if format != ".toml" {
+		doc := &yaml.Node{}
+		if yaml.Unmarshal(source, doc) == nil && len(doc.Content) > 0 {
+			root = doc.Content[0]
+		}
+	}
+	for i := range errs {
+		errs[i].Line, errs[i].Column = yamlPosition(root, errs[i].Path)
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+	return errs
+}
+
+// yamlPosition finds the line and column of a path such as $.rules[0].name in a YAML document. Keys are located by
+// the key itself, so unknown keys point to where they are written.
+func yamlPosition(root *yaml.Node, path string) (int, int) {
+	if root == nil {
+		return 0, 0
+	}
+
+	n := root
+	line, column := n.Line, n.Column
+	for _, segment := range pathSegment.FindAllStringSubmatch(path, -1) {
+		if n.Kind == yaml.AliasNode {
+			n = n.Alias
+		}
+		switch {
+		case segment[2] != "" && n.Kind == yaml.SequenceNode:
+			index, _ := strconv.Atoi(segment[2])
+			if index >= len(n.Content) {
+				return line, column
+			}
+			n = n.Content[index]
+			line, column = n.Line, n.Column
+		case segment[1] != "" && n.Kind == yaml.MappingNode:
+			found := false
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == segment[1] {
+					line, column = n.Content[i].Line, n.Content[i].Column
+					n = n.Content[i+1]
+					found = true
+					break
+				}
+			}
+			if !found {
+				return line, column
+			}
+		default:
+			return line, column
+		}
+	}
+	return line, column
+}
@@ -0,0 +1,193 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormats(t *testing.T) {
+	environment := func(envs map[string]string) func(key string) (string, bool) {
+		return func(key string) (string, bool) {
+			value, ok := envs[key]
+			return value, ok
+		}
+	}
+	files := func(contents map[string]string) func(path string) ([]byte, error) {
+		return func(path string) ([]byte, error) {
+			if content, ok := contents[path]; ok {
+				return []byte(content), nil
+			}
+			return nil, os.ErrNotExist
+		}
+	}
+
+	t.Run("find the manifest in any format", func(t *testing.T) {
+		dir := t.TempDir()
+		require.Equal(t, filepath.Join(dir, "manifest.json"), FindManifest(dir))
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "manifest.toml"), []byte(""), 0644))
+		require.Equal(t, filepath.Join(dir, "manifest.toml"), FindManifest(dir))
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(""), 0644))
+		require.Equal(t, filepath.Join(dir, "manifest.yaml"), FindManifest(dir))
+	})
+
+	t.Run("interpolate environment variables", func(t *testing.T) {
+		r := &replacer{lookupEnv: environment(map[string]string{"HOST": `api."quoted".com`, "EMPTY": ""})}
+
+		result := r.string(`${HOST} ${PORT:-8080} ${EMPTY:-fallback} ${EMPTY} ${uri} $${HOST}`)
+		require.NoError(t, r.err())
+		require.Equal(t, `api."quoted".com 8080 fallback  ${uri} ${HOST}`, result)
+
+		r.string(`${HOST} ${PORT} $${SECRET} ${TOKEN}`)
+		require.ErrorContains(t, r.err(), "PORT, TOKEN not set")
+	})
+
+	t.Run("load a JSON manifest with escaped values", func(t *testing.T) {
+		man := NewManifestInterpreter()
+		man.EnvPath = ""
+		man.LookupEnv = environment(map[string]string{"HOST": `api."quoted".com`})
+		man.FileReader = files(map[string]string{
+			"manifest.json": `{"origin": [{"name": "api", "origin_type": "single_origin",
+				"addresses": [{"address": "${HOST}"}], "host_header": "$${HOST}"}]}`,
+		})
+
+		manifest, err := man.LoadManifest("manifest.json")
+		require.NoError(t, err)
+		require.Equal(t, `api."quoted".com`, manifest.Origins[0].Addresses[0].Address)
+		require.Equal(t, "${HOST}", manifest.Origins[0].HostHeader)
+	})
+
+	t.Run("report the variables that aren't set", func(t *testing.T) {
+		man := NewManifestInterpreter()
+		man.EnvPath = ""
+		man.LookupEnv = environment(nil)
+		man.FileReader = files(map[string]string{
+			"manifest.json": `{"origin": [{"name": "api", "origin_type": "single_origin", "addresses": [{"address": "${HOST}"}]}]}`,
+			"manifest.yaml": "origin:\n  - name: api\n    addresses:\n      - address: ${HOST}\n",
+		})
+
+		for _, path := range []string{"manifest.json", "manifest.yaml"} {
+			_, err := man.LoadManifest(path)
+			require.ErrorContains(t, err, "HOST not set")
+		}
+	})
+
+	t.Run("YAML values are replaced once parsed", func(t *testing.T) {
+		man := NewManifestInterpreter()
+		man.EnvPath = ""
+		man.LookupEnv = environment(map[string]string{"HEADER": "api: main # staging", "TTL": "3600"})
+		man.FileReader = files(map[string]string{
+			"manifest.yaml": `
+origin:
+  - name: api
+    host_header: ${HEADER}
+cache:
+  - name: static
+    browser_cache_settings: override
+    browser_cache_settings_maximum_ttl: ${TTL}
+`,
+		})
+
+		require.NoError(t, man.ValidateManifest("manifest.yaml"))
+		manifest, err := man.LoadManifest("manifest.yaml")
+		require.NoError(t, err)
+		require.Equal(t, "api: main # staging", manifest.Origins[0].HostHeader)
+		require.Equal(t, int64(3600), *manifest.CacheSettings[0].BrowserCacheSettingsMaximumTtl)
+	})
+
+	t.Run("locate the problems of a JSON manifest in its source", func(t *testing.T) {
+		man := NewManifestInterpreter()
+		man.EnvPath = ""
+		man.LookupEnv = environment(map[string]string{"NAME": "a-much-longer-name-than-the-variable"})
+		man.FileReader = files(map[string]string{
+			"manifest.json": "{\n\"origin\": [{\"name\": \"${NAME}\", \"colour\": \"blue\"}]}",
+		})
+
+		err := man.ValidateManifest("manifest.json")
+		require.ErrorIs(t, err, msg.ErrorInvalidManifest)
+		require.Equal(t, ValidationErrors{
+			{Path: "$.origin[0].colour", Line: 2, Column: 32, Message: "unknown key 'colour'"},
+		}, err)
+	})
+
+	t.Run("load YAML and TOML manifests", func(t *testing.T) {
+		envPath := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(envPath, []byte("HOST=staging.example.com\n"), 0644))
+
+		man := NewManifestInterpreter()
+		man.EnvPath = envPath
+		man.LookupEnv = environment(nil)
+		man.FileReader = files(map[string]string{
+			"manifest.yaml": `
+# origins of the project
+origin:
+  - name: api
+    origin_type: single_origin
+    addresses:
+      - address: ${HOST}
+rules:
+  - name: api
+    phase: request
+    is_active: true
+    criteria:
+      - - variable: ${uri}
+          operator: starts_with
+          conditional: if
+          input_value: /api
+    behaviors:
+      - name: set_origin
+        target: api
+`,
+			"manifest.toml": `
+[[origin]]
+name = "api"
+origin_type = "single_origin"
+addresses = [{ address = "${HOST:-localhost}" }]
+
+[[rules]]
+name = "api"
+phase = "request"
+is_active = true
+criteria = [[{ variable = "${uri}", operator = "starts_with", conditional = "if", input_value = "/api" }]]
+behaviors = [{ name = "set_origin", target = "api" }]
+`,
+		})
+
+		for _, path := range []string{"manifest.yaml", "manifest.toml"} {
+			require.NoError(t, man.ValidateManifest(path))
+			manifest, err := man.LoadManifest(path)
+			require.NoError(t, err)
+			require.Equal(t, "staging.example.com", manifest.Origins[0].Addresses[0].Address)
+			require.Equal(t, "${uri}", manifest.Rules[0].Criteria[0][0].Variable)
+			require.Len(t, manifest.Rules, 1)
+		}
+	})
+
+	t.Run("locate the problems of YAML and TOML manifests", func(t *testing.T) {
+		man := NewManifestInterpreter()
+		man.EnvPath = ""
+		man.FileReader = files(map[string]string{
+			"manifest.yaml": "origin:\n  - name: api\n    addresses: nope\ncache:\n  - name: c\n    colour: blue\n",
+			"manifest.toml": "[[cache]]\nbrowser_cache_settings = \"honor\"\n",
+		})
+
+		err := man.ValidateManifest("manifest.yaml")
+		require.ErrorIs(t, err, msg.ErrorInvalidManifest)
+		require.Equal(t, ValidationErrors{
+			{Path: "$.origin[0].addresses", Line: 3, Column: 5, Message: "expected array, found string"},
+			{Path: "$.cache[0].colour", Line: 6, Column: 5, Message: "unknown key 'colour'"},
+		}, err)
+
+		err = man.ValidateManifest("manifest.toml")
+		require.ErrorIs(t, err, msg.ErrorInvalidManifest)
+		require.Equal(t, ValidationErrors{
+			{Path: "$.cache[0]", Message: "cache settings must have a name"},
+		}, err)
+		require.Contains(t, err.Error(), "$.cache[0]: cache settings must have a name")
+	})
+}
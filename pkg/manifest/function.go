@@ -0,0 +1,208 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	apiEdgeApplications "github.com/aziontech/azion-cli/pkg/api/edge_applications"
+	apiEdgeFunction "github.com/aziontech/azion-cli/pkg/api/edge_function"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// doFunctions creates or updates the functions of the manifest and their instances, tracking them in azion.json.
+// Instances are named after their function when the manifest declares none.
+func (man *ManifestInterpreter) doFunctions(
+	ctx context.Context,
+	f *cmdutil.Factory,
+	conf *contracts.AzionApplicationOptions,
+	manifest *contracts.Manifest,
+	msgs *[]string) error {
+	client := apiEdgeApplications.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+	clientFunction := apiEdgeFunction.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+
+	functionConf := []contracts.AzionJsonDataManifestFunction{}
+	instanceConf := []contracts.AzionJsonDataFunctionInstance{}
+	for _, function := range manifest.Functions {
+		code, err := man.readFunctionFile(function.Path, function.Name)
+		if err != nil {
+			return err
+		}
+		args, err := man.readArgs(function.Args, function.Name)
+		if err != nil {
+			return err
+		}
+		active := function.Active == nil || *function.Active
+
+		var functionId int64
+		if id := FunctionIds[function.Name]; id > 0 {
+			requestUpdate := apiEdgeFunction.UpdateRequest{}
+			requestUpdate.SetName(function.Name)
+			requestUpdate.SetCode(string(code))
+			requestUpdate.SetJsonArgs(args)
+			requestUpdate.SetActive(active)
+			updated, err := clientFunction.Update(ctx, &requestUpdate, id)
+			if err != nil {
+				return fmt.Errorf("%w: %s", msg.ErrorUpdateFunction, err.Error())
+			}
+			functionId = updated.GetId()
+			man.changed(ResourceFunction, function.Name, strconv.FormatInt(functionId, 10), PlanUpdate)
+			msgf := fmt.Sprintf(msg.ManifestUpdateFunction, function.Name, functionId)
+			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+			*msgs = append(*msgs, msgf)
+		} else {
+			requestCreate := apiEdgeFunction.CreateRequest{}
+			requestCreate.SetName(function.Name)
+			requestCreate.SetCode(string(code))
+			requestCreate.SetJsonArgs(args)
+			requestCreate.SetActive(active)
+			created, err := clientFunction.Create(ctx, &requestCreate)
+			if err != nil {
+				return fmt.Errorf("%w: %s", msg.ErrorCreateFunction, err.Error())
+			}
+			functionId = created.GetId()
+			FunctionIds[function.Name] = functionId
+			if err := man.created(contracts.JournalEntry{
+				Resource: ResourceFunction,
+				ID:       functionId,
+				Name:     function.Name,
+			}); err != nil {
+				return err
+			}
+			man.changed(ResourceFunction, function.Name, strconv.FormatInt(functionId, 10), PlanCreate)
+			msgf := fmt.Sprintf(msg.ManifestCreateFunction, function.Name, functionId)
+			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+			*msgs = append(*msgs, msgf)
+		}
+		functionConf = append(functionConf, contracts.AzionJsonDataManifestFunction{
+			Id:             functionId,
+			Name:           function.Name,
+			PreventDestroy: preventDestroy(function.Lifecycle),
+		})
+
+		for _, instance := range instancesOf(function) {
+			instanceArgs := args
+			if instance.Args != "" {
+				instanceArgs, err = man.readArgs(instance.Args, function.Name)
+				if err != nil {
+					return err
+				}
+			}
+
+			var instanceId int64
+			if id := FunctionInstanceIds[instance.Name]; id > 0 {
+				requestUpdate := apiEdgeApplications.UpdateInstanceRequest{}
+				requestUpdate.SetName(instance.Name)
+				requestUpdate.SetEdgeFunctionId(functionId)
+				requestUpdate.SetArgs(instanceArgs)
+				updated, err := client.UpdateInstance(ctx, &requestUpdate,
+					strconv.FormatInt(conf.Application.ID, 10), strconv.FormatInt(id, 10))
+				if err != nil {
+					return fmt.Errorf("%w: %s", msg.ErrorUpdateInstance, err.Error())
+				}
+				instanceId = updated.GetId()
+				man.changed(ResourceFunctionInstance, instance.Name, strconv.FormatInt(instanceId, 10), PlanUpdate)
+				msgf := fmt.Sprintf(msg.ManifestUpdateFunctionInstance, instance.Name, instanceId)
+				logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+				*msgs = append(*msgs, msgf)
+			} else {
+				requestCreate := apiEdgeApplications.CreateInstanceRequest{}
+				requestCreate.SetName(instance.Name)
+				requestCreate.SetEdgeFunctionId(functionId)
+				requestCreate.SetArgs(instanceArgs)
+				requestCreate.ApplicationId = conf.Application.ID
+				created, err := client.CreateFuncInstances(ctx, &requestCreate, conf.Application.ID)
+				if err != nil {
+					return fmt.Errorf("%w: %s", msg.ErrorCreateInstance, err.Error())
+				}
+				instanceId = created.GetId()
+				FunctionInstanceIds[instance.Name] = instanceId
+				if err := man.created(contracts.JournalEntry{
+					Resource:      ResourceFunctionInstance,
+					ID:            instanceId,
+					Name:          instance.Name,
+					ApplicationID: conf.Application.ID,
+				}); err != nil {
+					return err
+				}
+				man.changed(ResourceFunctionInstance, instance.Name, strconv.FormatInt(instanceId, 10), PlanCreate)
+				msgf := fmt.Sprintf(msg.ManifestCreateFunctionInstance, instance.Name, instanceId)
+				logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+				*msgs = append(*msgs, msgf)
+			}
+			instanceConf = append(instanceConf, contracts.AzionJsonDataFunctionInstance{
+				Id:             instanceId,
+				Name:           instance.Name,
+				PreventDestroy: preventDestroy(function.Lifecycle),
+			})
+		}
+	}
+
+	conf.Functions = functionConf
+	conf.FunctionInstances = instanceConf
+	return nil
+}
+
+// instancesOf returns the instances declared for a function, or a single one named after it
+func instancesOf(function contracts.Function) []contracts.FunctionInstance {
+	if len(function.Instances) > 0 {
+		return function.Instances
+	}
+	return []contracts.FunctionInstance{{Name: function.Name}}
+}
+
+// readFunctionFile reads a file the manifest names, relative to the project
+func (man *ManifestInterpreter) readFunctionFile(path, function string) ([]byte, error) {
+	if !filepath.IsAbs(path) {
+		workDir, err := man.GetWorkDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(workDir, path)
+	}
+	content, err := man.FileReader(path)
+	if err != nil {
+		logger.Debug("Error while reading the file of an edge function", zap.String("path", path), zap.Error(err))
+		return nil, fmt.Errorf(msg.ErrorReadFunctionFile.Error(), path, function, err)
+	}
+	return content, nil
+}
+
+// readArgs reads the JSON arguments of a function or instance. Without a file the arguments are empty.
+func (man *ManifestInterpreter) readArgs(path, function string) (map[string]interface{}, error) {
+	args := make(map[string]interface{})
+	if path == "" {
+		return args, nil
+	}
+	content, err := man.readFunctionFile(path, function)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &args); err != nil {
+		logger.Debug("Error while unmarshalling the arguments of an edge function", zap.Error(err))
+		return nil, fmt.Errorf(msg.ErrorParseFunctionArgs.Error(), path)
+	}
+	return args, nil
+}
+
+// functionInstance returns the instance a run_function behavior points to. Its target names an instance of the
+// functions section, and rules without one run the instance of the project's function. Manifests without a functions
+// section always run the project's function, whatever the target.
+func functionInstance(target string, conf *contracts.AzionApplicationOptions) (string, error) {
+	if target == "" || len(conf.FunctionInstances) == 0 {
+		return strconv.FormatInt(conf.Function.InstanceID, 10), nil
+	}
+	for _, instance := range conf.FunctionInstances {
+		if instance.Name == target {
+			return strconv.FormatInt(instance.Id, 10), nil
+		}
+	}
+	logger.Debug("Edge Function instance not found", zap.Any("Target", target))
+	return "", fmt.Errorf(msg.ErrorInstanceNotFound.Error(), target)
+}
@@ -0,0 +1,181 @@
+package manifest
+
+import (
+	"github.com/aziontech/azion-cli/pkg/contracts"
+)
+
+// protection holds which resources have lifecycle.prevent_destroy set, by resource and name
+type protection map[string]map[string]bool
+
+// newProtection reads the protection recorded in azion.json, so a resource stays protected after it is removed from
+// the manifest. The manifest wins for the resources it still declares, which is how the protection is lifted.
+func newProtection(conf *contracts.AzionApplicationOptions, manifest *contracts.Manifest) protection {
+	p := protection{
+		ResourceOrigin:      make(map[string]bool),
+		ResourceCache:       make(map[string]bool),
+		ResourceRule:        make(map[string]bool),
+		ResourceDeviceGroup: make(map[string]bool),
+		// instances are protected along with their function
+		ResourceFunction:         make(map[string]bool),
+		ResourceFunctionInstance: make(map[string]bool),
+		ResourceDomain:           make(map[string]bool),
+		ResourceVariable:         make(map[string]bool),
+	}
+
+	for _, origin := range conf.Origin {
+		p[ResourceOrigin][origin.Name] = origin.PreventDestroy
+	}
+	for _, cache := range conf.CacheSettings {
+		p[ResourceCache][cache.Name] = cache.PreventDestroy
+	}
+	for _, rule := range conf.RulesEngine.Rules {
+		p[ResourceRule][rule.Name] = rule.PreventDestroy
+	}
+	for _, group := range conf.DeviceGroups {
+		p[ResourceDeviceGroup][group.Name] = group.PreventDestroy
+	}
+	for _, function := range conf.Functions {
+		p[ResourceFunction][function.Name] = function.PreventDestroy
+	}
+	for _, instance := range conf.FunctionInstances {
+		p[ResourceFunctionInstance][instance.Name] = instance.PreventDestroy
+	}
+	for _, domain := range conf.Domains {
+		p[ResourceDomain][domain.Name] = domain.PreventDestroy
+	}
+	for _, variable := range conf.Variables {
+		p[ResourceVariable][variable.Key] = variable.PreventDestroy
+	}
+
+	for _, origin := range manifest.Origins {
+		p[ResourceOrigin][origin.Name] = preventDestroy(origin.Lifecycle)
+	}
+	for _, cache := range manifest.CacheSettings {
+		if cache.Name != nil {
+			p[ResourceCache][*cache.Name] = preventDestroy(cache.Lifecycle)
+		}
+	}
+	for _, rule := range manifest.Rules {
+		p[ResourceRule][rule.Name] = preventDestroy(rule.Lifecycle)
+	}
+	for _, group := range manifest.DeviceGroups {
+		p[ResourceDeviceGroup][group.Name] = preventDestroy(group.Lifecycle)
+	}
+	for _, function := range manifest.Functions {
+		p[ResourceFunction][function.Name] = preventDestroy(function.Lifecycle)
+		for _, instance := range instancesOf(function) {
+			p[ResourceFunctionInstance][instance.Name] = preventDestroy(function.Lifecycle)
+		}
+	}
+	for _, domain := range manifest.Domains {
+		p[ResourceDomain][domain.Name] = preventDestroy(domain.Lifecycle)
+	}
+	for _, variable := range manifest.Variables {
+		p[ResourceVariable][variable.Key] = preventDestroy(variable.Lifecycle)
+	}
+
+	return p
+}
+
+func (p protection) protected(resource, name string) bool {
+	return p[resource][name]
+}
+
+func preventDestroy(lifecycle *contracts.Lifecycle) bool {
+	return lifecycle != nil && lifecycle.PreventDestroy
+}
+
+// retain keeps tracking in azion.json a resource deploy did not delete, so the next deploy can still update or
+// delete it
+func retain(conf *contracts.AzionApplicationOptions, resource contracts.ResourcePlan, protected bool) {
+	switch resource.Resource {
+	case ResourceRule:
+		tracked := ruleByID(resource.Id)
+		for _, rule := range conf.RulesEngine.Rules {
+			if rule.Name == resource.Name && rule.Phase == tracked.Phase {
+				return
+			}
+		}
+		conf.RulesEngine.Rules = append(conf.RulesEngine.Rules, contracts.AzionJsonDataRules{
+			Id:             tracked.Id,
+			Name:           resource.Name,
+			Phase:          tracked.Phase,
+			PreventDestroy: protected,
+		})
+	case ResourceOrigin:
+		for _, origin := range conf.Origin {
+			if origin.Name == resource.Name {
+				return
+			}
+		}
+		conf.Origin = append(conf.Origin, contracts.AzionJsonDataOrigin{
+			OriginId:       OriginIds[resource.Name],
+			OriginKey:      OriginKeys[resource.Name],
+			Name:           resource.Name,
+			PreventDestroy: protected,
+		})
+	case ResourceCache:
+		for _, cache := range conf.CacheSettings {
+			if cache.Name == resource.Name {
+				return
+			}
+		}
+		conf.CacheSettings = append(conf.CacheSettings, contracts.AzionJsonDataCacheSettings{
+			Id:             CacheIds[resource.Name],
+			Name:           resource.Name,
+			PreventDestroy: protected,
+		})
+	case ResourceDeviceGroup:
+		conf.DeviceGroups = append(conf.DeviceGroups, contracts.AzionJsonDataDeviceGroup{
+			Id:             DeviceGroupIds[resource.Name],
+			Name:           resource.Name,
+			PreventDestroy: protected,
+		})
+	case ResourceFunction:
+		conf.Functions = append(conf.Functions, contracts.AzionJsonDataManifestFunction{
+			Id:             FunctionIds[resource.Name],
+			Name:           resource.Name,
+			PreventDestroy: protected,
+		})
+	case ResourceFunctionInstance:
+		conf.FunctionInstances = append(conf.FunctionInstances, contracts.AzionJsonDataFunctionInstance{
+			Id:             FunctionInstanceIds[resource.Name],
+			Name:           resource.Name,
+			PreventDestroy: protected,
+		})
+	case ResourceDomain:
+		conf.Domains = append(conf.Domains, contracts.AzionJsonDataManifestDomain{
+			Id:             DomainIds[resource.Name],
+			Name:           resource.Name,
+			PreventDestroy: protected,
+		})
+	case ResourceVariable:
+		conf.Variables = append(conf.Variables, contracts.AzionJsonDataVariable{
+			Uuid:           VariableIds[resource.Name],
+			Key:            resource.Name,
+			PreventDestroy: protected,
+		})
+	}
+}
+
+// forget stops tracking a deleted resource the manifest still declares without any rule referencing it
+func forget(conf *contracts.AzionApplicationOptions, resource contracts.ResourcePlan) {
+	switch resource.Resource {
+	case ResourceOrigin:
+		origins := []contracts.AzionJsonDataOrigin{}
+		for _, origin := range conf.Origin {
+			if origin.Name != resource.Name {
+				origins = append(origins, origin)
+			}
+		}
+		conf.Origin = origins
+	case ResourceCache:
+		caches := []contracts.AzionJsonDataCacheSettings{}
+		for _, cache := range conf.CacheSettings {
+			if cache.Name != resource.Name {
+				caches = append(caches, cache)
+			}
+		}
+		conf.CacheSettings = caches
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	msgcache "github.com/aziontech/azion-cli/messages/cache_setting"
@@ -12,29 +14,86 @@ import (
 	msg "github.com/aziontech/azion-cli/messages/manifest"
 	msgorigin "github.com/aziontech/azion-cli/messages/origin"
 	apiCache "github.com/aziontech/azion-cli/pkg/api/cache_setting"
+	apiDomain "github.com/aziontech/azion-cli/pkg/api/domain"
 	apiEdgeApplications "github.com/aziontech/azion-cli/pkg/api/edge_applications"
+	apiEdgeFunction "github.com/aziontech/azion-cli/pkg/api/edge_function"
 	apiOrigin "github.com/aziontech/azion-cli/pkg/api/origin"
+	apiVariables "github.com/aziontech/azion-cli/pkg/api/variables"
 	"github.com/aziontech/azion-cli/pkg/cmdutil"
 	"github.com/aziontech/azion-cli/pkg/contracts"
 	"github.com/aziontech/azion-cli/pkg/logger"
 	"github.com/aziontech/azion-cli/utils"
+	sdk "github.com/aziontech/azionapi-go-sdk/edgeapplications"
 	thoth "github.com/aziontech/go-thoth"
 	"go.uber.org/zap"
 )
 
 var (
-	CacheIds         map[string]int64
-	CacheIdsBackup   map[string]int64
-	RuleIds          map[string]contracts.RuleIdsStruct
-	OriginKeys       map[string]string
-	OriginIds        map[string]int64
-	manifestFilePath = "/.edge/manifest.json"
+	CacheIds       map[string]int64
+	CacheIdsBackup map[string]int64
+	// RuleIds holds the rules by RuleKey, since rules of different phases may share a name
+	RuleIds        map[string]contracts.RuleIdsStruct
+	OriginKeys     map[string]string
+	OriginIds      map[string]int64
+	DeviceGroupIds map[string]int64
+	manifestDir    = ".edge"
 )
 
+var (
+	// FunctionIds and FunctionInstanceIds hold the functions of the manifest and their instances by name
+	FunctionIds         map[string]int64
+	FunctionInstanceIds map[string]int64
+	// DomainIds holds the domains of the manifest by name
+	DomainIds map[string]int64
+	// VariableIds holds the UUIDs of the variables of the manifest by key
+	VariableIds map[string]string
+)
+
+// RuleKey is the key of a rule in RuleIds. Rules tracked before their phase was recorded have an empty phase.
+func RuleKey(phase, name string) string {
+	return phase + "/" + name
+}
+
+// trackedRule finds a rule of the manifest in ids, or the rule of the same name whose phase wasn't recorded, and
+// returns the key it is tracked under
+func trackedRule(ids map[string]contracts.RuleIdsStruct, rule contracts.RuleEngine) (string, contracts.RuleIdsStruct) {
+	for _, key := range []string{RuleKey(rule.Phase, rule.Name), RuleKey("", rule.Name)} {
+		if r := ids[key]; r.Id > 0 {
+			return key, r
+		}
+	}
+	return "", contracts.RuleIdsStruct{}
+}
+
+// ruleByID finds a rule of RuleIds by the ID a plan gives it
+func ruleByID(id string) contracts.RuleIdsStruct {
+	for _, rule := range RuleIds {
+		if strconv.FormatInt(rule.Id, 10) == id {
+			return rule
+		}
+	}
+	return contracts.RuleIdsStruct{}
+}
+
 type ManifestInterpreter struct {
 	FileReader            func(path string) ([]byte, error)
 	GetWorkDir            func() (string, error)
 	WriteAzionJsonContent func(conf *contracts.AzionApplicationOptions, confPath string) error
+	// Created is told about every resource CreateResources creates, before it is written to azion.json
+	Created func(entry contracts.JournalEntry) error
+	// Changed is told about every resource CreateResources creates, updates or deletes
+	Changed func(change contracts.ResourcePlan)
+	// OverridesPath is a partial manifest whose entries replace the ones with the same name, used by environments
+	OverridesPath string
+	// Prune deletes the resources no longer declared in the manifest. When false they are kept and stay tracked
+	Prune bool
+	// ConfirmDelete decides whether the listed resources are deleted. Without it they are kept. PlanResources
+	// calls it for each deletion, so there it must tell what the deploy would decide without asking
+	ConfirmDelete func(deletions []contracts.ResourcePlan) bool
+	// EnvPath is the .env file the values of the variables are read from, after the environment of the process
+	EnvPath string
+	// LookupEnv reads the environment of the process. Without it os.LookupEnv is used
+	LookupEnv func(key string) (string, bool)
 }
 
 func NewManifestInterpreter() *ManifestInterpreter {
@@ -42,6 +101,8 @@ func NewManifestInterpreter() *ManifestInterpreter {
 		FileReader:            os.ReadFile,
 		GetWorkDir:            utils.GetWorkingDir,
 		WriteAzionJsonContent: utils.WriteAzionJsonContent,
+		Prune:                 true,
+		EnvPath:               filepath.Join(manifestDir, ".env"),
 	}
 }
 
@@ -51,13 +112,31 @@ func (man *ManifestInterpreter) ManifestPath() (string, error) {
 		return "", err
 	}
 
-	return utils.Concat(pathWorkingDir, manifestFilePath), nil
+	return FindManifest(filepath.Join(pathWorkingDir, manifestDir)), nil
 }
 
 func (man *ManifestInterpreter) ReadManifest(
 	path string, f *cmdutil.Factory, msgs *[]string) (*contracts.Manifest, error) {
 	logger.FInfoFlags(f.IOStreams.Out, msg.ReadingManifest, f.Format, f.Out)
 	*msgs = append(*msgs, msg.ReadingManifest)
+
+	manifest, err := man.LoadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if man.OverridesPath != "" {
+		err = man.applyOverrides(manifest, f, msgs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return manifest, nil
+}
+
+// LoadManifest reads the manifest in path, in any of its formats, without the overrides of the environment
+func (man *ManifestInterpreter) LoadManifest(path string) (*contracts.Manifest, error) {
 	manifest := &contracts.Manifest{}
 
 	byteManifest, err := man.FileReader(path)
@@ -65,6 +144,11 @@ func (man *ManifestInterpreter) ReadManifest(
 		return nil, err
 	}
 
+	byteManifest, err = man.decodeManifest(path, byteManifest)
+	if err != nil {
+		return nil, err
+	}
+
 	err = json.Unmarshal(byteManifest, &manifest)
 	if err != nil {
 		return nil, err
@@ -92,14 +176,23 @@ func (man *ManifestInterpreter) CreateResources(
 	RuleIds = make(map[string]contracts.RuleIdsStruct)
 	OriginKeys = make(map[string]string)
 	OriginIds = make(map[string]int64)
+	DeviceGroupIds = make(map[string]int64)
+	FunctionIds = make(map[string]int64)
+	FunctionInstanceIds = make(map[string]int64)
+	DomainIds = make(map[string]int64)
+	VariableIds = make(map[string]string)
+
+	// read before the tracked resources below are replaced by the ones of the manifest
+	protected := newProtection(conf, manifest)
 
 	for _, cacheConf := range conf.CacheSettings {
 		CacheIds[cacheConf.Name] = cacheConf.Id
 	}
 
 	for _, ruleConf := range conf.RulesEngine.Rules {
-		RuleIds[ruleConf.Name] = contracts.RuleIdsStruct{
+		RuleIds[RuleKey(ruleConf.Phase, ruleConf.Name)] = contracts.RuleIdsStruct{
 			Id:    ruleConf.Id,
+			Name:  ruleConf.Name,
 			Phase: ruleConf.Phase,
 		}
 	}
@@ -109,6 +202,97 @@ func (man *ManifestInterpreter) CreateResources(
 		OriginIds[originConf.Name] = originConf.OriginId
 	}
 
+	for _, groupConf := range conf.DeviceGroups {
+		DeviceGroupIds[groupConf.Name] = groupConf.Id
+	}
+
+	for _, functionConf := range conf.Functions {
+		FunctionIds[functionConf.Name] = functionConf.Id
+	}
+
+	for _, instanceConf := range conf.FunctionInstances {
+		FunctionInstanceIds[instanceConf.Name] = instanceConf.Id
+	}
+
+	for _, domainConf := range conf.Domains {
+		DomainIds[domainConf.Name] = domainConf.Id
+	}
+
+	for _, variableConf := range conf.Variables {
+		VariableIds[variableConf.Key] = variableConf.Uuid
+	}
+
+	// variables go first, since they belong to the account and nothing of the edge application references them
+	err := man.doVariables(ctx, f, conf, manifest, msgs)
+	if err != nil {
+		return err
+	}
+
+	// device groups go first, since cache settings and rules reference them
+	groupConf := []contracts.AzionJsonDataDeviceGroup{}
+	for _, group := range manifest.DeviceGroups {
+		if id := DeviceGroupIds[group.Name]; id > 0 {
+			requestUpdate := sdk.PatchDeviceGroupsRequest{}
+			requestUpdate.SetName(group.Name)
+			requestUpdate.SetUserAgent(group.UserAgent)
+			updated, err := client.UpdateDeviceGroup(ctx, requestUpdate, conf.Application.ID, id)
+			if err != nil {
+				return fmt.Errorf("%w: %s", msg.ErrorUpdateDeviceGroup, err.Error())
+			}
+			newGroup := contracts.AzionJsonDataDeviceGroup{
+				Id:             updated.GetId(),
+				Name:           updated.GetName(),
+				PreventDestroy: preventDestroy(group.Lifecycle),
+			}
+			groupConf = append(groupConf, newGroup)
+			man.changed(ResourceDeviceGroup, newGroup.Name, strconv.FormatInt(newGroup.Id, 10), PlanUpdate)
+			msgf := fmt.Sprintf(msg.ManifestUpdateDeviceGroup, group.Name, newGroup.Id)
+			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+			*msgs = append(*msgs, msgf)
+		} else {
+			requestCreate := &apiEdgeApplications.CreateDeviceGroupsRequest{}
+			requestCreate.SetName(group.Name)
+			requestCreate.SetUserAgent(group.UserAgent)
+			created, err := client.CreateDeviceGroups(ctx, requestCreate, conf.Application.ID)
+			if err != nil {
+				return fmt.Errorf("%w: %s", msg.ErrorCreateDeviceGroup, err.Error())
+			}
+			newGroup := contracts.AzionJsonDataDeviceGroup{
+				Id:             created.GetId(),
+				Name:           created.GetName(),
+				PreventDestroy: preventDestroy(group.Lifecycle),
+			}
+			groupConf = append(groupConf, newGroup)
+			DeviceGroupIds[newGroup.Name] = newGroup.Id
+			if err := man.created(contracts.JournalEntry{
+				Resource:      ResourceDeviceGroup,
+				ID:            newGroup.Id,
+				Name:          newGroup.Name,
+				ApplicationID: conf.Application.ID,
+			}); err != nil {
+				return err
+			}
+			man.changed(ResourceDeviceGroup, newGroup.Name, strconv.FormatInt(newGroup.Id, 10), PlanCreate)
+			msgf := fmt.Sprintf(msg.ManifestCreateDeviceGroup, group.Name, newGroup.Id)
+			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+			*msgs = append(*msgs, msgf)
+		}
+	}
+
+	conf.DeviceGroups = groupConf
+
+	// functions go before the rules running them
+	err = man.doFunctions(ctx, f, conf, manifest, msgs)
+	if err != nil {
+		return err
+	}
+
+	err = man.WriteAzionJsonContent(conf, projectConf)
+	if err != nil {
+		logger.Debug("Error while writing azion.json file", zap.Error(err))
+		return err
+	}
+
 	originConf := []contracts.AzionJsonDataOrigin{}
 	for _, origin := range manifest.Origins {
 		if id := OriginIds[origin.Name]; id > 0 {
@@ -124,11 +308,13 @@ func (man *ManifestInterpreter) CreateResources(
 			}
 
 			newEntry := contracts.AzionJsonDataOrigin{
-				OriginId:  updated.GetOriginId(),
-				OriginKey: updated.GetOriginKey(),
-				Name:      updated.GetName(),
+				OriginId:       updated.GetOriginId(),
+				OriginKey:      updated.GetOriginKey(),
+				Name:           updated.GetName(),
+				PreventDestroy: preventDestroy(origin.Lifecycle),
 			}
 			originConf = append(originConf, newEntry)
+			man.changed(ResourceOrigin, newEntry.Name, updated.GetOriginKey(), PlanUpdate)
 
 			msgf := fmt.Sprintf(msg.ManifestUpdateOrigin, origin.Name, updated.GetOriginKey())
 			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
@@ -145,14 +331,25 @@ func (man *ManifestInterpreter) CreateResources(
 				return fmt.Errorf("%w: %s", msg.ErrorCreateOrigin, err.Error())
 			}
 			newOrigin := contracts.AzionJsonDataOrigin{
-				OriginId:  created.GetOriginId(),
-				OriginKey: created.GetOriginKey(),
-				Name:      created.GetName(),
+				OriginId:       created.GetOriginId(),
+				OriginKey:      created.GetOriginKey(),
+				Name:           created.GetName(),
+				PreventDestroy: preventDestroy(origin.Lifecycle),
 			}
 
 			originConf = append(originConf, newOrigin)
 			OriginIds[created.GetName()] = created.GetOriginId()
 			OriginKeys[created.GetName()] = created.GetOriginKey()
+			if err := man.created(contracts.JournalEntry{
+				Resource:      ResourceOrigin,
+				ID:            created.GetOriginId(),
+				Key:           created.GetOriginKey(),
+				Name:          created.GetName(),
+				ApplicationID: conf.Application.ID,
+			}); err != nil {
+				return err
+			}
+			man.changed(ResourceOrigin, newOrigin.Name, newOrigin.OriginKey, PlanCreate)
 			msgf := fmt.Sprintf(msg.ManifestCreateOrigin, origin.Name, created.GetOriginId())
 			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
 			*msgs = append(*msgs, msgf)
@@ -160,7 +357,7 @@ func (man *ManifestInterpreter) CreateResources(
 	}
 
 	conf.Origin = originConf
-	err := man.WriteAzionJsonContent(conf, projectConf)
+	err = man.WriteAzionJsonContent(conf, projectConf)
 	if err != nil {
 		logger.Debug("Error while writing azion.json file", zap.Error(err))
 		return err
@@ -168,6 +365,9 @@ func (man *ManifestInterpreter) CreateResources(
 
 	cacheConf := []contracts.AzionJsonDataCacheSettings{}
 	for _, cache := range manifest.CacheSettings {
+		if err := resolveDeviceGroups(&cache); err != nil {
+			return err
+		}
 		if id := CacheIds[*cache.Name]; id > 0 {
 			requestUpdate := makeCacheRequestUpdate(cache)
 			if cache.Name != nil {
@@ -180,10 +380,12 @@ func (man *ManifestInterpreter) CreateResources(
 				return fmt.Errorf("%w: %s", msg.ErrorUpdateCache, err.Error())
 			}
 			newCache := contracts.AzionJsonDataCacheSettings{
-				Id:   updated.GetId(),
-				Name: updated.GetName(),
+				Id:             updated.GetId(),
+				Name:           updated.GetName(),
+				PreventDestroy: preventDestroy(cache.Lifecycle),
 			}
 			cacheConf = append(cacheConf, newCache)
+			man.changed(ResourceCache, newCache.Name, strconv.FormatInt(newCache.Id, 10), PlanUpdate)
 			msgf := fmt.Sprintf(msg.ManifestUpdateCache, *cache.Name, id)
 			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
 			*msgs = append(*msgs, msgf)
@@ -199,11 +401,21 @@ func (man *ManifestInterpreter) CreateResources(
 				return fmt.Errorf("%w: %s", msg.ErrorCreateCache, err.Error())
 			}
 			newCache := contracts.AzionJsonDataCacheSettings{
-				Id:   created.GetId(),
-				Name: created.GetName(),
+				Id:             created.GetId(),
+				Name:           created.GetName(),
+				PreventDestroy: preventDestroy(cache.Lifecycle),
 			}
 			cacheConf = append(cacheConf, newCache)
 			CacheIds[newCache.Name] = newCache.Id
+			if err := man.created(contracts.JournalEntry{
+				Resource:      ResourceCache,
+				ID:            newCache.Id,
+				Name:          newCache.Name,
+				ApplicationID: conf.Application.ID,
+			}); err != nil {
+				return err
+			}
+			man.changed(ResourceCache, newCache.Name, strconv.FormatInt(newCache.Id, 10), PlanCreate)
 			msgf := fmt.Sprintf(msg.ManifestCreateCache, *cache.Name, newCache.Id)
 			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
 			*msgs = append(*msgs, msgf)
@@ -224,7 +436,7 @@ func (man *ManifestInterpreter) CreateResources(
 
 	ruleConf := []contracts.AzionJsonDataRules{}
 	for _, rule := range manifest.Rules {
-		if r := RuleIds[rule.Name]; r.Id > 0 {
+		if key, r := trackedRule(RuleIds, rule); r.Id > 0 {
 			requestUpdate, err := makeRuleRequestUpdate(rule, conf)
 			if err != nil {
 				return err
@@ -232,22 +444,23 @@ func (man *ManifestInterpreter) CreateResources(
 			requestUpdate.Id = r.Id
 			requestUpdate.Phase = rule.Phase
 			requestUpdate.IsActive = &rule.IsActive
-			requestUpdate.Order = &rule.Order
 			requestUpdate.IdApplication = conf.Application.ID
 			updated, err := client.UpdateRulesEngine(ctx, requestUpdate)
 			if err != nil {
 				return fmt.Errorf("%w: %s", msg.ErrorUpdateRule, err.Error())
 			}
 			newRule := contracts.AzionJsonDataRules{
-				Id:    updated.GetId(),
-				Name:  updated.GetName(),
-				Phase: updated.GetPhase(),
+				Id:             updated.GetId(),
+				Name:           updated.GetName(),
+				Phase:          updated.GetPhase(),
+				PreventDestroy: preventDestroy(rule.Lifecycle),
 			}
+			man.changed(ResourceRule, newRule.Name, strconv.FormatInt(newRule.Id, 10), PlanUpdate)
 			msgf := fmt.Sprintf(msg.ManifestUpdateRule, newRule.Name, newRule.Id)
 			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
 			*msgs = append(*msgs, msgf)
 			ruleConf = append(ruleConf, newRule)
-			delete(RuleIds, rule.Name)
+			delete(RuleIds, key)
 		} else {
 			requestCreate, err := makeRuleRequestCreate(rule, conf, client, ctx)
 			if err != nil {
@@ -259,17 +472,27 @@ func (man *ManifestInterpreter) CreateResources(
 				requestCreate.Name = conf.Name + thoth.GenerateName()
 			}
 			requestCreate.IsActive = &rule.IsActive
-			requestCreate.Order = &rule.Order
 			created, err := client.CreateRulesEngine(ctx, conf.Application.ID, rule.Phase, requestCreate)
 			if err != nil {
 				return fmt.Errorf("%w: %s", msg.ErrorCreateRule, err.Error())
 			}
 			newRule := contracts.AzionJsonDataRules{
-				Id:    created.GetId(),
-				Name:  created.GetName(),
-				Phase: created.GetPhase(),
+				Id:             created.GetId(),
+				Name:           created.GetName(),
+				Phase:          created.GetPhase(),
+				PreventDestroy: preventDestroy(rule.Lifecycle),
 			}
 			ruleConf = append(ruleConf, newRule)
+			if err := man.created(contracts.JournalEntry{
+				Resource:      ResourceRule,
+				ID:            newRule.Id,
+				Name:          newRule.Name,
+				ApplicationID: conf.Application.ID,
+				Phase:         rule.Phase,
+			}); err != nil {
+				return err
+			}
+			man.changed(ResourceRule, newRule.Name, strconv.FormatInt(newRule.Id, 10), PlanCreate)
 			msgf := fmt.Sprintf(msg.ManifestCreateRule, newRule.Name, newRule.Id)
 			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
 			*msgs = append(*msgs, msgf)
@@ -283,7 +506,24 @@ func (man *ManifestInterpreter) CreateResources(
 		return err
 	}
 
-	err = deleteResources(ctx, f, conf, msgs)
+	err = man.doDomains(ctx, f, conf, manifest, msgs)
+	if err != nil {
+		return err
+	}
+
+	err = man.WriteAzionJsonContent(conf, projectConf)
+	if err != nil {
+		logger.Debug("Error while writing azion.json file", zap.Error(err))
+		return err
+	}
+
+	err = man.deleteResources(ctx, f, conf, protected, projectConf, msgs)
+	if err != nil {
+		return err
+	}
+
+	// rules are put in order once the ones no longer declared are gone
+	err = man.orderRules(ctx, f, conf, manifest, msgs)
 	if err != nil {
 		return err
 	}
@@ -291,50 +531,213 @@ func (man *ManifestInterpreter) CreateResources(
 	return nil
 }
 
-// this is called to delete resources no longer present in manifest.json
-func deleteResources(ctx context.Context, f *cmdutil.Factory, conf *contracts.AzionApplicationOptions, msgs *[]string) error {
+func (man *ManifestInterpreter) created(entry contracts.JournalEntry) error {
+	if man.Created != nil {
+		return man.Created(entry)
+	}
+	return nil
+}
+
+func (man *ManifestInterpreter) changed(resource, name, id, action string) {
+	if man.Changed != nil {
+		man.Changed(contracts.ResourcePlan{Resource: resource, Name: name, Id: id, Action: action})
+	}
+}
+
+// deleteResources deletes the resources no longer present in manifest.json or referenced by any rule. Protected
+// resources are always kept, and the others only once ConfirmDelete agrees. Kept resources stay tracked in azion.json.
+func (man *ManifestInterpreter) deleteResources(
+	ctx context.Context,
+	f *cmdutil.Factory,
+	conf *contracts.AzionApplicationOptions,
+	protected protection,
+	projectConf string,
+	msgs *[]string) error {
 	client := apiEdgeApplications.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
 	clientCache := apiCache.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
 	clientOrigin := apiOrigin.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
-
-	for _, value := range RuleIds {
-		//since until [UXE-3599] was carried out we'd only cared about "request" phase, this check guarantees that if Phase is empty
-		// we are probably dealing with a rule engine from a previous version
-		phase := "request"
-		if value.Phase != "" {
-			phase = value.Phase
+	clientFunction := apiEdgeFunction.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+	clientDomain := apiDomain.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+	clientVariable := apiVariables.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+
+	// rules go first, since they may reference the resources deleted after them, and instances before their functions
+	pending := []contracts.ResourcePlan{}
+	for _, key := range sortedKeys(RuleIds) {
+		pending = append(pending, contracts.ResourcePlan{
+			Resource: ResourceRule, Name: RuleIds[key].Name, Id: strconv.FormatInt(RuleIds[key].Id, 10), Action: PlanDelete})
+	}
+	declaredInstances := make(map[string]bool)
+	for _, instance := range conf.FunctionInstances {
+		declaredInstances[instance.Name] = true
+	}
+	for _, name := range sortedKeys(FunctionInstanceIds) {
+		if declaredInstances[name] {
+			continue
 		}
-		err := client.DeleteRulesEngine(ctx, conf.Application.ID, phase, value.Id)
-		if err != nil {
-			return err
+		pending = append(pending, contracts.ResourcePlan{
+			Resource: ResourceFunctionInstance, Name: name, Id: strconv.FormatInt(FunctionInstanceIds[name], 10), Action: PlanDelete})
+	}
+	declaredFunctions := make(map[string]bool)
+	for _, function := range conf.Functions {
+		declaredFunctions[function.Name] = true
+	}
+	for _, name := range sortedKeys(FunctionIds) {
+		if declaredFunctions[name] {
+			continue
 		}
-		msgf := fmt.Sprintf(msgrule.DeleteOutputSuccess+"\n", value.Id)
-		logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
-		*msgs = append(*msgs, msgf)
+		pending = append(pending, contracts.ResourcePlan{
+			Resource: ResourceFunction, Name: name, Id: strconv.FormatInt(FunctionIds[name], 10), Action: PlanDelete})
+	}
+	for _, name := range sortedKeys(OriginKeys) {
+		if strings.Contains(name, "_single") {
+			continue
+		}
+		pending = append(pending, contracts.ResourcePlan{
+			Resource: ResourceOrigin, Name: name, Id: OriginKeys[name], Action: PlanDelete})
+	}
+	for _, name := range sortedKeys(CacheIds) {
+		pending = append(pending, contracts.ResourcePlan{
+			Resource: ResourceCache, Name: name, Id: strconv.FormatInt(CacheIds[name], 10), Action: PlanDelete})
+	}
+	// device groups are kept while the manifest declares them, even when nothing references them
+	declaredGroups := make(map[string]bool)
+	for _, group := range conf.DeviceGroups {
+		declaredGroups[group.Name] = true
+	}
+	for _, name := range sortedKeys(DeviceGroupIds) {
+		if declaredGroups[name] {
+			continue
+		}
+		pending = append(pending, contracts.ResourcePlan{
+			Resource: ResourceDeviceGroup, Name: name, Id: strconv.FormatInt(DeviceGroupIds[name], 10), Action: PlanDelete})
+	}
+	declaredDomains := make(map[string]bool)
+	for _, domain := range conf.Domains {
+		declaredDomains[domain.Name] = true
+	}
+	for _, name := range sortedKeys(DomainIds) {
+		if declaredDomains[name] {
+			continue
+		}
+		pending = append(pending, contracts.ResourcePlan{
+			Resource: ResourceDomain, Name: name, Id: strconv.FormatInt(DomainIds[name], 10), Action: PlanDelete})
+	}
+	declaredVariables := make(map[string]bool)
+	for _, variable := range conf.Variables {
+		declaredVariables[variable.Key] = true
+	}
+	for _, key := range sortedKeys(VariableIds) {
+		if declaredVariables[key] {
+			continue
+		}
+		pending = append(pending, contracts.ResourcePlan{
+			Resource: ResourceVariable, Name: key, Id: VariableIds[key], Action: PlanDelete})
 	}
 
-	for i, value := range OriginKeys {
-		if strings.Contains(i, "_single") {
+	deletions := []contracts.ResourcePlan{}
+	kept := []contracts.ResourcePlan{}
+	for _, resource := range pending {
+		if protected.protected(resource.Resource, resource.Name) {
+			resource.Details = msg.PlanPreventDestroy
+			kept = append(kept, resource)
 			continue
 		}
-		err := clientOrigin.DeleteOrigins(ctx, conf.Application.ID, value)
+		deletions = append(deletions, resource)
+	}
+
+	if len(deletions) > 0 {
+		details := ""
+		if !man.Prune {
+			details = msg.PlanPruneDisabled
+		} else if man.ConfirmDelete == nil || !man.ConfirmDelete(deletions) {
+			details = msg.PlanNotConfirmed
+		}
+		if details != "" {
+			for _, resource := range deletions {
+				resource.Details = details
+				kept = append(kept, resource)
+			}
+			deletions = nil
+		}
+	}
+
+	// only the resources that are actually deleted are listed, the kept ones are reported below
+	if len(deletions) > 0 {
+		logger.FInfoFlags(f.IOStreams.Out, msg.DeletingResources, f.Format, f.Out)
+		*msgs = append(*msgs, msg.DeletingResources)
+		for _, resource := range deletions {
+			msgf := fmt.Sprintf(msg.DeletingResource, resource.Resource, resource.Name, resource.Id)
+			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+			*msgs = append(*msgs, msgf)
+		}
+	}
+
+	for _, resource := range deletions {
+		var err error
+		var msgf string
+		switch resource.Resource {
+		case ResourceRule:
+			// rules tracked by previous versions have no phase recorded, so it is looked up
+			rule := ruleByID(resource.Id)
+			phase := rule.Phase
+			if phase == "" {
+				phase, err = findRulePhase(ctx, client, conf.Application.ID, rule.Id)
+				if err != nil {
+					return err
+				}
+			}
+			err = client.DeleteRulesEngine(ctx, conf.Application.ID, phase, rule.Id)
+			msgf = fmt.Sprintf(msgrule.DeleteOutputSuccess+"\n", rule.Id)
+		case ResourceOrigin:
+			err = clientOrigin.DeleteOrigins(ctx, conf.Application.ID, OriginKeys[resource.Name])
+			msgf = fmt.Sprintf(msgorigin.DeleteOutputSuccess+"\n", OriginKeys[resource.Name])
+		case ResourceCache:
+			err = clientCache.Delete(ctx, conf.Application.ID, CacheIds[resource.Name])
+			msgf = fmt.Sprintf(msgcache.DeleteOutputSuccess+"\n", CacheIds[resource.Name])
+		case ResourceDeviceGroup:
+			err = client.DeleteDeviceGroup(ctx, conf.Application.ID, DeviceGroupIds[resource.Name])
+			msgf = fmt.Sprintf(msg.ManifestDeleteDeviceGroup, resource.Name, DeviceGroupIds[resource.Name])
+		case ResourceFunctionInstance:
+			err = client.DeleteFunctionInstance(ctx, strconv.FormatInt(conf.Application.ID, 10), resource.Id)
+			msgf = fmt.Sprintf(msg.ManifestDeleteFunctionInstance, resource.Name, FunctionInstanceIds[resource.Name])
+		case ResourceFunction:
+			err = clientFunction.Delete(ctx, FunctionIds[resource.Name])
+			msgf = fmt.Sprintf(msg.ManifestDeleteFunction, resource.Name, FunctionIds[resource.Name])
+		case ResourceDomain:
+			err = clientDomain.Delete(ctx, DomainIds[resource.Name])
+			msgf = fmt.Sprintf(msg.ManifestDeleteDomain, resource.Name, DomainIds[resource.Name])
+		case ResourceVariable:
+			err = clientVariable.Delete(ctx, VariableIds[resource.Name])
+			msgf = fmt.Sprintf(msg.ManifestDeleteVariable, resource.Name, VariableIds[resource.Name])
+		}
 		if err != nil {
 			return err
 		}
-		msgf := fmt.Sprintf(msgorigin.DeleteOutputSuccess+"\n", value)
+		forget(conf, resource)
+		man.changed(resource.Resource, resource.Name, resource.Id, PlanDelete)
 		logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
 		*msgs = append(*msgs, msgf)
 	}
 
-	for _, value := range CacheIds {
-		err := clientCache.Delete(ctx, conf.Application.ID, value)
-		if err != nil {
-			return err
+	for _, resource := range kept {
+		retain(conf, resource, protected.protected(resource.Resource, resource.Name))
+		resource.Action = PlanKeep
+		if man.Changed != nil {
+			man.Changed(resource)
 		}
-		msgf := fmt.Sprintf(msgcache.DeleteOutputSuccess+"\n", value)
+		msgf := fmt.Sprintf(msg.ManifestKeptResource, resource.Resource, resource.Name, resource.Id, resource.Details)
 		logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
 		*msgs = append(*msgs, msgf)
 	}
 
+	if len(deletions) == 0 && len(kept) == 0 {
+		return nil
+	}
+	err := man.WriteAzionJsonContent(conf, projectConf)
+	if err != nil {
+		logger.Debug("Error while writing azion.json file", zap.Error(err))
+		return err
+	}
+
 	return nil
 }
@@ -1,12 +1,18 @@
 package manifest
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	msg "github.com/aziontech/azion-cli/messages/manifest"
 	"github.com/aziontech/azion-cli/pkg/contracts"
 	"github.com/aziontech/azion-cli/pkg/httpmock"
 	"github.com/aziontech/azion-cli/pkg/logger"
 	"github.com/aziontech/azion-cli/pkg/testutils"
+	sdk "github.com/aziontech/azionapi-go-sdk/edgeapplications"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zapcore"
 )
@@ -31,6 +37,72 @@ func TestDeployCmd(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("read manifest with overrides", func(t *testing.T) {
+		f, _, _ := testutils.NewFactory(nil)
+
+		interpreter := NewManifestInterpreter()
+		interpreter.OverridesPath = "fixtures/overrides.json"
+
+		manifest, err := interpreter.ReadManifest("fixtures/manifest.json", f, &msgs)
+		require.NoError(t, err)
+		require.Len(t, manifest.CacheSettings, 1)
+		require.Equal(t, "honor", *manifest.CacheSettings[0].BrowserCacheSettings)
+		require.Nil(t, manifest.CacheSettings[0].BrowserCacheSettingsMaximumTtl)
+		require.Len(t, manifest.Rules, 1)
+		require.Len(t, manifest.Origins, 1)
+		require.Equal(t, "staging-api", manifest.Origins[0].Name)
+	})
+
+	t.Run("missing overrides are ignored", func(t *testing.T) {
+		f, _, _ := testutils.NewFactory(nil)
+
+		interpreter := NewManifestInterpreter()
+		interpreter.OverridesPath = "fixtures/does-not-exist.json"
+
+		manifest, err := interpreter.ReadManifest("fixtures/manifest.json", f, &msgs)
+		require.NoError(t, err)
+		require.Equal(t, "override", *manifest.CacheSettings[0].BrowserCacheSettings)
+	})
+
+	t.Run("overrides every section and matches rules by phase", func(t *testing.T) {
+		f, _, _ := testutils.NewFactory(nil)
+
+		files := map[string]string{
+			"manifest.json": `{"rules": [
+					{"name": "cache", "phase": "request", "criteria": [], "behaviors": []},
+					{"name": "cache", "phase": "response", "criteria": [], "behaviors": []}],
+				"device_groups": [{"name": "mobile", "user_agent": "Mobile"}],
+				"functions": [{"name": "auth", "path": "auth.js"}],
+				"domains": [{"name": "__DEFAULT__"}],
+				"variables": [{"key": "API_URL"}]}`,
+			"overrides.json": `{"rules": [{"name": "cache", "phase": "response", "description": "staging", "criteria": [], "behaviors": []}],
+				"device_groups": [{"name": "mobile", "user_agent": "Staging"}],
+				"functions": [{"name": "auth", "path": "auth.staging.js"}],
+				"domains": [{"name": "__DEFAULT__", "cnames": ["staging.example.com"]}],
+				"variables": [{"key": "API_URL", "secret": true}, {"key": "DEBUG"}]}`,
+		}
+		interpreter := NewManifestInterpreter()
+		interpreter.EnvPath = ""
+		interpreter.OverridesPath = "overrides.json"
+		interpreter.FileReader = func(path string) ([]byte, error) {
+			if content, ok := files[path]; ok {
+				return []byte(content), nil
+			}
+			return nil, os.ErrNotExist
+		}
+
+		manifest, err := interpreter.ReadManifest("manifest.json", f, &msgs)
+		require.NoError(t, err)
+		require.Len(t, manifest.Rules, 2)
+		require.Nil(t, manifest.Rules[0].Description)
+		require.Equal(t, "staging", *manifest.Rules[1].Description)
+		require.Equal(t, "Staging", manifest.DeviceGroups[0].UserAgent)
+		require.Equal(t, "auth.staging.js", manifest.Functions[0].Path)
+		require.Equal(t, []string{"staging.example.com"}, manifest.Domains[0].Cnames)
+		require.Len(t, manifest.Variables, 2)
+		require.True(t, manifest.Variables[0].Secret)
+	})
+
 	t.Run("create resources", func(t *testing.T) {
 		mock := &httpmock.Registry{}
 		options := &contracts.AzionApplicationOptions{
@@ -50,6 +122,11 @@ func TestDeployCmd(t *testing.T) {
 			httpmock.JSONFromFile("./fixtures/rulessuccess.json"),
 		)
 
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+
 		f, _, _ := testutils.NewFactory(mock)
 
 		interpreter := NewManifestInterpreter()
@@ -65,4 +142,740 @@ func TestDeployCmd(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("create resources keeps the deletions not confirmed", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+		options := &contracts.AzionApplicationOptions{
+			Name: "NotAVeryGoodName",
+			Application: contracts.AzionJsonDataApplication{
+				ID: 1673635841,
+			},
+			Origin: []contracts.AzionJsonDataOrigin{
+				{OriginId: 91799, OriginKey: "e4f0761b", Name: "Create Origin"},
+				{OriginId: 91800, OriginKey: "a1b2c3d4", Name: "Production Origin", PreventDestroy: true},
+			},
+			RulesEngine: contracts.AzionJsonDataRulesEngine{
+				Rules: []contracts.AzionJsonDataRules{
+					{Id: 173617, Name: "old rule", Phase: "response"},
+				},
+			},
+		}
+
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/cache_settings"),
+			httpmock.JSONFromFile("./fixtures/cachesuccess.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rulessuccess.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+
+		f, stdout, _ := testutils.NewFactory(mock)
+
+		interpreter := NewManifestInterpreter()
+		interpreter.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+			return nil
+		}
+		var asked []contracts.ResourcePlan
+		interpreter.ConfirmDelete = func(deletions []contracts.ResourcePlan) bool {
+			asked = deletions
+			return false
+		}
+
+		manifest, err := interpreter.ReadManifest("fixtures/manifest.json", f, &msgs)
+		require.NoError(t, err)
+		err = interpreter.CreateResources(options, manifest, f, "azion", &msgs)
+		require.NoError(t, err)
+
+		require.Equal(t, []contracts.ResourcePlan{
+			{Resource: ResourceRule, Name: "old rule", Id: "173617", Action: PlanDelete},
+			{Resource: ResourceOrigin, Name: "Create Origin", Id: "e4f0761b", Action: PlanDelete},
+		}, asked)
+		// only what is actually deleted is listed
+		require.NotContains(t, stdout.String(), msg.DeletingResources)
+		require.Contains(t, stdout.String(), fmt.Sprintf(msg.ManifestKeptResource, ResourceRule, "old rule", "173617", msg.PlanNotConfirmed))
+		require.Len(t, options.RulesEngine.Rules, 2)
+		require.Equal(t, contracts.AzionJsonDataRules{Id: 173617, Name: "old rule", Phase: "response"}, options.RulesEngine.Rules[1])
+		require.ElementsMatch(t, []contracts.AzionJsonDataOrigin{
+			{OriginId: 91799, OriginKey: "e4f0761b", Name: "Create Origin"},
+			{OriginId: 91800, OriginKey: "a1b2c3d4", Name: "Production Origin", PreventDestroy: true},
+		}, options.Origin)
+		mock.Verify(t)
+	})
+
+	t.Run("create resources deletes the confirmed resources only", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+		options := &contracts.AzionApplicationOptions{
+			Name: "NotAVeryGoodName",
+			Application: contracts.AzionJsonDataApplication{
+				ID: 1673635841,
+			},
+			Origin: []contracts.AzionJsonDataOrigin{
+				{OriginId: 91799, OriginKey: "e4f0761b", Name: "Create Origin"},
+				{OriginId: 91800, OriginKey: "a1b2c3d4", Name: "Production Origin", PreventDestroy: true},
+			},
+		}
+
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/cache_settings"),
+			httpmock.JSONFromFile("./fixtures/cachesuccess.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rulessuccess.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("DELETE", "edge_applications/1673635841/origins/e4f0761b"),
+			httpmock.StatusStringResponse(204, ""),
+		)
+
+		f, _, _ := testutils.NewFactory(mock)
+
+		interpreter := NewManifestInterpreter()
+		interpreter.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+			return nil
+		}
+		interpreter.ConfirmDelete = func(deletions []contracts.ResourcePlan) bool {
+			return true
+		}
+
+		manifest, err := interpreter.ReadManifest("fixtures/manifest.json", f, &msgs)
+		require.NoError(t, err)
+		err = interpreter.CreateResources(options, manifest, f, "azion", &msgs)
+		require.NoError(t, err)
+
+		require.Equal(t, []contracts.AzionJsonDataOrigin{
+			{OriginId: 91800, OriginKey: "a1b2c3d4", Name: "Production Origin", PreventDestroy: true},
+		}, options.Origin)
+		mock.Verify(t)
+	})
+
+	t.Run("create resources with device groups", func(t *testing.T) {
+		jsonPayload := func(body string, cb func(payload map[string]interface{})) httpmock.Responder {
+			return httpmock.WithHeader(httpmock.RESTPayload(201, body, cb), "Content-Type", "application/json")
+		}
+		mock := &httpmock.Registry{}
+		options := &contracts.AzionApplicationOptions{
+			Name: "NotAVeryGoodName",
+			Application: contracts.AzionJsonDataApplication{
+				ID: 1673635841,
+			},
+			DeviceGroups: []contracts.AzionJsonDataDeviceGroup{
+				{Id: 30, Name: "tablet"},
+			},
+		}
+
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/device_groups"),
+			jsonPayload(`{"results": {"id": 12, "name": "mobile", "user_agent": "Mobile|Android"}, "schema_version": 3}`,
+				func(payload map[string]interface{}) {
+					require.Equal(t, "mobile", payload["name"])
+					require.Equal(t, "Mobile|Android", payload["user_agent"])
+				}),
+		)
+
+		cacheSuccess, err := os.ReadFile("./fixtures/cachesuccess.json")
+		require.NoError(t, err)
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/cache_settings"),
+			jsonPayload(string(cacheSuccess), func(payload map[string]interface{}) {
+				require.Equal(t, []interface{}{float64(12)}, payload["device_group"])
+			}),
+		)
+
+		rulesSuccess, err := os.ReadFile("./fixtures/rulessuccess.json")
+		require.NoError(t, err)
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/rules_engine/request/rules"),
+			jsonPayload(string(rulesSuccess), func(payload map[string]interface{}) {
+				criteria := payload["criteria"].([]interface{})[0].([]interface{})
+				require.Equal(t, "12", criteria[1].(map[string]interface{})["input_value"])
+			}),
+		)
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("DELETE", "edge_applications/1673635841/device_groups/30"),
+			httpmock.StatusStringResponse(204, ""),
+		)
+
+		f, _, _ := testutils.NewFactory(mock)
+
+		interpreter := NewManifestInterpreter()
+		interpreter.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+			return nil
+		}
+		interpreter.ConfirmDelete = func(deletions []contracts.ResourcePlan) bool {
+			return true
+		}
+
+		manifest, err := interpreter.ReadManifest("fixtures/manifest.json", f, &msgs)
+		require.NoError(t, err)
+		manifest.DeviceGroups = []contracts.DeviceGroup{{Name: "mobile", UserAgent: "Mobile|Android"}}
+		manifest.CacheSettings[0].DeviceGroups = []string{"mobile"}
+		device := "mobile"
+		manifest.Rules[0].Criteria[0] = append(manifest.Rules[0].Criteria[0], sdk.RulesEngineCriteria{
+			Variable:    "${device_group}",
+			Operator:    "is_equal",
+			Conditional: "and",
+			InputValue:  &device,
+		})
+
+		err = interpreter.CreateResources(options, manifest, f, "azion", &msgs)
+		require.NoError(t, err)
+		require.Equal(t, []contracts.AzionJsonDataDeviceGroup{{Id: 12, Name: "mobile"}}, options.DeviceGroups)
+		mock.Verify(t)
+	})
+
+	t.Run("create resources with an unknown device group", func(t *testing.T) {
+		f, _, _ := testutils.NewFactory(&httpmock.Registry{})
+		options := &contracts.AzionApplicationOptions{Name: "NotAVeryGoodName"}
+
+		interpreter := NewManifestInterpreter()
+		interpreter.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+			return nil
+		}
+
+		manifest, err := interpreter.ReadManifest("fixtures/manifest.json", f, &msgs)
+		require.NoError(t, err)
+		manifest.CacheSettings[0].DeviceGroups = []string{"mobile"}
+
+		err = interpreter.CreateResources(options, manifest, f, "azion", &msgs)
+		require.ErrorContains(t, err, "Could not find the device group mobile")
+	})
+
+	t.Run("create resources with functions", func(t *testing.T) {
+		jsonPayload := func(body string, cb func(payload map[string]interface{})) httpmock.Responder {
+			return httpmock.WithHeader(httpmock.RESTPayload(200, body, cb), "Content-Type", "application/json")
+		}
+		function := func(id int, name string) string {
+			return fmt.Sprintf(`{"results": {"id": %d, "name": "%s", "language": "javascript", "code": "", "json_args": {},
+				"function_to_run": "", "initiator_type": "edge_application", "active": true, "last_editor": "",
+				"modified": "", "reference_count": 0}, "schema_version": 3}`, id, name)
+		}
+		instance := func(id int, name string, functionId int) string {
+			return fmt.Sprintf(`{"results": {"id": %d, "name": "%s", "edge_function_id": %d, "args": {}}, "schema_version": 3}`,
+				id, name, functionId)
+		}
+
+		mock := &httpmock.Registry{}
+		options := &contracts.AzionApplicationOptions{
+			Name:        "NotAVeryGoodName",
+			Application: contracts.AzionJsonDataApplication{ID: 1673635841},
+			Function:    contracts.AzionJsonDataFunction{InstanceID: 5, CacheId: 9},
+			Functions: []contracts.AzionJsonDataManifestFunction{
+				{Id: 70, Name: "legacy"},
+				{Id: 80, Name: "image"},
+			},
+			FunctionInstances: []contracts.AzionJsonDataFunctionInstance{
+				{Id: 71, Name: "legacy"},
+				{Id: 81, Name: "auth-api"},
+				{Id: 83, Name: "image"},
+			},
+		}
+
+		mock.Register(
+			httpmock.REST("POST", "edge_functions"),
+			jsonPayload(function(90, "auth"), func(payload map[string]interface{}) {
+				require.Equal(t, "auth", payload["name"])
+				require.Equal(t, "auth code", payload["code"])
+				require.Equal(t, map[string]interface{}{"issuer": "azion"}, payload["json_args"])
+				require.Equal(t, true, payload["active"])
+			}),
+		)
+		mock.Register(
+			httpmock.REST("PATCH", "edge_applications/1673635841/functions_instances/81"),
+			jsonPayload(instance(81, "auth-api", 90), func(payload map[string]interface{}) {
+				require.Equal(t, float64(90), payload["edge_function_id"])
+				require.Equal(t, map[string]interface{}{"issuer": "azion"}, payload["args"])
+			}),
+		)
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/functions_instances"),
+			jsonPayload(instance(82, "auth-admin", 90), func(payload map[string]interface{}) {
+				require.Equal(t, "auth-admin", payload["name"])
+				require.Equal(t, map[string]interface{}{"issuer": "admin"}, payload["args"])
+			}),
+		)
+		mock.Register(
+			httpmock.REST("PATCH", "edge_functions/80"),
+			jsonPayload(function(80, "image"), func(payload map[string]interface{}) {
+				require.Equal(t, false, payload["active"])
+			}),
+		)
+		mock.Register(
+			httpmock.REST("PATCH", "edge_applications/1673635841/functions_instances/83"),
+			jsonPayload(instance(83, "image", 80), func(payload map[string]interface{}) {
+				require.Equal(t, float64(80), payload["edge_function_id"])
+			}),
+		)
+
+		cacheSuccess, err := os.ReadFile("./fixtures/cachesuccess.json")
+		require.NoError(t, err)
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/cache_settings"),
+			jsonPayload(string(cacheSuccess), func(payload map[string]interface{}) {}),
+		)
+
+		rulesSuccess, err := os.ReadFile("./fixtures/rulessuccess.json")
+		require.NoError(t, err)
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/rules_engine/request/rules"),
+			jsonPayload(string(rulesSuccess), func(payload map[string]interface{}) {
+				behaviors := payload["behaviors"].([]interface{})
+				require.Contains(t, behaviors, map[string]interface{}{"name": "run_function", "target": "82"})
+			}),
+		)
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("DELETE", "edge_applications/1673635841/functions_instances/71"),
+			httpmock.StatusStringResponse(204, ""),
+		)
+		mock.Register(
+			httpmock.REST("DELETE", "edge_functions/70"),
+			httpmock.StatusStringResponse(204, ""),
+		)
+
+		f, _, _ := testutils.NewFactory(mock)
+
+		files := map[string]string{
+			"/project/functions/auth.js":    "auth code",
+			"/project/functions/auth.json":  `{"issuer": "azion"}`,
+			"/project/functions/admin.json": `{"issuer": "admin"}`,
+			"/project/functions/image.js":   "image code",
+		}
+		interpreter := NewManifestInterpreter()
+		interpreter.GetWorkDir = func() (string, error) {
+			return "/project", nil
+		}
+		interpreter.FileReader = func(path string) ([]byte, error) {
+			if content, ok := files[path]; ok {
+				return []byte(content), nil
+			}
+			return os.ReadFile(path)
+		}
+		interpreter.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+			return nil
+		}
+		interpreter.ConfirmDelete = func(deletions []contracts.ResourcePlan) bool {
+			return true
+		}
+
+		manifest, err := interpreter.ReadManifest("fixtures/manifest.json", f, &msgs)
+		require.NoError(t, err)
+		inactive := false
+		manifest.Functions = []contracts.Function{
+			{
+				Name: "auth",
+				Path: "functions/auth.js",
+				Args: "functions/auth.json",
+				Instances: []contracts.FunctionInstance{
+					{Name: "auth-api"},
+					{Name: "auth-admin", Args: "functions/admin.json"},
+				},
+			},
+			{Name: "image", Path: "functions/image.js", Active: &inactive},
+		}
+		manifest.Rules[0].Behaviors = append(manifest.Rules[0].Behaviors, sdk.RulesEngineBehaviorEntry{
+			RulesEngineBehaviorString: &sdk.RulesEngineBehaviorString{Name: "run_function", Target: "auth-admin"},
+		})
+
+		err = interpreter.CreateResources(options, manifest, f, "azion", &msgs)
+		require.NoError(t, err)
+		require.Equal(t, []contracts.AzionJsonDataManifestFunction{{Id: 90, Name: "auth"}, {Id: 80, Name: "image"}}, options.Functions)
+		require.Equal(t, []contracts.AzionJsonDataFunctionInstance{
+			{Id: 81, Name: "auth-api"},
+			{Id: 82, Name: "auth-admin"},
+			{Id: 83, Name: "image"},
+		}, options.FunctionInstances)
+		mock.Verify(t)
+	})
+
+	t.Run("create resources with domains", func(t *testing.T) {
+		jsonPayload := func(body string, cb func(payload map[string]interface{})) httpmock.Responder {
+			return httpmock.WithHeader(httpmock.RESTPayload(200, body, cb), "Content-Type", "application/json")
+		}
+		domain := func(id int, name, cnames string, cnameAccessOnly bool) string {
+			return fmt.Sprintf(`{"results": {"id": %d, "name": "%s", "cnames": %s, "cname_access_only": %t,
+				"digital_certificate_id": null, "edge_application_id": 1673635841, "is_active": true,
+				"domain_name": "%s.map.azionedge.net"}, "schema_version": 3}`, id, name, cnames, cnameAccessOnly, name)
+		}
+
+		mock := &httpmock.Registry{}
+		options := &contracts.AzionApplicationOptions{
+			Name:        "NotAVeryGoodName",
+			Application: contracts.AzionJsonDataApplication{ID: 1673635841},
+			Domains: []contracts.AzionJsonDataManifestDomain{
+				{Id: 7, Name: "old"},
+				{Id: 8, Name: "api", Cnames: []string{"api.example.com"}, DigitalCertificateId: 44},
+			},
+		}
+
+		cacheSuccess, err := os.ReadFile("./fixtures/cachesuccess.json")
+		require.NoError(t, err)
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/cache_settings"),
+			jsonPayload(string(cacheSuccess), func(payload map[string]interface{}) {}),
+		)
+		rulesSuccess, err := os.ReadFile("./fixtures/rulessuccess.json")
+		require.NoError(t, err)
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/rules_engine/request/rules"),
+			jsonPayload(string(rulesSuccess), func(payload map[string]interface{}) {}),
+		)
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("POST", "domains"),
+			jsonPayload(domain(9, "site", `["www.example.com"]`, true), func(payload map[string]interface{}) {
+				require.Equal(t, "site", payload["name"])
+				require.Equal(t, []interface{}{"www.example.com"}, payload["cnames"])
+				require.Equal(t, true, payload["cname_access_only"])
+				require.Equal(t, "55", payload["digital_certificate_id"])
+				require.Equal(t, float64(1673635841), payload["edge_application_id"])
+			}),
+		)
+		mock.Register(
+			httpmock.REST("PATCH", "domains/8"),
+			jsonPayload(domain(8, "api", `[]`, false), func(payload map[string]interface{}) {
+				require.Equal(t, []interface{}{}, payload["cnames"])
+				// the certificate recorded by the previous deploy is no longer declared
+				require.Equal(t, "0", payload["digital_certificate_id"])
+			}),
+		)
+		mock.Register(
+			httpmock.REST("DELETE", "domains/7"),
+			httpmock.StatusStringResponse(204, ""),
+		)
+
+		f, _, _ := testutils.NewFactory(mock)
+
+		interpreter := NewManifestInterpreter()
+		interpreter.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+			return nil
+		}
+		interpreter.ConfirmDelete = func(deletions []contracts.ResourcePlan) bool {
+			return true
+		}
+
+		manifest, err := interpreter.ReadManifest("fixtures/manifest.json", f, &msgs)
+		require.NoError(t, err)
+		cnameAccessOnly := true
+		certificate := int64(55)
+		manifest.Domains = []contracts.Domain{
+			{Name: "site", Cnames: []string{"www.example.com"}, CnameAccessOnly: &cnameAccessOnly, DigitalCertificateId: &certificate},
+			{Name: "api"},
+			// the project's domain is left to deploy
+			{Name: DefaultDomain, Cnames: []string{"example.com"}},
+		}
+
+		err = interpreter.CreateResources(options, manifest, f, "azion", &msgs)
+		require.NoError(t, err)
+		require.Equal(t, []contracts.AzionJsonDataManifestDomain{
+			{Id: 9, Name: "site", DomainName: "site.map.azionedge.net", Cnames: []string{"www.example.com"}, CnameAccessOnly: true},
+			{Id: 8, Name: "api", DomainName: "api.map.azionedge.net", Cnames: []string{}},
+		}, options.Domains)
+		require.Equal(t, []string{"www.example.com"}, Hostnames(options.Domains[0]))
+		require.Equal(t, []string{"api.map.azionedge.net"}, Hostnames(options.Domains[1]))
+		mock.Verify(t)
+	})
+
+	t.Run("create resources with variables", func(t *testing.T) {
+		jsonPayload := func(body string, cb func(payload map[string]interface{})) httpmock.Responder {
+			return httpmock.WithHeader(httpmock.RESTPayload(200, body, cb), "Content-Type", "application/json")
+		}
+		variable := func(uuid, key, value string, secret bool) string {
+			return fmt.Sprintf(`{"uuid": "%s", "key": "%s", "value": "%s", "secret": %t, "last_editor": "user@azion.com",
+				"created_at": "2024-06-13T13:17:13.145625Z", "updated_at": "2024-06-13T13:17:13.145666Z"}`, uuid, key, value, secret)
+		}
+
+		mock := &httpmock.Registry{}
+		options := &contracts.AzionApplicationOptions{
+			Name:        "NotAVeryGoodName",
+			Application: contracts.AzionJsonDataApplication{ID: 1673635841},
+			Variables: []contracts.AzionJsonDataVariable{
+				{Uuid: "u-old", Key: "OLD"},
+				{Uuid: "u-url", Key: "API_URL"},
+			},
+		}
+
+		cacheSuccess, err := os.ReadFile("./fixtures/cachesuccess.json")
+		require.NoError(t, err)
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/cache_settings"),
+			jsonPayload(string(cacheSuccess), func(payload map[string]interface{}) {}),
+		)
+		rulesSuccess, err := os.ReadFile("./fixtures/rulessuccess.json")
+		require.NoError(t, err)
+		mock.Register(
+			httpmock.REST("POST", "edge_applications/1673635841/rules_engine/request/rules"),
+			jsonPayload(string(rulesSuccess), func(payload map[string]interface{}) {}),
+		)
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("GET", "variables"),
+			httpmock.WithHeader(httpmock.StringResponse(fmt.Sprintf("[%s, %s, %s]",
+				variable("u-url", "API_URL", "https://old.example.com", false),
+				variable("u-same", "SAME", "same", false),
+				variable("u-old", "OLD", "old", false))), "Content-Type", "application/json"),
+		)
+		mock.Register(
+			httpmock.REST("PUT", "variables/u-url"),
+			jsonPayload(variable("u-url", "API_URL", "https://new.example.com", false), func(payload map[string]interface{}) {
+				require.Equal(t, "https://new.example.com", payload["value"])
+				require.Equal(t, false, payload["secret"])
+			}),
+		)
+		mock.Register(
+			httpmock.REST("POST", "variables"),
+			jsonPayload(variable("u-token", "TOKEN", "<secret>", true), func(payload map[string]interface{}) {
+				require.Equal(t, "TOKEN", payload["key"])
+				require.Equal(t, "s3cr3t", payload["value"])
+				require.Equal(t, true, payload["secret"])
+			}),
+		)
+		mock.Register(
+			httpmock.REST("DELETE", "variables/u-old"),
+			httpmock.StatusStringResponse(204, ""),
+		)
+
+		f, _, _ := testutils.NewFactory(mock)
+
+		envPath := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(envPath, []byte("TOKEN=s3cr3t\nAPI_URL=https://file.example.com\n"), 0644))
+
+		interpreter := NewManifestInterpreter()
+		interpreter.WriteAzionJsonContent = func(conf *contracts.AzionApplicationOptions, confPath string) error {
+			return nil
+		}
+		interpreter.ConfirmDelete = func(deletions []contracts.ResourcePlan) bool {
+			return true
+		}
+		interpreter.EnvPath = envPath
+		interpreter.LookupEnv = func(key string) (string, bool) {
+			value, ok := map[string]string{"API_URL": "https://new.example.com", "SAME": "same"}[key]
+			return value, ok
+		}
+
+		variableMsgs := []string{}
+		manifest, err := interpreter.ReadManifest("fixtures/manifest.json", f, &variableMsgs)
+		require.NoError(t, err)
+		manifest.Variables = []contracts.Variable{
+			{Key: "API_URL"},
+			{Key: "TOKEN", Secret: true},
+			{Key: "SAME"},
+		}
+
+		err = interpreter.CreateResources(options, manifest, f, "azion", &variableMsgs)
+		require.NoError(t, err)
+		require.Equal(t, []contracts.AzionJsonDataVariable{
+			{Uuid: "u-url", Key: "API_URL"},
+			{Uuid: "u-token", Key: "TOKEN", Secret: true},
+			{Uuid: "u-same", Key: "SAME"},
+		}, options.Variables)
+		require.Contains(t, variableMsgs, "Variable API_URL successfully updated: \"https://old.example.com\" -> \"https://new.example.com\"\n")
+		require.Contains(t, variableMsgs, "Variable TOKEN successfully created with value ********\n")
+		require.NotContains(t, strings.Join(variableMsgs, ""), "s3cr3t")
+		mock.Verify(t)
+	})
+
+	t.Run("create resources with a variable without value", func(t *testing.T) {
+		f, _, _ := testutils.NewFactory(&httpmock.Registry{})
+
+		interpreter := NewManifestInterpreter()
+		interpreter.EnvPath = filepath.Join(t.TempDir(), ".env")
+		interpreter.LookupEnv = func(key string) (string, bool) {
+			return "", false
+		}
+
+		manifest := &contracts.Manifest{Variables: []contracts.Variable{{Key: "TOKEN", Secret: true}}}
+		err := interpreter.CreateResources(&contracts.AzionApplicationOptions{}, manifest, f, "azion", &msgs)
+		require.ErrorContains(t, err, "Could not find the value of the variable TOKEN")
+	})
+
+	t.Run("plan resources", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+		options := &contracts.AzionApplicationOptions{
+			Name: "NotAVeryGoodName",
+			Application: contracts.AzionJsonDataApplication{
+				ID: 1673635841,
+			},
+			CacheSettings: []contracts.AzionJsonDataCacheSettings{
+				{Id: 107313, Name: "zoooop"},
+			},
+			Origin: []contracts.AzionJsonDataOrigin{
+				{OriginId: 88144, OriginKey: "0cee30cd", Name: "NotAVeryGoodName_single"},
+				{OriginId: 91799, OriginKey: "e4f0761b", Name: "Create Origin"},
+			},
+			RulesEngine: contracts.AzionJsonDataRulesEngine{
+				Rules: []contracts.AzionJsonDataRules{
+					{Id: 173617, Name: "old rule", Phase: "response"},
+					// shares its name with a request rule of the manifest
+					{Id: 173618, Name: "nomezinhomatotinho", Phase: "response"},
+				},
+			},
+		}
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/origins"),
+			httpmock.JSONFromFile("./fixtures/origins.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/cache_settings"),
+			httpmock.JSONFromFile("./fixtures/caches.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/response/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+
+		f, _, _ := testutils.NewFactory(mock)
+
+		interpreter := NewManifestInterpreter()
+		interpreter.ConfirmDelete = func(deletions []contracts.ResourcePlan) bool {
+			return true
+		}
+
+		pathManifest := "fixtures/manifest.json"
+		manifest, err := interpreter.ReadManifest(pathManifest, f, &msgs)
+		require.NoError(t, err)
+
+		plan, err := interpreter.PlanResources(options, manifest, f)
+		require.NoError(t, err)
+		require.Equal(t, []contracts.ResourcePlan{
+			{Resource: ResourceCache, Name: "zoooop", Id: "107313", Action: PlanUpdate, Details: msg.PlanNotFoundRemotely},
+			{Resource: ResourceRule, Name: "nomezinhomatotinho", Action: PlanCreate, Details: "Phase request"},
+			{Resource: ResourceRule, Name: "nomezinhomatotinho", Id: "173618", Action: PlanDelete, Details: "Phase response"},
+			{Resource: ResourceRule, Name: "old rule", Id: "173617", Action: PlanDelete, Details: "Phase response"},
+			{Resource: ResourceOrigin, Name: "Create Origin", Id: "91799", Action: PlanDelete, Details: msg.PlanNotReferenced},
+		}, plan)
+		mock.Verify(t)
+	})
+
+	t.Run("plan resources without pruning", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+		options := &contracts.AzionApplicationOptions{
+			Name: "NotAVeryGoodName",
+			Application: contracts.AzionJsonDataApplication{
+				ID: 1673635841,
+			},
+			CacheSettings: []contracts.AzionJsonDataCacheSettings{
+				{Id: 107313, Name: "zoooop"},
+			},
+			Origin: []contracts.AzionJsonDataOrigin{
+				{OriginId: 91799, OriginKey: "e4f0761b", Name: "Create Origin", PreventDestroy: true},
+			},
+			RulesEngine: contracts.AzionJsonDataRulesEngine{
+				Rules: []contracts.AzionJsonDataRules{
+					{Id: 173617, Name: "old rule", Phase: "response"},
+				},
+			},
+		}
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/origins"),
+			httpmock.JSONFromFile("./fixtures/origins.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/cache_settings"),
+			httpmock.JSONFromFile("./fixtures/caches.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/response/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+
+		f, _, _ := testutils.NewFactory(mock)
+
+		interpreter := NewManifestInterpreter()
+		interpreter.Prune = false
+
+		manifest, err := interpreter.ReadManifest("fixtures/manifest.json", f, &msgs)
+		require.NoError(t, err)
+
+		plan, err := interpreter.PlanResources(options, manifest, f)
+		require.NoError(t, err)
+		require.Equal(t, []contracts.ResourcePlan{
+			{Resource: ResourceCache, Name: "zoooop", Id: "107313", Action: PlanUpdate, Details: msg.PlanNotFoundRemotely},
+			{Resource: ResourceRule, Name: "nomezinhomatotinho", Action: PlanCreate, Details: "Phase request"},
+			{Resource: ResourceRule, Name: "old rule", Id: "173617", Action: PlanKeep, Details: msg.PlanPruneDisabled},
+			{Resource: ResourceOrigin, Name: "Create Origin", Id: "91799", Action: PlanKeep, Details: msg.PlanPreventDestroy},
+		}, plan)
+		mock.Verify(t)
+	})
+
+	t.Run("plan resources with unknown cache target", func(t *testing.T) {
+		f, _, _ := testutils.NewFactory(nil)
+		options := &contracts.AzionApplicationOptions{Name: "NotAVeryGoodName"}
+
+		interpreter := NewManifestInterpreter()
+
+		manifest, err := interpreter.ReadManifest("fixtures/manifest.json", f, &msgs)
+		require.NoError(t, err)
+		manifest.CacheSettings = nil
+
+		_, err = interpreter.PlanResources(options, manifest, f)
+		require.ErrorIs(t, err, msg.ErrorCacheNotFound)
+	})
+
+	t.Run("plan resources with unknown function instance", func(t *testing.T) {
+		f, _, _ := testutils.NewFactory(nil)
+		options := &contracts.AzionApplicationOptions{Name: "NotAVeryGoodName"}
+
+		interpreter := NewManifestInterpreter()
+
+		manifest, err := interpreter.ReadManifest("fixtures/manifest.json", f, &msgs)
+		require.NoError(t, err)
+		manifest.Functions = []contracts.Function{{Name: "auth", Path: "functions/auth.js"}}
+		manifest.Rules[0].Behaviors = append(manifest.Rules[0].Behaviors, sdk.RulesEngineBehaviorEntry{
+			RulesEngineBehaviorString: &sdk.RulesEngineBehaviorString{Name: "run_function", Target: "image"},
+		})
+
+		_, err = interpreter.PlanResources(options, manifest, f)
+		require.ErrorContains(t, err, "Could not find the edge function instance image")
+	})
 }
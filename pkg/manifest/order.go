@@ -0,0 +1,250 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	apiEdgeApplications "github.com/aziontech/azion-cli/pkg/api/edge_applications"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/utils"
+	sdk "github.com/aziontech/azionapi-go-sdk/edgeapplications"
+	"go.uber.org/zap"
+)
+
+// RuleOrder moves a rule of a phase from one position to another
+type RuleOrder struct {
+	Id    int64  `json:"id" yaml:"id" toml:"id"`
+	Name  string `json:"name" yaml:"name" toml:"name"`
+	Phase string `json:"phase" yaml:"phase" toml:"phase"`
+	From  int64  `json:"from" yaml:"from" toml:"from"`
+	To    int64  `json:"to" yaml:"to" toml:"to"`
+}
+
+// ListRules returns every rule of a phase in the order they run. Phases without rules have none.
+func ListRules(
+	ctx context.Context,
+	client *apiEdgeApplications.Client,
+	applicationID int64,
+	phase string) ([]sdk.RulesEngineResultResponse, error) {
+	opts := &contracts.ListOptions{
+		PageSize: 1000,
+		Page:     1,
+	}
+	rules := []sdk.RulesEngineResultResponse{}
+	for {
+		resp, err := client.ListRulesEngine(ctx, opts, applicationID, phase)
+		if err != nil {
+			if errors.Is(err, utils.ErrorNotFound404) {
+				break
+			}
+			logger.Debug("Error while listing rules engine", zap.String("phase", phase), zap.Error(err))
+			return nil, err
+		}
+		rules = append(rules, resp.GetResults()...)
+		if opts.Page >= resp.GetTotalPages() {
+			break
+		}
+		opts.Page++
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].GetOrder() < rules[j].GetOrder()
+	})
+	return rules, nil
+}
+
+// findRulePhase returns the phase a rule belongs to. Rules not found in any phase are looked for in the request phase,
+// the only one azion.json used to track.
+func findRulePhase(ctx context.Context, client *apiEdgeApplications.Client, applicationID, id int64) (string, error) {
+	for _, phase := range apiEdgeApplications.RulesEnginePhases {
+		rules, err := ListRules(ctx, client, applicationID, phase)
+		if err != nil {
+			return "", err
+		}
+		for _, rule := range rules {
+			if rule.GetId() == id {
+				return phase, nil
+			}
+		}
+	}
+	return "request", nil
+}
+
+// ReorderRules computes the order of the rules of a phase: the ones in desired go first, in that order, followed by the
+// others in the order they already run. The default rule always runs first and keeps its place. Only the rules whose
+// position changes are returned.
+func ReorderRules(rules []sdk.RulesEngineResultResponse, desired []int64) []RuleOrder {
+	byId := make(map[int64]sdk.RulesEngineResultResponse)
+	for _, rule := range rules {
+		if rule.GetName() != defaultRule {
+			byId[rule.GetId()] = rule
+		}
+	}
+
+	ordered := []sdk.RulesEngineResultResponse{}
+	placed := make(map[int64]bool)
+	for _, id := range desired {
+		if rule, ok := byId[id]; ok && !placed[id] {
+			ordered = append(ordered, rule)
+			placed[id] = true
+		}
+	}
+	for _, rule := range rules {
+		if _, ok := byId[rule.GetId()]; ok && !placed[rule.GetId()] {
+			ordered = append(ordered, rule)
+			placed[rule.GetId()] = true
+		}
+	}
+
+	changes := []RuleOrder{}
+	for i, rule := range ordered {
+		position := int64(i + 1)
+		if rule.GetOrder() == position {
+			continue
+		}
+		changes = append(changes, RuleOrder{
+			Id:    rule.GetId(),
+			Name:  rule.GetName(),
+			Phase: rule.GetPhase(),
+			From:  rule.GetOrder(),
+			To:    position,
+		})
+	}
+	return changes
+}
+
+// UnknownRules returns the IDs of desired that ReorderRules can't move: the ones that aren't rules of the phase, and
+// the default rule
+func UnknownRules(rules []sdk.RulesEngineResultResponse, desired []int64) []int64 {
+	known := make(map[int64]bool)
+	for _, rule := range rules {
+		if rule.GetName() != defaultRule {
+			known[rule.GetId()] = true
+		}
+	}
+	unknown := []int64{}
+	for _, id := range desired {
+		if !known[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	return unknown
+}
+
+// ApplyRuleOrder moves every rule to its new position
+func ApplyRuleOrder(
+	ctx context.Context,
+	client *apiEdgeApplications.Client,
+	applicationID int64,
+	changes []RuleOrder) error {
+	for _, change := range changes {
+		request := &apiEdgeApplications.UpdateRulesEngineRequest{
+			IdApplication: applicationID,
+			Phase:         change.Phase,
+			Id:            change.Id,
+		}
+		request.SetOrder(change.To)
+		if _, err := client.UpdateRulesEngine(ctx, request); err != nil {
+			logger.Debug("Error while reordering a rule", zap.Int64("id", change.Id), zap.Error(err))
+			return fmt.Errorf("%w: %s", msg.ErrorReorderRules, err.Error())
+		}
+	}
+	return nil
+}
+
+// manifestRuleOrder returns the names of the rules of a phase in the order the manifest runs them: by their order
+// field, and in the order they are declared when it is equal or missing
+func manifestRuleOrder(manifest *contracts.Manifest, phase string) []string {
+	rules := []contracts.RuleEngine{}
+	for _, rule := range manifest.Rules {
+		if rule.Phase == phase {
+			rules = append(rules, rule)
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Order < rules[j].Order
+	})
+
+	names := make([]string, len(rules))
+	for i, rule := range rules {
+		names[i] = rule.Name
+	}
+	return names
+}
+
+// ruleIdsByName turns names of rules into the IDs of the rules of a phase, leaving out the ones it doesn't have
+func ruleIdsByName(rules []sdk.RulesEngineResultResponse, names []string) []int64 {
+	ids := make(map[string]int64)
+	for _, rule := range rules {
+		ids[rule.GetName()] = rule.GetId()
+	}
+	desired := []int64{}
+	for _, name := range names {
+		if id, ok := ids[name]; ok {
+			desired = append(desired, id)
+		}
+	}
+	return desired
+}
+
+// orderRules converges the order of the rules of each phase the manifest declares rules for, in a single pass once
+// every rule exists. Rules the manifest doesn't declare run after its own.
+func (man *ManifestInterpreter) orderRules(
+	ctx context.Context,
+	f *cmdutil.Factory,
+	conf *contracts.AzionApplicationOptions,
+	manifest *contracts.Manifest,
+	msgs *[]string) error {
+	client := apiEdgeApplications.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+	for _, phase := range apiEdgeApplications.RulesEnginePhases {
+		names := manifestRuleOrder(manifest, phase)
+		if len(names) == 0 {
+			continue
+		}
+
+		rules, err := ListRules(ctx, client, conf.Application.ID, phase)
+		if err != nil {
+			return fmt.Errorf("%w: %s", msg.ErrorReorderRules, err.Error())
+		}
+		changes := ReorderRules(rules, ruleIdsByName(rules, names))
+		if err := ApplyRuleOrder(ctx, client, conf.Application.ID, changes); err != nil {
+			return err
+		}
+		for _, change := range changes {
+			man.changed(ResourceRule, change.Name, strconv.FormatInt(change.Id, 10), PlanReorder)
+			msgf := fmt.Sprintf(msg.ManifestReorderRule, change.Name, change.Phase, change.From, change.To)
+			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+			*msgs = append(*msgs, msgf)
+		}
+	}
+	return nil
+}
+
+// planRuleOrder computes the moves orderRules would make to the rules that already exist. Rules created by the deploy
+// take their place when they are created.
+func (man *ManifestInterpreter) planRuleOrder(manifest *contracts.Manifest, remote *remoteState) []contracts.ResourcePlan {
+	plan := []contracts.ResourcePlan{}
+	for _, phase := range apiEdgeApplications.RulesEnginePhases {
+		names := manifestRuleOrder(manifest, phase)
+		if len(names) == 0 {
+			continue
+		}
+		rules := remote.ruleOrder[phase]
+		for _, change := range ReorderRules(rules, ruleIdsByName(rules, names)) {
+			plan = append(plan, contracts.ResourcePlan{
+				Resource: ResourceRule,
+				Name:     change.Name,
+				Id:       strconv.FormatInt(change.Id, 10),
+				Action:   PlanReorder,
+				Details:  fmt.Sprintf(msg.PlanRuleOrder, change.Phase, change.From, change.To),
+			})
+		}
+	}
+	return plan
+}
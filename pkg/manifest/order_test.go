@@ -0,0 +1,106 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	apiEdgeApplications "github.com/aziontech/azion-cli/pkg/api/edge_applications"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/httpmock"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/pkg/testutils"
+	sdk "github.com/aziontech/azionapi-go-sdk/edgeapplications"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRuleOrder(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+	rules := []sdk.RulesEngineResultResponse{
+		{Id: 1, Name: defaultRule, Phase: "request", Order: 0},
+		{Id: 10, Name: "images", Phase: "request", Order: 1},
+		{Id: 11, Name: "api", Phase: "request", Order: 2},
+		{Id: 12, Name: "legacy", Phase: "request", Order: 3},
+	}
+
+	t.Run("reorder rules", func(t *testing.T) {
+		require.Equal(t, []RuleOrder{
+			{Id: 11, Name: "api", Phase: "request", From: 2, To: 1},
+			{Id: 10, Name: "images", Phase: "request", From: 1, To: 2},
+		}, ReorderRules(rules, []int64{11, 1, 404}))
+
+		require.Empty(t, ReorderRules(rules, []int64{10, 11, 12}))
+		require.Equal(t, []int64{1, 404}, UnknownRules(rules, []int64{11, 1, 404}))
+	})
+
+	t.Run("manifest order", func(t *testing.T) {
+		manifest := &contracts.Manifest{Rules: []contracts.RuleEngine{
+			{Name: "images", Phase: "request"},
+			{Name: "headers", Phase: "response", Order: 1},
+			{Name: "legacy", Phase: "request", Order: 2},
+			{Name: "api", Phase: "request", Order: 1},
+		}}
+		require.Equal(t, []string{"images", "api", "legacy"}, manifestRuleOrder(manifest, "request"))
+		require.Equal(t, []string{"headers"}, manifestRuleOrder(manifest, "response"))
+		require.Equal(t, []int64{12, 11}, ruleIdsByName(rules, []string{"legacy", "missing", "api"}))
+	})
+
+	t.Run("converge the order of the rules", func(t *testing.T) {
+		rule := func(id int64, name, phase string, order int64) string {
+			return fmt.Sprintf(`{"id": %d, "name": %q, "phase": %q, "criteria": [], "is_active": true, "order": %d}`,
+				id, name, phase, order)
+		}
+
+		mock := &httpmock.Registry{}
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/request/rules"),
+			httpmock.JSONFromString(fmt.Sprintf(`{"count": 3, "total_pages": 1, "schema_version": 3, "links": {}, "results": [%s, %s, %s]}`,
+				rule(1, defaultRule, "default", 0), rule(11, "api", "request", 2), rule(10, "images", "request", 1))),
+		)
+		for id, order := range map[int64]int64{10: 2, 11: 1} {
+			order := order
+			mock.Register(
+				httpmock.REST("PATCH", fmt.Sprintf("edge_applications/1673635841/rules_engine/request/rules/%d", id)),
+				httpmock.WithHeader(httpmock.RESTPayload(200,
+					fmt.Sprintf(`{"results": %s, "schema_version": 3}`, rule(id, "rule", "request", order)),
+					func(payload map[string]interface{}) {
+						require.Equal(t, float64(order), payload["order"])
+					}), "Content-Type", "application/json"),
+			)
+		}
+
+		f, _, _ := testutils.NewFactory(mock)
+		conf := &contracts.AzionApplicationOptions{Application: contracts.AzionJsonDataApplication{ID: 1673635841}}
+		manifest := &contracts.Manifest{Rules: []contracts.RuleEngine{
+			{Name: "api", Phase: "request", Order: 1},
+			{Name: "images", Phase: "request", Order: 2},
+		}}
+
+		var msgs []string
+		interpreter := NewManifestInterpreter()
+		require.NoError(t, interpreter.orderRules(context.Background(), f, conf, manifest, &msgs))
+		require.Len(t, msgs, 2)
+		mock.Verify(t)
+	})
+
+	t.Run("find the phase of a rule", func(t *testing.T) {
+		mock := &httpmock.Registry{}
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/request/rules"),
+			httpmock.JSONFromFile("./fixtures/rules.json"),
+		)
+		mock.Register(
+			httpmock.REST("GET", "edge_applications/1673635841/rules_engine/response/rules"),
+			httpmock.JSONFromString(`{"count": 1, "total_pages": 1, "schema_version": 3, "links": {}, "results": [
+				{"id": 20, "name": "enable gzip", "phase": "response", "criteria": [], "is_active": true, "order": 1}]}`),
+		)
+
+		f, _, _ := testutils.NewFactory(mock)
+		client := apiEdgeApplications.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+		phase, err := findRulePhase(context.Background(), client, 1673635841, 20)
+		require.NoError(t, err)
+		require.Equal(t, "response", phase)
+		mock.Verify(t)
+	})
+}
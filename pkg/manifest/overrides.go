@@ -0,0 +1,92 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// applyOverrides merges the overrides file into the manifest: each resource replaces the one of its section with the
+// same name, or the rule with the same phase and name, and is added when there is none. A missing file leaves the
+// manifest untouched.
+func (man *ManifestInterpreter) applyOverrides(manifest *contracts.Manifest, f *cmdutil.Factory, msgs *[]string) error {
+	content, err := man.FileReader(man.OverridesPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		logger.Debug("Error while reading manifest overrides", zap.Error(err))
+		return fmt.Errorf(msg.ErrorReadOverrides.Error(), man.OverridesPath, err)
+	}
+
+	content, err = man.decodeManifest(man.OverridesPath, content)
+	if err != nil {
+		return err
+	}
+
+	overrides := &contracts.Manifest{}
+	if err := json.Unmarshal(content, overrides); err != nil {
+		logger.Debug("Error while unmarshalling manifest overrides", zap.Error(err))
+		return fmt.Errorf(msg.ErrorReadOverrides.Error(), man.OverridesPath, err)
+	}
+
+	msgf := fmt.Sprintf(msg.ApplyingOverrides, man.OverridesPath)
+	logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+	*msgs = append(*msgs, msgf)
+
+	for _, origin := range overrides.Origins {
+		manifest.Origins = override(manifest.Origins, origin, func(o contracts.Origin) string { return o.Name })
+	}
+	for _, cache := range overrides.CacheSettings {
+		manifest.CacheSettings = override(manifest.CacheSettings, cache, cacheName)
+	}
+	for _, rule := range overrides.Rules {
+		manifest.Rules = override(manifest.Rules, rule, func(r contracts.RuleEngine) string {
+			return RuleKey(r.Phase, r.Name)
+		})
+	}
+	for _, group := range overrides.DeviceGroups {
+		manifest.DeviceGroups = override(manifest.DeviceGroups, group, func(g contracts.DeviceGroup) string {
+			return g.Name
+		})
+	}
+	for _, function := range overrides.Functions {
+		manifest.Functions = override(manifest.Functions, function, func(fn contracts.Function) string {
+			return fn.Name
+		})
+	}
+	for _, domain := range overrides.Domains {
+		manifest.Domains = override(manifest.Domains, domain, func(d contracts.Domain) string { return d.Name })
+	}
+	for _, variable := range overrides.Variables {
+		manifest.Variables = override(manifest.Variables, variable, func(v contracts.Variable) string {
+			return v.Key
+		})
+	}
+
+	return nil
+}
+
+func override[T any](items []T, item T, name func(T) string) []T {
+	for i := range items {
+		if name(items[i]) == name(item) {
+			items[i] = item
+			return items
+		}
+	}
+	return append(items, item)
+}
+
+func cacheName(cache contracts.CacheSetting) string {
+	if cache.Name == nil {
+		return ""
+	}
+	return *cache.Name
+}
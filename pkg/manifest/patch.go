@@ -0,0 +1,339 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// section is a list of the manifest along with the entries added to it
+type section struct {
+	key   string
+	items []interface{}
+}
+
+// PatchManifest adds the entries of additions to the lists of the manifest in source, in the format of path. What the
+// manifest already declares is kept as it is written, along with its comments, key order and environment variables.
+func PatchManifest(path string, source []byte, additions *contracts.Manifest) ([]byte, error) {
+	sections := []section{
+		{key: "origin", items: items(additions.Origins)},
+		{key: "cache", items: items(additions.CacheSettings)},
+		{key: "device_groups", items: items(additions.DeviceGroups)},
+		{key: "rules", items: items(additions.Rules)},
+	}
+
+	patch := patchJSON
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		patch = patchYAML
+	case ".toml":
+		patch = patchTOML
+	}
+
+	patched := source
+	for _, s := range sections {
+		if len(s.items) == 0 {
+			continue
+		}
+		var err error
+		patched, err = patch(patched, s)
+		if err != nil {
+			return nil, fmt.Errorf(msg.ErrorPatchManifest.Error(), path, err)
+		}
+	}
+
+	if err := checkPatch(path, source, patched, additions); err != nil {
+		return nil, fmt.Errorf(msg.ErrorPatchManifest.Error(), path, err)
+	}
+	return patched, nil
+}
+
+func items[T any](entries []T) []interface{} {
+	converted := make([]interface{}, len(entries))
+	for i, entry := range entries {
+		converted[i] = entry
+	}
+	return converted
+}
+
+// checkPatch makes sure the patched manifest still reads, with the added entries on top of the declared ones
+func checkPatch(path string, source, patched []byte, additions *contracts.Manifest) error {
+	// the values of the variables don't matter, only the entries do
+	man := &ManifestInterpreter{LookupEnv: func(string) (string, bool) { return "", true }}
+	count := func(raw []byte) ([]int, error) {
+		manifest := &contracts.Manifest{}
+		if len(bytes.TrimSpace(raw)) > 0 {
+			decoded, err := man.decodeManifest(path, raw)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(decoded, manifest); err != nil {
+				return nil, err
+			}
+		}
+		return []int{len(manifest.Origins), len(manifest.CacheSettings), len(manifest.DeviceGroups), len(manifest.Rules)}, nil
+	}
+
+	before, err := count(source)
+	if err != nil {
+		return err
+	}
+	after, err := count(patched)
+	if err != nil {
+		return err
+	}
+	added := []int{len(additions.Origins), len(additions.CacheSettings), len(additions.DeviceGroups), len(additions.Rules)}
+	for i := range added {
+		if after[i] != before[i]+added[i] {
+			return errors.New(msg.PatchLostEntries)
+		}
+	}
+	return nil
+}
+
+// patchJSON inserts the entries before the end of the list, or adds the list before the end of the manifest
+func patchJSON(source []byte, s section) ([]byte, error) {
+	if len(bytes.TrimSpace(source)) == 0 {
+		source = []byte("{}\n")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(source))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return nil, errors.New(msg.PatchNotAnObject)
+	}
+	// the first key tells how the manifest is indented
+	indent := "  "
+	first := true
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		keyIndent := lineIndent(source, dec.InputOffset())
+		if first && keyIndent != "" {
+			indent = keyIndent
+		}
+		first = false
+		if token != s.key {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if token, err := dec.Token(); err != nil || token != json.Delim('[') {
+			return nil, fmt.Errorf(msg.PatchNotAList, s.key)
+		}
+		for dec.More() {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		end := int(dec.InputOffset()) - 1
+		rendered, err := renderJSON(s.items, keyIndent+indent, indent)
+		if err != nil {
+			return nil, err
+		}
+		return insertJSON(source, end, '[', rendered, keyIndent), nil
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	end := int(dec.InputOffset()) - 1
+	rendered, err := renderJSON(s.items, indent+indent, indent)
+	if err != nil {
+		return nil, err
+	}
+	list := fmt.Sprintf("%s%q: [\n%s\n%s]", indent, s.key, rendered, indent)
+	return insertJSON(source, end, '{', list, ""), nil
+}
+
+// insertJSON adds text as the last element of the object or list that closes at end
+func insertJSON(source []byte, end int, open byte, text, closeIndent string) []byte {
+	before := bytes.TrimRight(source[:end], " \t\r\n")
+	patched := append([]byte{}, before...)
+	if before[len(before)-1] == open {
+		patched = append(patched, "\n"+text+"\n"+closeIndent...)
+		return append(patched, source[end:]...)
+	}
+	patched = append(patched, ",\n"+text...)
+	return append(patched, source[len(before):]...)
+}
+
+// renderJSON writes the entries as the elements of a list, starting at indent and indenting their fields by unit
+func renderJSON(entries []interface{}, indent, unit string) (string, error) {
+	rendered := make([]string, len(entries))
+	for i, entry := range entries {
+		data, err := json.MarshalIndent(entry, indent, unit)
+		if err != nil {
+			return "", err
+		}
+		rendered[i] = indent + string(data)
+	}
+	return strings.Join(rendered, ",\n"), nil
+}
+
+// lineIndent returns the blanks the line of offset starts with
+func lineIndent(source []byte, offset int64) string {
+	start := bytes.LastIndexByte(source[:offset], '\n') + 1
+	end := start
+	for end < len(source) && (source[end] == ' ' || source[end] == '\t') {
+		end++
+	}
+	return string(source[start:end])
+}
+
+// patchYAML writes the entries below the last one of the list, or adds the list at the end of the manifest
+func patchYAML(source []byte, s section) ([]byte, error) {
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(source, doc); err != nil {
+		return nil, err
+	}
+	lines := strings.SplitAfter(string(source), "\n")
+
+	var root *yaml.Node
+	if len(doc.Content) > 0 {
+		root = doc.Content[0]
+		if root.Kind != yaml.MappingNode {
+			return nil, errors.New(msg.PatchNotAnObject)
+		}
+	}
+
+	for i := 0; root != nil && i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value != s.key {
+			continue
+		}
+
+		switch {
+		case value.Kind == yaml.SequenceNode && value.Style&yaml.FlowStyle == 0 && len(value.Content) > 0:
+			// the list ends where the next key starts, past the blank lines and comments before it
+			end := len(lines)
+			if i+2 < len(root.Content) {
+				end = root.Content[i+2].Line - 1
+				for end > value.Line && isBlankOrComment(lines[end-1]) {
+					end--
+				}
+			}
+			rendered, err := renderYAML(s.items, strings.Repeat(" ", value.Content[0].Column-3))
+			if err != nil {
+				return nil, err
+			}
+			return []byte(joinLines(lines[:end], rendered, lines[end:])), nil
+		case value.Kind == yaml.SequenceNode && len(value.Content) == 0 && value.Line == key.Line,
+			value.Kind == yaml.ScalarNode && value.Tag == "!!null" && value.Line == key.Line:
+			// an empty list such as origin: [] is written as a block list, keeping the comment after it
+			line := strings.TrimRight(lines[key.Line-1], "\r\n")
+			start := min(value.Column-1, len(line))
+			width := len(value.Value)
+			if value.Kind == yaml.SequenceNode {
+				width = strings.IndexByte(line[start:], ']') + 1
+			}
+			head := strings.TrimRight(line[:start], " ") + line[min(start+width, len(line)):]
+			rendered, err := renderYAML(s.items, strings.Repeat(" ", key.Column+1))
+			if err != nil {
+				return nil, err
+			}
+			before := append(append([]string{}, lines[:key.Line-1]...), head+"\n")
+			return []byte(joinLines(before, rendered, lines[key.Line:])), nil
+		default:
+			return nil, fmt.Errorf(msg.PatchNotAList, s.key)
+		}
+	}
+
+	rendered, err := renderYAML(s.items, "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []byte(joinLines(lines, s.key+":\n"+rendered, nil)), nil
+}
+
+// renderYAML writes the entries as the items of a block list, in the order of their fields
+func renderYAML(entries []interface{}, indent string) (string, error) {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return "", err
+	}
+	// JSON is YAML, and decoding it keeps the order of the fields
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return "", err
+	}
+	blockStyle(node)
+	encoded, err := yaml.Marshal(node)
+	if err != nil {
+		return "", err
+	}
+
+	rendered := strings.Builder{}
+	for _, line := range strings.SplitAfter(strings.TrimRight(string(encoded), "\n"), "\n") {
+		rendered.WriteString(indent + line)
+	}
+	rendered.WriteString("\n")
+	return rendered.String(), nil
+}
+
+func blockStyle(n *yaml.Node) {
+	n.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	for _, child := range n.Content {
+		blockStyle(child)
+	}
+}
+
+func isBlankOrComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+// joinLines puts text between two runs of lines, making sure it starts on a line of its own
+func joinLines(before []string, text string, after []string) string {
+	joined := strings.Join(before, "")
+	if joined != "" && !strings.HasSuffix(joined, "\n") {
+		joined += "\n"
+	}
+	return joined + text + strings.Join(after, "")
+}
+
+// patchTOML appends the entries to the end of the manifest as tables of the list. Lists written inline, such as
+// origin = [...], can't be appended to.
+func patchTOML(source []byte, s section) ([]byte, error) {
+	inline := regexp.MustCompile(`(?m)^\s*` + regexp.QuoteMeta(s.key) + `\s*=`)
+	if inline.Match(source) {
+		return nil, fmt.Errorf(msg.PatchNotAList, s.key)
+	}
+
+	// the entries are converted through JSON, since their fields are named by their json tags
+	data, err := json.Marshal(s.items)
+	if err != nil {
+		return nil, err
+	}
+	tables := []interface{}{}
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return nil, err
+	}
+	encoded, err := toml.Marshal(map[string]interface{}{s.key: tables})
+	if err != nil {
+		return nil, err
+	}
+
+	patched := string(source)
+	if patched != "" {
+		patched = strings.TrimRight(patched, "\n") + "\n\n"
+	}
+	return []byte(patched + string(encoded)), nil
+}
@@ -0,0 +1,173 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	sdk "github.com/aziontech/azionapi-go-sdk/edgeapplications"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchManifest(t *testing.T) {
+	additions := &contracts.Manifest{
+		Origins: []contracts.Origin{{
+			Name:       "console",
+			OriginType: "single_origin",
+			Addresses:  []sdk.CreateOriginsRequestAddresses{{Address: "console.example.com"}},
+		}},
+		Rules: []contracts.RuleEngine{{Name: "console", Phase: "request"}},
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		source   string
+		expected string
+		err      bool
+	}{
+		{
+			name: "json keeps the declared entries as written",
+			path: "manifest.json",
+			source: `{
+    "origin": [
+        {"name": "api", "addresses": [{"address": "${API_HOST}"}]}
+    ],
+    "rules": []
+}
+`,
+			expected: `{
+    "origin": [
+        {"name": "api", "addresses": [{"address": "${API_HOST}"}]},
+        {
+            "name": "console",
+            "origin_type": "single_origin",
+            "addresses": [
+                {
+                    "address": "console.example.com"
+                }
+            ]
+        }
+    ],
+    "rules": [
+        {
+            "name": "console",
+            "phase": "request"
+        }
+    ]
+}
+`,
+		},
+		{
+			name:   "json without the lists",
+			path:   "manifest.json",
+			source: "{\n  \"functions\": []\n}\n",
+			expected: `{
+  "functions": [],
+  "origin": [
+    {
+      "name": "console",
+      "origin_type": "single_origin",
+      "addresses": [
+        {
+          "address": "console.example.com"
+        }
+      ]
+    }
+  ],
+  "rules": [
+    {
+      "name": "console",
+      "phase": "request"
+    }
+  ]
+}
+`,
+		},
+		{
+			name: "yaml keeps comments and key order",
+			path: "manifest.yaml",
+			source: `# project manifest
+origin:
+  - name: api # the public API
+    addresses:
+      - address: ${API_HOST}
+
+# rules come last
+rules: [] # none yet
+`,
+			expected: `# project manifest
+origin:
+  - name: api # the public API
+    addresses:
+      - address: ${API_HOST}
+  - name: console
+    origin_type: single_origin
+    addresses:
+      - address: console.example.com
+
+# rules come last
+rules: # none yet
+  - name: console
+    phase: request
+`,
+		},
+		{
+			name:   "yaml without the lists",
+			path:   "manifest.yml",
+			source: "functions: []",
+			expected: `functions: []
+origin:
+  - name: console
+    origin_type: single_origin
+    addresses:
+      - address: console.example.com
+rules:
+  - name: console
+    phase: request
+`,
+		},
+		{
+			name:   "toml appends tables",
+			path:   "manifest.toml",
+			source: "[[origin]]\nname = \"api\"\n",
+			expected: `[[origin]]
+name = "api"
+
+[[origin]]
+name = 'console'
+origin_type = 'single_origin'
+
+[[origin.addresses]]
+address = 'console.example.com'
+
+[[rules]]
+name = 'console'
+phase = 'request'
+`,
+		},
+		{
+			name:   "toml inline list",
+			path:   "manifest.toml",
+			source: "origin = [{name = \"api\"}]\n",
+			err:    true,
+		},
+		{
+			name:   "json list of another type",
+			path:   "manifest.json",
+			source: `{"origin": {"name": "api"}}`,
+			err:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched, err := PatchManifest(tt.path, []byte(tt.source), additions)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, string(patched))
+		})
+	}
+}
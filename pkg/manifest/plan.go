@@ -0,0 +1,454 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	apiDomain "github.com/aziontech/azion-cli/pkg/api/domain"
+	apiEdgeApplications "github.com/aziontech/azion-cli/pkg/api/edge_applications"
+	apiEdgeFunction "github.com/aziontech/azion-cli/pkg/api/edge_function"
+	apiOrigin "github.com/aziontech/azion-cli/pkg/api/origin"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"github.com/aziontech/azion-cli/utils"
+	sdk "github.com/aziontech/azionapi-go-sdk/edgeapplications"
+	"go.uber.org/zap"
+)
+
+const (
+	PlanCreate = "create"
+	PlanUpdate = "update"
+	PlanDelete = "delete"
+	PlanKeep   = "keep"
+	// PlanReorder moves a rule to another position of its phase
+	PlanReorder = "reorder"
+
+	ResourceOrigin      = "origin"
+	ResourceCache       = "cache_setting"
+	ResourceRule        = "rules_engine"
+	ResourceDeviceGroup = "device_group"
+	// the functions and domains of the manifest are told apart from the project's ones, which deploy manages itself
+	ResourceFunction         = "manifest_function"
+	ResourceFunctionInstance = "manifest_function_instance"
+	ResourceDomain           = "manifest_domain"
+	ResourceVariable         = "variable"
+)
+
+// remoteState holds what currently exists on the edge application, indexed by name
+type remoteState struct {
+	origins      map[string]bool
+	caches       map[string]bool
+	rules        map[string]bool
+	deviceGroups map[string]bool
+	functions    map[string]bool
+	instances    map[string]bool
+	domains      map[string]bool
+	// ruleOrder holds the rules of each phase in the order they run
+	ruleOrder map[string][]sdk.RulesEngineResultResponse
+}
+
+// PlanResources computes the actions CreateResources would take for the manifest, using only read calls.
+// It mirrors the bookkeeping of CreateResources, including the removal of resources that are no longer
+// referenced by any rule and the ones kept instead because they are protected or pruning is disabled.
+func (man *ManifestInterpreter) PlanResources(
+	conf *contracts.AzionApplicationOptions,
+	manifest *contracts.Manifest,
+	f *cmdutil.Factory) ([]contracts.ResourcePlan, error) {
+	plan := []contracts.ResourcePlan{}
+	ctx := context.Background()
+
+	remote, err := readRemoteState(ctx, f, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	protected := newProtection(conf, manifest)
+
+	groupIds := make(map[string]int64)
+	for _, group := range conf.DeviceGroups {
+		groupIds[group.Name] = group.Id
+	}
+	declaredGroups := make(map[string]bool)
+	for _, group := range manifest.DeviceGroups {
+		declaredGroups[group.Name] = true
+		if id := groupIds[group.Name]; id > 0 {
+			plan = append(plan, updateAction(ResourceDeviceGroup, group.Name, fmt.Sprint(id), remote.deviceGroups))
+			continue
+		}
+		plan = append(plan, contracts.ResourcePlan{Resource: ResourceDeviceGroup, Name: group.Name, Action: PlanCreate})
+		groupIds[group.Name] = -1
+	}
+
+	functionIds := make(map[string]int64)
+	for _, function := range conf.Functions {
+		functionIds[function.Name] = function.Id
+	}
+	instanceIds := make(map[string]int64)
+	for _, instance := range conf.FunctionInstances {
+		instanceIds[instance.Name] = instance.Id
+	}
+	declaredFunctions := make(map[string]bool)
+	declaredInstances := make(map[string]bool)
+	for _, function := range manifest.Functions {
+		declaredFunctions[function.Name] = true
+		if id := functionIds[function.Name]; id > 0 {
+			plan = append(plan, updateAction(ResourceFunction, function.Name, fmt.Sprint(id), remote.functions))
+		} else {
+			plan = append(plan, contracts.ResourcePlan{Resource: ResourceFunction, Name: function.Name, Action: PlanCreate})
+		}
+		for _, instance := range instancesOf(function) {
+			declaredInstances[instance.Name] = true
+			if id := instanceIds[instance.Name]; id > 0 {
+				plan = append(plan, updateAction(ResourceFunctionInstance, instance.Name, fmt.Sprint(id), remote.instances))
+				continue
+			}
+			plan = append(plan, contracts.ResourcePlan{Resource: ResourceFunctionInstance, Name: instance.Name, Action: PlanCreate})
+		}
+	}
+
+	originIds := make(map[string]int64)
+	originKeys := make(map[string]string)
+	for _, origin := range conf.Origin {
+		originIds[origin.Name] = origin.OriginId
+		originKeys[origin.Name] = origin.OriginKey
+	}
+
+	cacheIds := make(map[string]int64)
+	for _, cache := range conf.CacheSettings {
+		cacheIds[cache.Name] = cache.Id
+	}
+
+	ruleIds := make(map[string]contracts.RuleIdsStruct)
+	for _, rule := range conf.RulesEngine.Rules {
+		ruleIds[RuleKey(rule.Phase, rule.Name)] = contracts.RuleIdsStruct{
+			Id:    rule.Id,
+			Name:  rule.Name,
+			Phase: rule.Phase,
+		}
+	}
+
+	for _, origin := range manifest.Origins {
+		name := origin.Name
+		if name == "" {
+			name = conf.Name
+		}
+		if id := originIds[origin.Name]; id > 0 {
+			plan = append(plan, updateAction(ResourceOrigin, name, fmt.Sprint(id), remote.origins))
+			continue
+		}
+		plan = append(plan, contracts.ResourcePlan{Resource: ResourceOrigin, Name: name, Action: PlanCreate})
+		originIds[name] = -1
+		originKeys[name] = ""
+	}
+
+	for _, cache := range manifest.CacheSettings {
+		name := ""
+		if cache.Name != nil {
+			name = *cache.Name
+		}
+		for _, group := range cache.DeviceGroups {
+			if groupIds[group] == 0 {
+				logger.Debug("Device group not found", zap.Any("Name", group))
+				return nil, fmt.Errorf(msg.ErrorDeviceGroupNotFound.Error(), group)
+			}
+		}
+		if id := cacheIds[name]; id > 0 {
+			plan = append(plan, updateAction(ResourceCache, name, fmt.Sprint(id), remote.caches))
+			continue
+		}
+		plan = append(plan, contracts.ResourcePlan{Resource: ResourceCache, Name: name, Action: PlanCreate})
+		cacheIds[name] = -1
+	}
+
+	cacheIdsBackup := make(map[string]int64)
+	for k, v := range cacheIds {
+		cacheIdsBackup[k] = v
+	}
+
+	functionCache := false
+	for _, rule := range manifest.Rules {
+		key, r := trackedRule(ruleIds, rule)
+		tracked := r.Id > 0
+		for _, behavior := range rule.Behaviors {
+			if behavior.RulesEngineBehaviorString == nil {
+				continue
+			}
+			target := behavior.RulesEngineBehaviorString.Target
+			switch behavior.RulesEngineBehaviorString.Name {
+			case "set_cache_policy":
+				if id := cacheIdsBackup[target]; id == 0 {
+					logger.Debug("Cache Setting not found", zap.Any("Target", target))
+					return nil, msg.ErrorCacheNotFound
+				}
+				delete(cacheIds, target)
+			case "set_origin":
+				if id := originIds[target]; id == 0 {
+					logger.Debug("Origin not found", zap.Any("Target", target))
+					return nil, msg.ErrorOriginNotFound
+				}
+				delete(originKeys, target)
+			case "run_function":
+				if target != "" && len(declaredInstances) > 0 && !declaredInstances[target] {
+					logger.Debug("Edge Function instance not found", zap.Any("Target", target))
+					return nil, fmt.Errorf(msg.ErrorInstanceNotFound.Error(), target)
+				}
+				if !tracked && conf.Function.CacheId == 0 && !functionCache {
+					functionCache = true
+					plan = append(plan, contracts.ResourcePlan{
+						Resource: ResourceCache,
+						Name:     "function-policy",
+						Action:   PlanCreate,
+						Details:  msg.PlanFunctionPolicy,
+					})
+				}
+			}
+		}
+
+		if tracked {
+			plan = append(plan, updateAction(ResourceRule, rule.Name, fmt.Sprint(r.Id), remote.rules))
+			delete(ruleIds, key)
+			continue
+		}
+		plan = append(plan, contracts.ResourcePlan{
+			Resource: ResourceRule,
+			Name:     rule.Name,
+			Action:   PlanCreate,
+			Details:  fmt.Sprintf(msg.PlanPhase, rule.Phase),
+		})
+	}
+
+	for _, key := range sortedKeys(ruleIds) {
+		phase := "request"
+		if ruleIds[key].Phase != "" {
+			phase = ruleIds[key].Phase
+		}
+		plan = append(plan, man.pruneAction(contracts.ResourcePlan{
+			Resource: ResourceRule,
+			Name:     ruleIds[key].Name,
+			Id:       fmt.Sprint(ruleIds[key].Id),
+			Action:   PlanDelete,
+			Details:  fmt.Sprintf(msg.PlanPhase, phase),
+		}, protected))
+	}
+
+	for _, name := range sortedKeys(instanceIds) {
+		if declaredInstances[name] {
+			continue
+		}
+		plan = append(plan, man.pruneAction(deleteAction(ResourceFunctionInstance, name, instanceIds[name]), protected))
+	}
+
+	for _, name := range sortedKeys(functionIds) {
+		if declaredFunctions[name] {
+			continue
+		}
+		plan = append(plan, man.pruneAction(deleteAction(ResourceFunction, name, functionIds[name]), protected))
+	}
+
+	for _, name := range sortedKeys(originKeys) {
+		if strings.Contains(name, "_single") {
+			continue
+		}
+		plan = append(plan, man.pruneAction(deleteAction(ResourceOrigin, name, originIds[name]), protected))
+	}
+
+	for _, name := range sortedKeys(cacheIds) {
+		plan = append(plan, man.pruneAction(deleteAction(ResourceCache, name, cacheIds[name]), protected))
+	}
+
+	for _, name := range sortedKeys(groupIds) {
+		if declaredGroups[name] {
+			continue
+		}
+		plan = append(plan, man.pruneAction(deleteAction(ResourceDeviceGroup, name, groupIds[name]), protected))
+	}
+
+	domainIds := make(map[string]int64)
+	for _, domain := range conf.Domains {
+		domainIds[domain.Name] = domain.Id
+	}
+	declaredDomains := make(map[string]bool)
+	for _, domain := range manifest.Domains {
+		if domain.Name == DefaultDomain {
+			continue
+		}
+		declaredDomains[domain.Name] = true
+		if id := domainIds[domain.Name]; id > 0 {
+			plan = append(plan, updateAction(ResourceDomain, domain.Name, fmt.Sprint(id), remote.domains))
+			continue
+		}
+		plan = append(plan, contracts.ResourcePlan{Resource: ResourceDomain, Name: domain.Name, Action: PlanCreate})
+	}
+	for _, name := range sortedKeys(domainIds) {
+		if declaredDomains[name] {
+			continue
+		}
+		plan = append(plan, man.pruneAction(deleteAction(ResourceDomain, name, domainIds[name]), protected))
+	}
+
+	plan = append(plan, man.planRuleOrder(manifest, remote)...)
+
+	variablePlan, err := man.planVariables(ctx, f, conf, manifest, protected)
+	if err != nil {
+		return nil, err
+	}
+	plan = append(plan, variablePlan...)
+
+	return plan, nil
+}
+
+func readRemoteState(ctx context.Context, f *cmdutil.Factory, conf *contracts.AzionApplicationOptions) (*remoteState, error) {
+	remote := &remoteState{
+		origins:      make(map[string]bool),
+		caches:       make(map[string]bool),
+		rules:        make(map[string]bool),
+		deviceGroups: make(map[string]bool),
+		functions:    make(map[string]bool),
+		instances:    make(map[string]bool),
+		domains:      make(map[string]bool),
+		ruleOrder:    make(map[string][]sdk.RulesEngineResultResponse),
+	}
+
+	// nothing exists remotely before the edge application is created
+	if conf.Application.ID == 0 {
+		return remote, nil
+	}
+
+	client := apiEdgeApplications.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+	clientOrigin := apiOrigin.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+	opts := &contracts.ListOptions{
+		PageSize: 1000,
+		Page:     1,
+	}
+
+	origins, err := listOrigins(ctx, clientOrigin, conf.Application.ID)
+	if err != nil {
+		logger.Debug("Error while listing origins", zap.Error(err))
+		return nil, err
+	}
+	for _, origin := range origins {
+		remote.origins[origin.GetName()] = true
+	}
+
+	caches, err := client.ListCacheEdgeApp(ctx, conf.Application.ID)
+	if err != nil {
+		logger.Debug("Error while listing cache settings", zap.Error(err))
+		return nil, err
+	}
+	for _, cache := range caches {
+		remote.caches[cache.GetName()] = true
+	}
+
+	for _, phase := range apiEdgeApplications.RulesEnginePhases {
+		rules, err := ListRules(ctx, client, conf.Application.ID, phase)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range rules {
+			remote.rules[rule.GetName()] = true
+		}
+		remote.ruleOrder[phase] = rules
+	}
+
+	// only projects that declared device groups have any to compare
+	if len(conf.DeviceGroups) > 0 {
+		groups, err := client.DeviceGroupsList(ctx, opts, conf.Application.ID)
+		if err != nil {
+			logger.Debug("Error while listing device groups", zap.Error(err))
+			return nil, err
+		}
+		for _, group := range groups.GetResults() {
+			remote.deviceGroups[group.GetName()] = true
+		}
+	}
+
+	// the same goes for the functions of the manifest, which are looked up one by one since they belong to the account
+	if len(conf.Functions) > 0 {
+		clientFunction := apiEdgeFunction.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+		for _, function := range conf.Functions {
+			found, err := clientFunction.Get(ctx, function.Id)
+			if err != nil {
+				if errors.Is(err, utils.ErrorNotFound404) {
+					continue
+				}
+				logger.Debug("Error while reading an edge function", zap.Error(err))
+				return nil, err
+			}
+			remote.functions[found.GetName()] = true
+		}
+
+		instances, err := client.EdgeFuncInstancesList(ctx, opts, conf.Application.ID)
+		if err != nil {
+			logger.Debug("Error while listing edge function instances", zap.Error(err))
+			return nil, err
+		}
+		for _, instance := range instances.GetResults() {
+			remote.instances[instance.GetName()] = true
+		}
+	}
+
+	// domains belong to the account as well
+	if len(conf.Domains) > 0 {
+		clientDomain := apiDomain.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+		for _, domain := range conf.Domains {
+			found, err := clientDomain.Get(ctx, fmt.Sprint(domain.Id))
+			if err != nil {
+				if errors.Is(err, utils.ErrorNotFound404) {
+					continue
+				}
+				logger.Debug("Error while reading a domain", zap.Error(err))
+				return nil, err
+			}
+			remote.domains[found.GetName()] = true
+		}
+	}
+
+	return remote, nil
+}
+
+func updateAction(resource, name, id string, remote map[string]bool) contracts.ResourcePlan {
+	action := contracts.ResourcePlan{Resource: resource, Name: name, Id: id, Action: PlanUpdate}
+	if !remote[name] {
+		action.Details = msg.PlanNotFoundRemotely
+	}
+	return action
+}
+
+func deleteAction(resource, name string, id int64) contracts.ResourcePlan {
+	action := contracts.ResourcePlan{Resource: resource, Name: name, Action: PlanDelete, Details: msg.PlanNotReferenced}
+	// resources created during this same deploy have no ID yet
+	if id > 0 {
+		action.Id = fmt.Sprint(id)
+	}
+	return action
+}
+
+// pruneAction turns the deletion of a resource CreateResources would keep into a keep action
+func (man *ManifestInterpreter) pruneAction(action contracts.ResourcePlan, protected protection) contracts.ResourcePlan {
+	switch {
+	case protected.protected(action.Resource, action.Name):
+		action.Action = PlanKeep
+		action.Details = msg.PlanPreventDestroy
+	case !man.Prune:
+		action.Action = PlanKeep
+		action.Details = msg.PlanPruneDisabled
+	case man.ConfirmDelete == nil || !man.ConfirmDelete([]contracts.ResourcePlan{action}):
+		action.Action = PlanKeep
+		action.Details = msg.PlanNotConfirmed
+	}
+	return action
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
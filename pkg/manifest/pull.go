@@ -0,0 +1,115 @@
+package manifest
+
+import (
+	"strconv"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+)
+
+// Pull returns the remote definitions of the origins, cache settings and rules azion.json tracks but the manifest
+// doesn't declare, which PatchManifest adds to it so the next deploy keeps them instead of deleting them. The device
+// groups they name come along and are tracked as well. Resources the manifest already declares are left as they are;
+// Diff tells how they differ from their remote configuration. The origin and cache setting deploy manages itself are
+// never pulled.
+func Pull(
+	manifest *contracts.Manifest,
+	conf *contracts.AzionApplicationOptions,
+	remote *Remote) (*contracts.Manifest, []contracts.ResourceDrift) {
+	additions := &contracts.Manifest{}
+	pulled := []contracts.ResourceDrift{}
+	groups := make(map[string]bool)
+
+	declaredOrigins := make(map[string]bool)
+	for _, origin := range manifest.Origins {
+		declaredOrigins[origin.Name] = true
+	}
+	trackedOrigins := make(map[int64]bool)
+	for _, origin := range conf.Origin {
+		trackedOrigins[origin.OriginId] = true
+	}
+	for _, origin := range remote.Origins {
+		if !trackedOrigins[origin.ID] || declaredOrigins[origin.Origin.Name] || origin.Origin.Name == conf.Name+"_single" {
+			continue
+		}
+		additions.Origins = append(additions.Origins, origin.Origin)
+		pulled = append(pulled, pulledDrift(ResourceOrigin, origin.Origin.Name, origin.ID))
+	}
+
+	declaredCaches := make(map[string]bool)
+	for _, cache := range manifest.CacheSettings {
+		if cache.Name != nil {
+			declaredCaches[*cache.Name] = true
+		}
+	}
+	trackedCaches := make(map[int64]bool)
+	for _, cache := range conf.CacheSettings {
+		trackedCaches[cache.Id] = true
+	}
+	for _, cache := range remote.CacheSettings {
+		if !trackedCaches[cache.ID] || declaredCaches[*cache.Cache.Name] || cache.ID == conf.Function.CacheId {
+			continue
+		}
+		additions.CacheSettings = append(additions.CacheSettings, cache.Cache)
+		pulled = append(pulled, pulledDrift(ResourceCache, *cache.Cache.Name, cache.ID))
+		for _, name := range cache.Cache.DeviceGroups {
+			groups[name] = true
+		}
+	}
+
+	declaredRules := make(map[string]bool)
+	for _, rule := range manifest.Rules {
+		declaredRules[RuleKey(rule.Phase, rule.Name)] = true
+	}
+	trackedRules := make(map[int64]bool)
+	for _, rule := range conf.RulesEngine.Rules {
+		trackedRules[rule.Id] = true
+	}
+	for _, rule := range remote.Rules {
+		if !trackedRules[rule.ID] || declaredRules[RuleKey(rule.Rule.Phase, rule.Rule.Name)] {
+			continue
+		}
+		additions.Rules = append(additions.Rules, rule.Rule)
+		pulled = append(pulled, pulledDrift(ResourceRule, rule.Rule.Name, rule.ID))
+		for _, criteria := range rule.Rule.Criteria {
+			for _, criterion := range criteria {
+				if criterion.Variable == deviceGroupVariable && criterion.InputValue != nil {
+					groups[*criterion.InputValue] = true
+				}
+			}
+		}
+	}
+
+	declaredGroups := make(map[string]bool)
+	for _, group := range manifest.DeviceGroups {
+		declaredGroups[group.Name] = true
+	}
+	trackedGroups := make(map[int64]bool)
+	for _, group := range conf.DeviceGroups {
+		trackedGroups[group.Id] = true
+	}
+	for _, group := range remote.DeviceGroups {
+		if !groups[group.Group.Name] || declaredGroups[group.Group.Name] {
+			continue
+		}
+		additions.DeviceGroups = append(additions.DeviceGroups, group.Group)
+		if !trackedGroups[group.ID] {
+			conf.DeviceGroups = append(conf.DeviceGroups, contracts.AzionJsonDataDeviceGroup{
+				Id:   group.ID,
+				Name: group.Group.Name,
+			})
+		}
+		pulled = append(pulled, pulledDrift(ResourceDeviceGroup, group.Group.Name, group.ID))
+	}
+
+	return additions, pulled
+}
+
+func pulledDrift(resource, name string, id int64) contracts.ResourceDrift {
+	return contracts.ResourceDrift{
+		Resource: resource,
+		Name:     name,
+		Id:       strconv.FormatInt(id, 10),
+		Details:  msg.DriftPulled,
+	}
+}
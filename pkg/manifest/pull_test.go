@@ -0,0 +1,76 @@
+package manifest
+
+import (
+	"testing"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	sdk "github.com/aziontech/azionapi-go-sdk/edgeapplications"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPull(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	manifest := &contracts.Manifest{
+		Origins: []contracts.Origin{{Name: "api", OriginType: "single_origin"}},
+	}
+	conf := &contracts.AzionApplicationOptions{
+		Name: "shop",
+		Origin: []contracts.AzionJsonDataOrigin{
+			{OriginId: 42, Name: "api"}, {OriginId: 43, Name: "console-origin"}, {OriginId: 45, Name: "shop_single"},
+		},
+		CacheSettings: []contracts.AzionJsonDataCacheSettings{{Id: 7, Name: "mobile cache"}, {Id: 8, Name: "function policy"}},
+		Function:      contracts.AzionJsonDataFunction{CacheId: 8},
+		RulesEngine: contracts.AzionJsonDataRulesEngine{Rules: []contracts.AzionJsonDataRules{
+			{Id: 3, Name: "mobile", Phase: "request"},
+		}},
+	}
+	remote := &Remote{
+		Origins: []RemoteOrigin{
+			{ID: 42, Origin: contracts.Origin{Name: "api", OriginType: "single_origin", HostHeader: "remote"}},
+			{ID: 43, Origin: contracts.Origin{Name: "console-origin", OriginType: "single_origin"}},
+			{ID: 44, Origin: contracts.Origin{Name: "untracked", OriginType: "single_origin"}},
+			{ID: 45, Origin: contracts.Origin{Name: "shop_single", OriginType: "single_origin"}},
+		},
+		CacheSettings: []RemoteCache{
+			{ID: 7, Cache: contracts.CacheSetting{Name: str("mobile cache"), DeviceGroups: []string{"tablet"}}},
+			{ID: 8, Cache: contracts.CacheSetting{Name: str("function policy")}},
+		},
+		Rules: []RemoteRule{
+			{ID: 3, Rule: contracts.RuleEngine{Name: "mobile", Phase: "request", Criteria: [][]sdk.RulesEngineCriteria{{
+				{Variable: deviceGroupVariable, Operator: "is_equal", Conditional: "if", InputValue: str("mobile")},
+			}}}},
+		},
+		DeviceGroups: []RemoteDeviceGroup{
+			{ID: 12, Group: contracts.DeviceGroup{Name: "mobile", UserAgent: "iPhone"}},
+			{ID: 13, Group: contracts.DeviceGroup{Name: "tablet", UserAgent: "iPad"}},
+			{ID: 14, Group: contracts.DeviceGroup{Name: "unused", UserAgent: "Nokia"}},
+		},
+	}
+
+	// the origin and cache setting deploy manages for the function are left out
+	additions, pulled := Pull(manifest, conf, remote)
+	require.Equal(t, []contracts.ResourceDrift{
+		{Resource: ResourceOrigin, Name: "console-origin", Id: "43", Details: msg.DriftPulled},
+		{Resource: ResourceCache, Name: "mobile cache", Id: "7", Details: msg.DriftPulled},
+		{Resource: ResourceRule, Name: "mobile", Id: "3", Details: msg.DriftPulled},
+		{Resource: ResourceDeviceGroup, Name: "mobile", Id: "12", Details: msg.DriftPulled},
+		{Resource: ResourceDeviceGroup, Name: "tablet", Id: "13", Details: msg.DriftPulled},
+	}, pulled)
+
+	// the declared origin keeps its local definition and the manifest is left for PatchManifest to change
+	require.Len(t, manifest.Origins, 1)
+	require.Equal(t, []contracts.Origin{remote.Origins[1].Origin}, additions.Origins)
+	require.Len(t, additions.CacheSettings, 1)
+	require.Len(t, additions.Rules, 1)
+	require.Len(t, additions.DeviceGroups, 2)
+	require.Equal(t, []contracts.AzionJsonDataDeviceGroup{{Id: 12, Name: "mobile"}, {Id: 13, Name: "tablet"}}, conf.DeviceGroups)
+
+	manifest.Origins = append(manifest.Origins, additions.Origins...)
+	manifest.CacheSettings = additions.CacheSettings
+	manifest.Rules = additions.Rules
+	manifest.DeviceGroups = additions.DeviceGroups
+	_, pulled = Pull(manifest, conf, remote)
+	require.Empty(t, pulled)
+}
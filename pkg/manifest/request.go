@@ -161,7 +161,7 @@ func makeRuleRequestUpdate(rule contracts.RuleEngine, conf *contracts.AzionAppli
 			criteria.Conditional = itemCriteria.Conditional
 			criteria.Variable = itemCriteria.Variable
 			criteria.Operator = itemCriteria.Operator
-			criteria.InputValue = itemCriteria.InputValue
+			criteria.InputValue = deviceGroupInput(itemCriteria)
 
 			criterias = append(criterias, criteria)
 		}
@@ -199,7 +199,10 @@ func makeRuleRequestUpdate(rule contracts.RuleEngine, conf *contracts.AzionAppli
 						return nil, msg.ErrorCacheNotFound
 					}
 				} else if v.RulesEngineBehaviorString.Name == "run_function" {
-					str := strconv.FormatInt(conf.Function.InstanceID, 10)
+					str, err := functionInstance(v.RulesEngineBehaviorString.Target, conf)
+					if err != nil {
+						return nil, err
+					}
 					behaviorString.SetTarget(str)
 				} else if v.RulesEngineBehaviorString.Name == "set_origin" {
 					if id := OriginIds[v.RulesEngineBehaviorString.Target]; id > 0 {
@@ -243,7 +246,7 @@ func makeRuleRequestCreate(rule contracts.RuleEngine, conf *contracts.AzionAppli
 			criteria.Conditional = itemCriteria.Conditional
 			criteria.Variable = itemCriteria.Variable
 			criteria.Operator = itemCriteria.Operator
-			criteria.InputValue = itemCriteria.InputValue
+			criteria.InputValue = deviceGroupInput(itemCriteria)
 
 			criterias = append(criterias, criteria)
 		}
@@ -292,7 +295,10 @@ func makeRuleRequestCreate(rule contracts.RuleEngine, conf *contracts.AzionAppli
 					behaviors = append(behaviors, sdk.RulesEngineBehaviorEntry{
 						RulesEngineBehaviorString: &beh,
 					})
-					str := strconv.FormatInt(conf.Function.InstanceID, 10)
+					str, err := functionInstance(v.RulesEngineBehaviorString.Target, conf)
+					if err != nil {
+						return nil, err
+					}
 					behaviorString.SetTarget(str)
 				} else if v.RulesEngineBehaviorString.Name == "set_origin" {
 					if id := OriginIds[v.RulesEngineBehaviorString.Target]; id > 0 {
@@ -401,3 +407,37 @@ func doCacheForRule(ctx context.Context, client *apiEdgeApplications.Client, con
 
 	return cache.GetId(), nil
 }
+
+// deviceGroupVariable is the criteria variable matching a device group, whose input may name a group of the manifest
+const deviceGroupVariable = "${device_group}"
+
+// resolveDeviceGroups adds the IDs of the device groups a cache setting names to the ones it sets directly
+func resolveDeviceGroups(cache *contracts.CacheSetting) error {
+	if len(cache.DeviceGroups) == 0 {
+		return nil
+	}
+	ids := append([]int32{}, cache.DeviceGroup...)
+	for _, name := range cache.DeviceGroups {
+		id, ok := DeviceGroupIds[name]
+		if !ok {
+			logger.Debug("Device group not found", zap.Any("Name", name))
+			return fmt.Errorf(msg.ErrorDeviceGroupNotFound.Error(), name)
+		}
+		ids = append(ids, int32(id))
+	}
+	cache.DeviceGroup = ids
+	return nil
+}
+
+// deviceGroupInput replaces the name of a device group of the manifest by its ID. Other inputs, IDs included, are
+// sent as they are.
+func deviceGroupInput(criteria sdk.RulesEngineCriteria) *string {
+	if criteria.Variable != deviceGroupVariable || criteria.InputValue == nil {
+		return criteria.InputValue
+	}
+	if id, ok := DeviceGroupIds[*criteria.InputValue]; ok {
+		str := strconv.FormatInt(id, 10)
+		return &str
+	}
+	return criteria.InputValue
+}
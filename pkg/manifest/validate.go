@@ -0,0 +1,614 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	apiEdgeApplications "github.com/aziontech/azion-cli/pkg/api/edge_applications"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	sdk "github.com/aziontech/azionapi-go-sdk/edgeapplications"
+)
+
+// ValidationError is a problem found in a manifest, located by its JSON path and its position in the file
+type ValidationError struct {
+	Path    string `json:"path" yaml:"path" toml:"path"`
+	Line    int    `json:"line" yaml:"line" toml:"line"`
+	Column  int    `json:"column" yaml:"column" toml:"column"`
+	Message string `json:"message" yaml:"message" toml:"message"`
+}
+
+func (e ValidationError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf(msg.ValidationProblemPath, e.Path, e.Message)
+	}
+	return fmt.Sprintf(msg.ValidationProblem, e.Line, e.Column, e.Path, e.Message)
+}
+
+// ValidationErrors are every problem found in a manifest, sorted by position
+type ValidationErrors []ValidationError
+
+func (errs ValidationErrors) Error() string {
+	lines := make([]string, len(errs))
+	for i, err := range errs {
+		lines[i] = err.Error()
+	}
+	return fmt.Sprintf(msg.ErrorInvalidManifest.Error(), len(errs), strings.Join(lines, "\n"))
+}
+
+func (errs ValidationErrors) Is(target error) bool {
+	return target == msg.ErrorInvalidManifest
+}
+
+var (
+	// enums holds the values accepted by the fields the API validates against a fixed list, keyed by section and field
+	enums = map[string][]string{
+		"origin.origin_type":             {"single_origin", "object_storage", "load_balancer", "live_ingest"},
+		"cache.browser_cache_settings":   {"honor", "override", "ignore"},
+		"cache.cdn_cache_settings":       {"honor", "override"},
+		"cache.cache_by_query_string":    {"ignore", "whitelist", "blacklist", "all"},
+		"cache.cache_by_cookies":         {"ignore", "whitelist", "blacklist", "all"},
+		"cache.adaptive_delivery_action": {"ignore", "whitelist"},
+		"rules.phase":                    apiEdgeApplications.RulesEnginePhases,
+		"criteria.conditional":           {"if", "and", "or"},
+	}
+
+	behaviorEntryType  = reflect.TypeOf(sdk.RulesEngineBehaviorEntry{})
+	behaviorTargetType = reflect.TypeOf(sdk.RulesEngineBehaviorObjectTarget{})
+)
+
+type nodeKind int
+
+const (
+	kindNull nodeKind = iota
+	kindObject
+	kindArray
+	kindString
+	kindNumber
+	kindBool
+)
+
+func (k nodeKind) String() string {
+	return [...]string{"null", "object", "array", "string", "number", "boolean"}[k]
+}
+
+// node is a JSON value along with where it starts in the file
+type node struct {
+	kind       nodeKind
+	offset     int64
+	str        string
+	boolean    bool
+	keys       []string
+	fields     map[string]*node
+	keyOffsets map[string]int64
+	items      []*node
+}
+
+type validator struct {
+	raw  []byte
+	errs ValidationErrors
+	// source is the manifest raw was replaced from, when variables were, and edits locate raw in it
+	source []byte
+	edits  edits
+	// declared holds the names of the origins, cache settings, device groups, functions and function instances found so
+	// far, keyed by section
+	declared map[string]map[string]bool
+}
+
+// Validate checks a manifest before any resource is created from it: unknown keys, values of the wrong type or outside
+// the ones the API accepts, duplicate names and rules naming cache settings or origins the manifest doesn't declare.
+// Partial manifests, such as the overrides of an environment, may name entries of the manifest they complete, so their
+// references are not checked.
+func Validate(raw []byte, partial bool) error {
+	return newValidator(raw).validate(partial)
+}
+
+func newValidator(raw []byte) *validator {
+	return &validator{
+		raw:      raw,
+		declared: map[string]map[string]bool{"origin": {}, "cache": {}, "device_groups": {}, "functions": {}, "instances": {}},
+	}
+}
+
+func (v *validator) validate(partial bool) error {
+	root, err := v.parse()
+	if err != nil {
+		return err
+	}
+
+	v.checkType(root, reflect.TypeOf(contracts.Manifest{}), "$")
+	if len(v.errs) == 0 {
+		v.checkEnums(root)
+		v.checkNames(root, partial)
+	}
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		if v.errs[i].Line != v.errs[j].Line {
+			return v.errs[i].Line < v.errs[j].Line
+		}
+		return v.errs[i].Column < v.errs[j].Column
+	})
+	return v.errs
+}
+
+// ValidateManifest reads and validates the manifest in path along with the overrides of the environment being deployed.
+// Rules of the manifest may name origins and cache settings only the overrides declare.
+func (man *ManifestInterpreter) ValidateManifest(path string) error {
+	source, err := man.FileReader(path)
+	if err != nil {
+		return err
+	}
+	v, err := man.newSourceValidator(path, source)
+	if err != nil {
+		return err
+	}
+
+	if man.OverridesPath != "" {
+		sourceOverrides, err := man.FileReader(man.OverridesPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf(msg.ErrorReadOverrides.Error(), man.OverridesPath, err)
+		}
+		if err == nil {
+			overrides, err := man.newSourceValidator(man.OverridesPath, sourceOverrides)
+			if err != nil {
+				return err
+			}
+			if err := overrides.validate(true); err != nil {
+				return relocate(err, man.OverridesPath, sourceOverrides)
+			}
+			v.declared = overrides.declared
+		}
+	}
+
+	return relocate(v.validate(false), path, source)
+}
+
+// newSourceValidator validates the manifest source decodes to, locating the problems of a JSON manifest in its source
+// even when variables were replaced in it
+func (man *ManifestInterpreter) newSourceValidator(path string, source []byte) (*validator, error) {
+	raw, changes, err := man.decode(path, source)
+	if err != nil {
+		return nil, err
+	}
+	v := newValidator(raw)
+	if len(changes) > 0 {
+		v.source, v.edits = source, changes
+	}
+	return v, nil
+}
+
+func (v *validator) add(offset int64, path, format string, args ...any) {
+	line, column := v.position(offset)
+	v.errs = append(v.errs, ValidationError{
+		Path:    path,
+		Line:    line,
+		Column:  column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// position turns a byte offset into a 1-based line and column
+func (v *validator) position(offset int64) (int, int) {
+	raw := v.raw
+	if v.source != nil {
+		raw, offset = v.source, v.edits.source(offset)
+	}
+	if offset > int64(len(raw)) {
+		offset = int64(len(raw))
+	}
+	before := raw[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+func (v *validator) parse() (*node, error) {
+	dec := json.NewDecoder(bytes.NewReader(v.raw))
+	dec.UseNumber()
+	root, err := v.parseValue(dec)
+	if err == nil {
+		if _, err = dec.Token(); err == io.EOF {
+			return root, nil
+		}
+		if err == nil {
+			err = errors.New(msg.ValidationTrailingData)
+		}
+	}
+
+	offset := dec.InputOffset()
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = syntaxErr.Offset
+	}
+	v.add(offset, "$", "%s", err.Error())
+	return nil, v.errs
+}
+
+// start is where the next value begins, past the separators the decoder has not consumed yet
+func (v *validator) start(dec *json.Decoder) int64 {
+	offset := dec.InputOffset()
+	for offset < int64(len(v.raw)) && strings.IndexByte(" \t\r\n:,", v.raw[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+func (v *validator) parseValue(dec *json.Decoder) (*node, error) {
+	offset := v.start(dec)
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	n := &node{offset: offset}
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '{' {
+			n.kind = kindObject
+			n.fields = make(map[string]*node)
+			n.keyOffsets = make(map[string]int64)
+			for dec.More() {
+				keyOffset := v.start(dec)
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := v.parseValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				name := key.(string)
+				if _, ok := n.fields[name]; !ok {
+					n.keys = append(n.keys, name)
+				}
+				n.fields[name] = value
+				n.keyOffsets[name] = keyOffset
+			}
+		} else {
+			n.kind = kindArray
+			for dec.More() {
+				item, err := v.parseValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.items = append(n.items, item)
+			}
+		}
+		// closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		n.kind = kindString
+		n.str = t
+	case json.Number:
+		n.kind = kindNumber
+	case bool:
+		n.kind = kindBool
+		n.boolean = t
+	default:
+		n.kind = kindNull
+	}
+	return n, nil
+}
+
+// fieldsOf maps the JSON keys of a struct to the types of their fields
+func fieldsOf(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// checkType compares a value with the Go type it is decoded into
+func (v *validator) checkType(n *node, t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if n.kind == kindNull {
+		return
+	}
+
+	if t == behaviorEntryType {
+		v.checkBehavior(n, path)
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if !v.expect(n, kindObject, path) {
+			return
+		}
+		fields := fieldsOf(t)
+		for _, key := range n.keys {
+			fieldType, ok := fields[key]
+			if !ok {
+				v.add(n.keyOffsets[key], path+"."+key, msg.ValidationUnknownKey, key)
+				continue
+			}
+			v.checkType(n.fields[key], fieldType, path+"."+key)
+		}
+	case reflect.Slice:
+		if !v.expect(n, kindArray, path) {
+			return
+		}
+		for i, item := range n.items {
+			v.checkType(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.String:
+		v.expect(n, kindString, path)
+	case reflect.Bool:
+		v.expect(n, kindBool, path)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		v.expect(n, kindNumber, path)
+	}
+}
+
+// checkBehavior accepts both shapes of a behavior: a string target, or an object one for capture_match_groups
+func (v *validator) checkBehavior(n *node, path string) {
+	if !v.expect(n, kindObject, path) {
+		return
+	}
+	for _, key := range n.keys {
+		value := n.fields[key]
+		switch key {
+		case "name":
+			v.expect(value, kindString, path+".name")
+		case "target":
+			if value.kind == kindObject {
+				v.checkType(value, behaviorTargetType, path+".target")
+			} else if value.kind != kindNull {
+				v.expect(value, kindString, path+".target")
+			}
+		default:
+			v.add(n.keyOffsets[key], path+"."+key, msg.ValidationUnknownKey, key)
+		}
+	}
+}
+
+func (v *validator) expect(n *node, kind nodeKind, path string) bool {
+	if n.kind != kind {
+		v.add(n.offset, path, msg.ValidationWrongType, kind, n.kind)
+		return false
+	}
+	return true
+}
+
+// entries returns the items of a top-level section of the manifest
+func entries(root *node, section string) []*node {
+	if n, ok := root.fields[section]; ok {
+		return n.items
+	}
+	return nil
+}
+
+func (v *validator) checkEnum(n *node, enum, path string) {
+	value, ok := n.fields[strings.SplitN(enum, ".", 2)[1]]
+	if !ok || value.kind != kindString {
+		return
+	}
+	for _, allowed := range enums[enum] {
+		if value.str == allowed {
+			return
+		}
+	}
+	v.add(value.offset, path, msg.ValidationInvalidValue, value.str, strings.Join(enums[enum], ", "))
+}
+
+func (v *validator) checkEnums(root *node) {
+	for section, items := range map[string][]*node{
+		"origin": entries(root, "origin"),
+		"cache":  entries(root, "cache"),
+		"rules":  entries(root, "rules"),
+	} {
+		for i, item := range items {
+			for enum := range enums {
+				if strings.HasPrefix(enum, section+".") {
+					field := strings.TrimPrefix(enum, section+".")
+					v.checkEnum(item, enum, fmt.Sprintf("$.%s[%d].%s", section, i, field))
+				}
+			}
+		}
+	}
+
+	for i, rule := range entries(root, "rules") {
+		criteria, ok := rule.fields["criteria"]
+		if !ok {
+			continue
+		}
+		for j, group := range criteria.items {
+			for k, criterion := range group.items {
+				v.checkEnum(criterion, "criteria.conditional", fmt.Sprintf("$.rules[%d].criteria[%d][%d].conditional", i, j, k))
+			}
+		}
+	}
+}
+
+// names collects the names of the entries of a section, reporting the ones declared twice. Sections whose entries
+// must have a name pass the message reporting the ones without it.
+func (v *validator) names(root *node, section, missing string) {
+	names := make(map[string]bool)
+	for i, item := range entries(root, section) {
+		path := fmt.Sprintf("$.%s[%d]", section, i)
+		name, ok := item.fields["name"]
+		if !ok || name.kind != kindString || name.str == "" {
+			if missing != "" {
+				v.add(item.offset, path, missing)
+			}
+			continue
+		}
+		if names[name.str] {
+			v.add(name.offset, path+".name", msg.ValidationDuplicateName, section, name.str)
+		}
+		names[name.str] = true
+		if declared, ok := v.declared[section]; ok {
+			declared[name.str] = true
+		}
+	}
+}
+
+func (v *validator) checkNames(root *node, partial bool) {
+	v.names(root, "origin", "")
+	v.names(root, "cache", msg.ValidationMissingName)
+	v.names(root, "rules", "")
+	v.names(root, "device_groups", msg.ValidationMissingGroupName)
+	v.names(root, "functions", msg.ValidationMissingFuncName)
+	v.instanceNames(root)
+	v.names(root, "domains", msg.ValidationMissingDomainName)
+	v.checkCnames(root)
+	v.variableKeys(root)
+
+	if partial {
+		return
+	}
+
+	references := map[string]map[string]bool{
+		"set_cache_policy": v.declared["cache"],
+		"set_origin":       v.declared["origin"],
+	}
+	for i, rule := range entries(root, "rules") {
+		behaviors, ok := rule.fields["behaviors"]
+		if !ok {
+			continue
+		}
+		for j, behavior := range behaviors.items {
+			name, target := behavior.fields["name"], behavior.fields["target"]
+			if name == nil || target == nil || target.kind != kindString {
+				continue
+			}
+			declared, ok := references[name.str]
+			if name.str == "run_function" {
+				// rules run the project's function unless they name an instance of the functions section
+				declared, ok = v.declared["instances"], len(v.declared["instances"]) > 0 && target.str != ""
+			}
+			if !ok || declared[target.str] {
+				continue
+			}
+			v.add(target.offset, fmt.Sprintf("$.rules[%d].behaviors[%d].target", i, j),
+				msg.ValidationDanglingReference, name.str, target.str)
+		}
+	}
+
+	v.checkDeviceGroups(root)
+}
+
+// instanceNames collects the names of the function instances, which are unique across the functions, and reports the
+// functions without the path of their code. Functions without instances have one named after them.
+func (v *validator) instanceNames(root *node) {
+	names := v.declared["instances"]
+	for i, function := range entries(root, "functions") {
+		path := fmt.Sprintf("$.functions[%d]", i)
+		if code, ok := function.fields["path"]; !ok || code.kind != kindString || code.str == "" {
+			v.add(function.offset, path, msg.ValidationMissingFuncPath)
+		}
+
+		instances, ok := function.fields["instances"]
+		if !ok || len(instances.items) == 0 {
+			if name, ok := function.fields["name"]; ok && name.kind == kindString && name.str != "" {
+				if names[name.str] {
+					v.add(name.offset, path+".name", msg.ValidationDuplicateName, "instances", name.str)
+				}
+				names[name.str] = true
+			}
+			continue
+		}
+		for j, instance := range instances.items {
+			instancePath := fmt.Sprintf("%s.instances[%d]", path, j)
+			name, ok := instance.fields["name"]
+			if !ok || name.kind != kindString || name.str == "" {
+				v.add(instance.offset, instancePath, msg.ValidationMissingInstName)
+				continue
+			}
+			if names[name.str] {
+				v.add(name.offset, instancePath+".name", msg.ValidationDuplicateName, "instances", name.str)
+			}
+			names[name.str] = true
+		}
+	}
+}
+
+// variableKeys reports the variables without a key and the keys declared twice
+func (v *validator) variableKeys(root *node) {
+	keys := make(map[string]bool)
+	for i, variable := range entries(root, "variables") {
+		path := fmt.Sprintf("$.variables[%d]", i)
+		key, ok := variable.fields["key"]
+		if !ok || key.kind != kindString || key.str == "" {
+			v.add(variable.offset, path, msg.ValidationMissingVarKey)
+			continue
+		}
+		if keys[key.str] {
+			v.add(key.offset, path+".key", msg.ValidationDuplicateName, "variables", key.str)
+		}
+		keys[key.str] = true
+	}
+}
+
+// checkCnames reports the domains served only through their CNAMEs that have none
+func (v *validator) checkCnames(root *node) {
+	for i, domain := range entries(root, "domains") {
+		only, ok := domain.fields["cname_access_only"]
+		if !ok || !only.boolean {
+			continue
+		}
+		if cnames, ok := domain.fields["cnames"]; ok && len(cnames.items) > 0 {
+			continue
+		}
+		v.add(only.offset, fmt.Sprintf("$.domains[%d].cname_access_only", i), msg.ValidationMissingCnames)
+	}
+}
+
+// checkDeviceGroups reports the device groups named by cache settings and ${device_group} criteria that the manifest
+// doesn't declare. Criteria may match a group by its ID as well.
+func (v *validator) checkDeviceGroups(root *node) {
+	declared := v.declared["device_groups"]
+	for i, cache := range entries(root, "cache") {
+		groups, ok := cache.fields["device_groups"]
+		if !ok {
+			continue
+		}
+		for j, group := range groups.items {
+			if group.kind == kindString && !declared[group.str] {
+				v.add(group.offset, fmt.Sprintf("$.cache[%d].device_groups[%d]", i, j),
+					msg.ValidationDanglingReference, "device_groups", group.str)
+			}
+		}
+	}
+
+	for i, rule := range entries(root, "rules") {
+		criteria, ok := rule.fields["criteria"]
+		if !ok {
+			continue
+		}
+		for j, group := range criteria.items {
+			for k, criterion := range group.items {
+				variable, input := criterion.fields["variable"], criterion.fields["input_value"]
+				if variable == nil || variable.str != deviceGroupVariable || input == nil || input.kind != kindString {
+					continue
+				}
+				if _, err := strconv.ParseInt(input.str, 10, 64); err == nil || declared[input.str] {
+					continue
+				}
+				v.add(input.offset, fmt.Sprintf("$.rules[%d].criteria[%d][%d].input_value", i, j, k),
+					msg.ValidationDanglingReference, deviceGroupVariable, input.str)
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package manifest
+
+import (
+	"os"
+	"testing"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("fixtures are valid", func(t *testing.T) {
+		raw, err := os.ReadFile("fixtures/manifest.json")
+		require.NoError(t, err)
+		require.NoError(t, Validate(raw, false))
+
+		raw, err = os.ReadFile("fixtures/overrides.json")
+		require.NoError(t, err)
+		require.NoError(t, Validate(raw, true))
+	})
+
+	tests := []struct {
+		name     string
+		manifest string
+		partial  bool
+		want     ValidationErrors
+	}{
+		{
+			name:     "syntax error",
+			manifest: "{\n  \"cache\": [\n    {\"name\": \"a\",}\n  ]\n}",
+			want: ValidationErrors{
+				{Path: "$", Line: 3, Column: 18, Message: "invalid character ',' looking for beginning of value"},
+			},
+		},
+		{
+			name:     "unknown key",
+			manifest: "{\n  \"origins\": []\n}",
+			want: ValidationErrors{
+				{Path: "$.origins", Line: 2, Column: 3, Message: "unknown key 'origins'"},
+			},
+		},
+		{
+			name:     "device groups",
+			manifest: `{"device_groups": [{"name": "mobile", "user_agent": "Android"}, {"user_agent": "iPad"}], "cache": [{"name": "a", "device_groups": ["mobile", "tablet"]}]}`,
+			want: ValidationErrors{
+				{Path: "$.device_groups[1]", Line: 1, Column: 65, Message: "device groups must have a name"},
+				{Path: "$.cache[0].device_groups[1]", Line: 1, Column: 142, Message: "device_groups names 'tablet', which is not declared in the manifest"},
+			},
+		},
+		{
+			name:     "device group criteria",
+			manifest: `{"rules": [{"name": "r", "criteria": [[{"variable": "${device_group}", "input_value": "12"}, {"variable": "${device_group}", "input_value": "tablet"}]]}]}`,
+			want: ValidationErrors{
+				{Path: "$.rules[0].criteria[0][1].input_value", Line: 1, Column: 141, Message: "${device_group} names 'tablet', which is not declared in the manifest"},
+			},
+		},
+		{
+			name:     "lifecycle",
+			manifest: `{"origin": [{"name": "a", "lifecycle": {"prevent_destroy": "yes"}}]}`,
+			want: ValidationErrors{
+				{Path: "$.origin[0].lifecycle.prevent_destroy", Line: 1, Column: 60, Message: "expected boolean, found string"},
+			},
+		},
+		{
+			name:     "wrong type",
+			manifest: `{"cache": [{"name": "a", "enable_caching_for_post": "yes"}]}`,
+			want: ValidationErrors{
+				{Path: "$.cache[0].enable_caching_for_post", Line: 1, Column: 53, Message: "expected boolean, found string"},
+			},
+		},
+		{
+			name:     "invalid enum",
+			manifest: `{"rules": [{"name": "r", "phase": "deliver"}]}`,
+			want: ValidationErrors{
+				{Path: "$.rules[0].phase", Line: 1, Column: 35, Message: "invalid value 'deliver', expected one of: request, response"},
+			},
+		},
+		{
+			name:     "duplicate name",
+			manifest: `{"cache": [{"name": "a"}, {"name": "a"}]}`,
+			want: ValidationErrors{
+				{Path: "$.cache[1].name", Line: 1, Column: 36, Message: "cache named 'a' is declared more than once"},
+			},
+		},
+		{
+			name:     "dangling reference",
+			manifest: `{"rules": [{"name": "r", "behaviors": [{"name": "set_origin", "target": "api"}]}]}`,
+			want: ValidationErrors{
+				{Path: "$.rules[0].behaviors[0].target", Line: 1, Column: 73, Message: "set_origin names 'api', which is not declared in the manifest"},
+			},
+		},
+		{
+			name:     "partial manifests may reference other entries",
+			manifest: `{"rules": [{"name": "r", "behaviors": [{"name": "set_cache_policy", "target": "c"}]}]}`,
+			partial:  true,
+		},
+		{
+			name:     "functions",
+			manifest: `{"functions": [{"name": "auth", "path": "a.js", "instances": [{"name": "api"}, {}]}, {"name": "api"}], "rules": [{"name": "r", "behaviors": [{"name": "run_function", "target": "web"}, {"name": "run_function", "target": ""}]}]}`,
+			want: ValidationErrors{
+				{Path: "$.functions[0].instances[1]", Line: 1, Column: 80, Message: "function instances must have a name"},
+				{Path: "$.functions[1]", Line: 1, Column: 86, Message: "functions must have the path of their code"},
+				{Path: "$.functions[1].name", Line: 1, Column: 95, Message: "instances named 'api' is declared more than once"},
+				{Path: "$.rules[0].behaviors[0].target", Line: 1, Column: 177, Message: "run_function names 'web', which is not declared in the manifest"},
+			},
+		},
+		{
+			name:     "run_function without a functions section",
+			manifest: `{"rules": [{"name": "r", "behaviors": [{"name": "run_function", "target": "web"}]}]}`,
+		},
+		{
+			name:     "domains",
+			manifest: `{"domains": [{"name": "site", "cname_access_only": true}, {"cnames": ["www.example.com"]}, {"name": "api", "cname_access_only": true, "cnames": ["api.example.com"]}]}`,
+			want: ValidationErrors{
+				{Path: "$.domains[0].cname_access_only", Line: 1, Column: 52, Message: "domains with cname_access_only must have at least one CNAME"},
+				{Path: "$.domains[1]", Line: 1, Column: 59, Message: "domains must have a name"},
+			},
+		},
+		{
+			name:     "variables",
+			manifest: `{"variables": [{"key": "API_URL"}, {"secret": true}, {"key": "API_URL"}]}`,
+			want: ValidationErrors{
+				{Path: "$.variables[1]", Line: 1, Column: 36, Message: "variables must have a key"},
+				{Path: "$.variables[2].key", Line: 1, Column: 62, Message: "variables named 'API_URL' is declared more than once"},
+			},
+		},
+		{
+			name:     "variable with a value",
+			manifest: `{"variables": [{"key": "TOKEN", "secret": true, "value": "t"}]}`,
+			want: ValidationErrors{
+				{Path: "$.variables[0].value", Line: 1, Column: 49, Message: "unknown key 'value'"},
+			},
+		},
+		{
+			name:     "behavior with an object target",
+			manifest: `{"rules": [{"name": "r", "behaviors": [{"name": "capture_match_groups", "target": {"regex": "(.*)", "subject": "${uri}", "captured_array": "c"}}]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate([]byte(tt.manifest), tt.partial)
+			if tt.want == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, msg.ErrorInvalidManifest)
+			require.Equal(t, tt.want, err)
+		})
+	}
+
+	t.Run("overrides declare entries referenced by the manifest", func(t *testing.T) {
+		files := map[string]string{
+			"manifest.json":  `{"rules": [{"name": "r", "behaviors": [{"name": "set_origin", "target": "api"}]}]}`,
+			"overrides.json": `{"origin": [{"name": "api", "origin_type": "single_origin"}]}`,
+		}
+		man := NewManifestInterpreter()
+		man.FileReader = func(path string) ([]byte, error) {
+			if content, ok := files[path]; ok {
+				return []byte(content), nil
+			}
+			return nil, os.ErrNotExist
+		}
+		require.Error(t, man.ValidateManifest("manifest.json"))
+
+		man.OverridesPath = "overrides.json"
+		require.NoError(t, man.ValidateManifest("manifest.json"))
+
+		man.OverridesPath = "missing.json"
+		require.Error(t, man.ValidateManifest("manifest.json"))
+	})
+}
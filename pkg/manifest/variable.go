@@ -0,0 +1,199 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	msg "github.com/aziontech/azion-cli/messages/manifest"
+	apiVariables "github.com/aziontech/azion-cli/pkg/api/variables"
+	"github.com/aziontech/azion-cli/pkg/cmdutil"
+	"github.com/aziontech/azion-cli/pkg/contracts"
+	"github.com/aziontech/azion-cli/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// doVariables converges the environment variables of the manifest: missing ones are created, and the ones whose value
+// or secrecy changed are updated. Variables of the account the manifest doesn't declare are left alone, unless
+// azion.json tracks them from a previous deploy.
+func (man *ManifestInterpreter) doVariables(
+	ctx context.Context,
+	f *cmdutil.Factory,
+	conf *contracts.AzionApplicationOptions,
+	manifest *contracts.Manifest,
+	msgs *[]string) error {
+	variableConf := []contracts.AzionJsonDataVariable{}
+	if len(manifest.Variables) == 0 {
+		conf.Variables = variableConf
+		return nil
+	}
+
+	values, err := man.variableValues(manifest)
+	if err != nil {
+		return err
+	}
+
+	client := apiVariables.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+	remote, err := listVariables(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	for _, variable := range manifest.Variables {
+		value := values[variable.Key]
+		request := apiVariables.Request{}
+		request.Key = variable.Key
+		request.Value = value
+		request.SetSecret(variable.Secret)
+
+		var uuid string
+		if found, ok := remote[variable.Key]; ok {
+			uuid = found.GetUuid()
+			if variableChanged(found, value, variable.Secret) {
+				request.Uuid = uuid
+				if _, err := client.Update(ctx, &request); err != nil {
+					return fmt.Errorf("%w: %s", msg.ErrorUpdateVariable, err.Error())
+				}
+				man.changed(ResourceVariable, variable.Key, uuid, PlanUpdate)
+				msgf := fmt.Sprintf(msg.ManifestUpdateVariable, variable.Key, variableDiff(found, value, variable.Secret))
+				logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+				*msgs = append(*msgs, msgf)
+			} else {
+				logger.Debug("Variable is up to date", zap.Any("Key", variable.Key))
+			}
+		} else {
+			created, err := client.Create(ctx, request)
+			if err != nil {
+				return fmt.Errorf("%w: %s", msg.ErrorCreateVariable, err.Error())
+			}
+			uuid = created.GetUuid()
+			if err := man.created(contracts.JournalEntry{
+				Resource: ResourceVariable,
+				Key:      uuid,
+				Name:     variable.Key,
+			}); err != nil {
+				return err
+			}
+			man.changed(ResourceVariable, variable.Key, uuid, PlanCreate)
+			msgf := fmt.Sprintf(msg.ManifestCreateVariable, variable.Key, apiVariables.MaskValue(value, variable.Secret))
+			logger.FInfoFlags(f.IOStreams.Out, msgf, f.Format, f.Out)
+			*msgs = append(*msgs, msgf)
+		}
+
+		VariableIds[variable.Key] = uuid
+		variableConf = append(variableConf, contracts.AzionJsonDataVariable{
+			Uuid:           uuid,
+			Key:            variable.Key,
+			Secret:         variable.Secret,
+			PreventDestroy: preventDestroy(variable.Lifecycle),
+		})
+	}
+
+	conf.Variables = variableConf
+	return nil
+}
+
+// variableValues reads the value of every variable of the manifest from the environment of the process or, when it is
+// not set there, from the .env file of the project. Values are never read from the manifest.
+func (man *ManifestInterpreter) variableValues(manifest *contracts.Manifest) (map[string]string, error) {
+	lookupEnv, err := man.environment()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, variable := range manifest.Variables {
+		value, ok := lookupEnv(variable.Key)
+		if !ok {
+			return nil, fmt.Errorf(msg.ErrorVariableValue.Error(), variable.Key, man.EnvPath)
+		}
+		values[variable.Key] = value
+	}
+	return values, nil
+}
+
+// listVariables returns the variables of the account by key
+func listVariables(ctx context.Context, client *apiVariables.Client) (map[string]apiVariables.Response, error) {
+	variables, err := client.List(ctx)
+	if err != nil {
+		logger.Debug("Error while listing variables", zap.Error(err))
+		return nil, fmt.Errorf("%w: %s", msg.ErrorListVariables, err.Error())
+	}
+	remote := make(map[string]apiVariables.Response)
+	for _, variable := range variables {
+		remote[variable.GetKey()] = variable
+	}
+	return remote, nil
+}
+
+// variableChanged tells whether a variable must be updated. The API never returns the value of a secret, so secrets
+// are always updated.
+func variableChanged(remote apiVariables.Response, value string, secret bool) bool {
+	return secret || remote.GetSecret() || remote.GetValue() != value
+}
+
+// variableDiff describes the change of a variable with the values of secrets masked
+func variableDiff(remote apiVariables.Response, value string, secret bool) string {
+	return fmt.Sprintf(msg.PlanVariableDiff,
+		apiVariables.MaskValue(remote.GetValue(), remote.GetSecret()), apiVariables.MaskValue(value, secret))
+}
+
+// planVariables computes the actions doVariables and deleteResources would take on the variables, leaving out the
+// ones that are up to date
+func (man *ManifestInterpreter) planVariables(
+	ctx context.Context,
+	f *cmdutil.Factory,
+	conf *contracts.AzionApplicationOptions,
+	manifest *contracts.Manifest,
+	protected protection) ([]contracts.ResourcePlan, error) {
+	plan := []contracts.ResourcePlan{}
+	declared := make(map[string]bool)
+	if len(manifest.Variables) > 0 {
+		values, err := man.variableValues(manifest)
+		if err != nil {
+			return nil, err
+		}
+		client := apiVariables.NewClient(f.HttpClient, f.Config.GetString("api_url"), f.Config.GetString("token"))
+		remote, err := listVariables(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, variable := range manifest.Variables {
+			declared[variable.Key] = true
+			value := values[variable.Key]
+			found, ok := remote[variable.Key]
+			if !ok {
+				plan = append(plan, contracts.ResourcePlan{
+					Resource: ResourceVariable,
+					Name:     variable.Key,
+					Action:   PlanCreate,
+					Details:  fmt.Sprintf(msg.PlanVariableValue, apiVariables.MaskValue(value, variable.Secret)),
+				})
+				continue
+			}
+			if variableChanged(found, value, variable.Secret) {
+				plan = append(plan, contracts.ResourcePlan{
+					Resource: ResourceVariable,
+					Name:     variable.Key,
+					Id:       found.GetUuid(),
+					Action:   PlanUpdate,
+					Details:  variableDiff(found, value, variable.Secret),
+				})
+			}
+		}
+	}
+
+	for _, variable := range conf.Variables {
+		if declared[variable.Key] {
+			continue
+		}
+		plan = append(plan, man.pruneAction(contracts.ResourcePlan{
+			Resource: ResourceVariable,
+			Name:     variable.Key,
+			Id:       variable.Uuid,
+			Action:   PlanDelete,
+			Details:  msg.PlanNotReferenced,
+		}, protected))
+	}
+	return plan, nil
+}
@@ -0,0 +1,25 @@
+package output
+
+import (
+	"github.com/aziontech/azion-cli/pkg/contracts"
+)
+
+// DeployOutput prints the messages of a deploy along with its report when a format is chosen.
+// The messages were already shown as the deploy ran, so nothing is printed otherwise.
+type DeployOutput struct {
+	Messages []string               `json:"messages" yaml:"messages" toml:"messages"`
+	Report   contracts.DeployReport `json:"report" yaml:"report" toml:"report"`
+	GeneralOutput
+}
+
+func (d *DeployOutput) Format() (bool, error) {
+	if len(d.Flags.Format) > 0 || len(d.Flags.Out) > 0 {
+		err := format(d, d.GeneralOutput)
+		if err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+func (d *DeployOutput) Output() {}
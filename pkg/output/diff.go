@@ -0,0 +1,42 @@
+package output
+
+import (
+	"github.com/aziontech/azion-cli/pkg/contracts"
+)
+
+type DiffOutput struct {
+	GeneralOutput `json:"-" yaml:"-" toml:"-"`
+	Drifts        []contracts.ResourceDrift `json:"drifts" yaml:"drifts" toml:"drifts"`
+}
+
+func (d *DiffOutput) Format() (bool, error) {
+	formated := false
+	if len(d.Flags.Format) > 0 || len(d.Flags.Out) > 0 {
+		formated = true
+		err := format(d, d.GeneralOutput)
+		if err != nil {
+			return formated, err
+		}
+	}
+	return formated, nil
+}
+
+// Output prints a line for each field that drifted, in the order the resources appear in the manifest
+func (d *DiffOutput) Output() {
+	listOut := ListOutput{
+		GeneralOutput: d.GeneralOutput,
+		Columns:       []string{"RESOURCE", "NAME", "ID", "FIELD", "MANIFEST", "REMOTE", "DETAILS"},
+	}
+	for _, drift := range d.Drifts {
+		listOut.Lines = append(listOut.Lines, []string{
+			drift.Resource,
+			drift.Name,
+			drift.Id,
+			drift.Field,
+			drift.Manifest,
+			drift.Remote,
+			drift.Details,
+		})
+	}
+	listOut.Output()
+}
@@ -1,6 +1,7 @@
 package output
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -25,6 +26,15 @@ func (e *ErrorOutput) Format() (bool, error) {
 	return formated, nil
 }
 
+// exitCode lets errors such as the ones of non-interactive mode exit with their own status
+func exitCode(err error) int {
+	var coded interface{ ExitCode() int }
+	if errors.As(err, &coded) {
+		return coded.ExitCode()
+	}
+	return 1
+}
+
 func (e *ErrorOutput) Output() {
 	if e.Err != nil {
 		format := fmt.Sprintf
@@ -32,6 +42,6 @@ func (e *ErrorOutput) Output() {
 			format = color.New(color.FgRed).SprintfFunc()
 		}
 		logger.FInfo(os.Stderr, format("Error: %s", e.Err.Error()))
-		os.Exit(1)
+		os.Exit(exitCode(e.Err))
 	}
 }
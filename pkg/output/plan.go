@@ -0,0 +1,56 @@
+package output
+
+import (
+	"github.com/aziontech/azion-cli/pkg/contracts"
+)
+
+type PlanOutput struct {
+	GeneralOutput `json:"-" yaml:"-" toml:"-"`
+	Actions       []contracts.ResourcePlan
+}
+
+func (p *PlanOutput) Format() (bool, error) {
+	formated := false
+	if len(p.Flags.Format) > 0 || len(p.Flags.Out) > 0 {
+		formated = true
+		grouped := make(map[string][]contracts.ResourcePlan)
+		for _, action := range p.Actions {
+			grouped[action.Resource] = append(grouped[action.Resource], action)
+		}
+		err := format(grouped, p.GeneralOutput)
+		if err != nil {
+			return formated, err
+		}
+	}
+	return formated, nil
+}
+
+// Output prints the planned actions as a table, keeping actions of the same
+// resource type together in the order the types first appear
+func (p *PlanOutput) Output() {
+	order := []string{}
+	grouped := make(map[string][]contracts.ResourcePlan)
+	for _, action := range p.Actions {
+		if _, ok := grouped[action.Resource]; !ok {
+			order = append(order, action.Resource)
+		}
+		grouped[action.Resource] = append(grouped[action.Resource], action)
+	}
+
+	listOut := ListOutput{
+		GeneralOutput: p.GeneralOutput,
+		Columns:       []string{"RESOURCE", "NAME", "ID", "ACTION", "DETAILS"},
+	}
+	for _, resource := range order {
+		for _, action := range grouped[resource] {
+			listOut.Lines = append(listOut.Lines, []string{
+				action.Resource,
+				action.Name,
+				action.Id,
+				action.Action,
+				action.Details,
+			})
+		}
+	}
+	listOut.Output()
+}
@@ -1,7 +1,9 @@
 package vulcan
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -21,6 +23,13 @@ const (
 
 var versionVulcan = "@latest"
 
+// pinnedVersion accepts exact versions and the semver ranges npm resolves, and nothing the shell would interpret
+var pinnedVersion = regexp.MustCompile(`^[0-9A-Za-z^~<>=*|.+ -]+$`)
+
+// exactVersion matches a full MAJOR.MINOR.PATCH version, with an optional prerelease. npm takes anything shorter, such
+// as 2 or 2.5, as a range
+var exactVersion = regexp.MustCompile(`^v?[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.-]+)?$`)
+
 type VulcanPkg struct {
 	Command          func(flags, params string, f *cmdutil.Factory) string
 	CheckVulcanMajor func(currentVersion string, f *cmdutil.Factory, vulcan *VulcanPkg) error
@@ -43,6 +52,79 @@ func command(flags, params string, f *cmdutil.Factory) string {
 	return fmt.Sprintf(installEdgeFunctions, flags, versionVulcan, params)
 }
 
+// Pin makes the commands run the edge-functions version a project pins in azion.json, an exact version or a semver
+// range, instead of the one kept in the settings; it warns when the pinned major isn't the one the CLI supports
+func Pin(version string, f *cmdutil.Factory) error {
+	version = strings.TrimSpace(version)
+	if err := validatePin(version); err != nil {
+		return err
+	}
+
+	if major, ok := pinnedMajor(version); ok && major != currentMajor {
+		logger.FInfoFlags(f.IOStreams.Out, fmt.Sprintf(msg.PinnedMajor, version, major, currentMajor), f.Format, f.Out)
+	}
+
+	if isExact(version) {
+		versionVulcan = "@" + version
+		return nil
+	}
+	// ranges hold characters the shell would interpret
+	versionVulcan = "@'" + version + "'"
+	return nil
+}
+
+// Resolve returns the exact version a pin names: an exact version as it is, and the highest release a semver range
+// accepts, as npm reports it
+func Resolve(version string, run func(comm string) (string, error)) (string, error) {
+	version = strings.TrimSpace(version)
+	if err := validatePin(version); err != nil {
+		return "", err
+	}
+	if isExact(version) {
+		return version, nil
+	}
+
+	out, err := run(fmt.Sprintf("npm view edge-functions@'%s' version --json", version))
+	if err != nil {
+		return "", fmt.Errorf(msg.ErrorResolveVersion.Error(), version, err)
+	}
+	// npm reports a single version as a string and several ones as a list, in ascending order
+	versions := []string{}
+	if err := json.Unmarshal([]byte(out), &versions); err != nil {
+		var single string
+		if err := json.Unmarshal([]byte(out), &single); err != nil {
+			return "", fmt.Errorf(msg.ErrorResolveVersion.Error(), version, err)
+		}
+		versions = []string{single}
+	}
+	if len(versions) == 0 || versions[len(versions)-1] == "" {
+		return "", fmt.Errorf(msg.ErrorResolveVersion.Error(), version, msg.NoMatchingRelease)
+	}
+	return versions[len(versions)-1], nil
+}
+
+func validatePin(version string) error {
+	if !pinnedVersion.MatchString(version) || strings.HasPrefix(version, "-") {
+		return fmt.Errorf(msg.ErrorPinnedVersion.Error(), version)
+	}
+	return nil
+}
+
+func isExact(version string) bool {
+	return exactVersion.MatchString(version)
+}
+
+// pinnedMajor returns the major of the lowest version a pin accepts, when it names one
+func pinnedMajor(version string) (int, bool) {
+	version = strings.TrimLeft(version, "^~<>= v")
+	end := strings.IndexFunc(version, func(r rune) bool { return r < '0' || r > '9' })
+	if end == -1 {
+		end = len(version)
+	}
+	major, err := strconv.Atoi(version[:end])
+	return major, err == nil
+}
+
 func checkVulcanMajor(currentVersion string, f *cmdutil.Factory, vulcan *VulcanPkg) error {
 	parts := strings.Split(currentVersion, ".")
 	// strings.Split will always return at least one element, so parts will always be len>0
@@ -168,3 +168,145 @@ func TestCheckVulcanMajor(t *testing.T) {
 		})
 	}
 }
+
+func TestPin(t *testing.T) {
+	logger.New(zapcore.DebugLevel)
+	defer func() { versionVulcan = "@latest" }()
+
+	tests := []struct {
+		name            string
+		version         string
+		expectedVersion string
+		warning         bool
+		wantErr         bool
+	}{
+		{
+			name:            "exact version",
+			version:         "2.5.0",
+			expectedVersion: "@2.5.0",
+		},
+		{
+			name:            "semver range",
+			version:         "^2.5.0",
+			expectedVersion: "@'^2.5.0'",
+		},
+		{
+			name:            "partial version",
+			version:         "2.5",
+			expectedVersion: "@'2.5'",
+		},
+		{
+			name:            "different major",
+			version:         ">=3.0.0 <4",
+			expectedVersion: "@'>=3.0.0 <4'",
+			warning:         true,
+		},
+		{
+			name:    "shell characters",
+			version: "2.5.0; rm -rf /",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versionVulcan = "@latest"
+			f, stdout, _ := testutils.NewFactory(nil)
+			err := Pin(tt.version, f)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Pin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if versionVulcan != tt.expectedVersion {
+				t.Errorf("versionVulcan = %v, expectedVersion %v", versionVulcan, tt.expectedVersion)
+			}
+			if warned := stdout.Len() > 0; warned != tt.warning {
+				t.Errorf("Pin() warned = %v, warning %v", warned, tt.warning)
+			}
+		})
+	}
+
+	t.Run("no warning in the JSON output", func(t *testing.T) {
+		f, stdout, _ := testutils.NewFactory(nil)
+		f.Format = "json"
+		if err := Pin(">=3.0.0 <4", f); err != nil {
+			t.Fatalf("Pin() error = %v", err)
+		}
+		if stdout.Len() > 0 {
+			t.Errorf("Pin() printed %q with --format json", stdout.String())
+		}
+	})
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		output   string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "exact version",
+			version:  "2.5.0",
+			expected: "2.5.0",
+		},
+		{
+			name:     "prerelease",
+			version:  "v2.6.0-beta.1",
+			expected: "v2.6.0-beta.1",
+		},
+		{
+			name:     "major only",
+			version:  "2",
+			output:   `["2.5.0", "2.6.0"]`,
+			expected: "2.6.0",
+		},
+		{
+			name:     "range matching several releases",
+			version:  "^2.5.0",
+			output:   `["2.5.0", "2.5.1", "2.6.0"]`,
+			expected: "2.6.0",
+		},
+		{
+			name:     "range matching one release",
+			version:  "~2.5.1",
+			output:   `"2.5.1"`,
+			expected: "2.5.1",
+		},
+		{
+			name:    "range matching no release",
+			version: "^9.0.0",
+			output:  "",
+			wantErr: true,
+		},
+		{
+			name:    "shell characters",
+			version: "^2.5.0'; rm -rf /",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			version, err := Resolve(tt.version, func(comm string) (string, error) {
+				ran = true
+				if comm != "npm view edge-functions@'"+tt.version+"' version --json" {
+					t.Errorf("Resolve() ran %s", comm)
+				}
+				return tt.output, nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if version != tt.expected {
+				t.Errorf("Resolve() = %v, expected %v", version, tt.expected)
+			}
+			// exact versions don't need npm
+			if ran && tt.version == tt.expected {
+				t.Errorf("Resolve() ran npm for an exact version")
+			}
+		})
+	}
+}
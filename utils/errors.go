@@ -26,6 +26,7 @@ var (
 	ErrorToken401                   = errors.New("The token doesn't exist or has expired. Manage your personal tokens on RTM using the Account Menu > Personal Tokens and configure a valid token with the command 'azion -t <my_token>'")
 	ErrorForbidden403               = errors.New("You do not have the permissions to access the API. Make sure the feature is enabled in your profile")
 	ErrorNotFound404                = errors.New("The given ID or API's endpoint doesn't exist or isn't available. Check that the identifying information is correct")
+	ErrorTooManyRequests429         = errors.New("Too many requests were sent to the API in a short time. Wait a few seconds and try again")
 	ErrorFetchingTemplates          = errors.New("Failed to fetch templates from the Azion's GitHub remote repository. Verify the connectivity to the repository https://github.com/aziontech/azioncli-template and try again")
 	ErrorMovingFiles                = errors.New("Failed to initialize your project with the Azion template. Please verify if you have write permissions to this directory")
 	ErrorInvalidOption              = errors.New("You must inform 'yes' or 'no' as input, or force --yes or --no by using the flags")
@@ -37,6 +38,9 @@ var (
 	ErrorUnmarshalAzionJsonFile     = errors.New("Failed to parse the given 'azion.json' file. Verify if the file format is JSON or fix its content according to the JSON format specification at https://www.json.org/json-en.html")
 	ErrorMarshalAzionJsonFile       = errors.New("Failed to encode the given 'azion.json' file. Verify if the file format is JSON or fix its content according to the JSON format specification at https://www.json.org/json-en.html")
 	ErrorWritingAzionJsonFile       = errors.New("Failed to write in the given 'azion.json' file. Verify if the file is writable and/or you have access to it, if the data format is JSON, or fix the content according to the JSON format specification at https://www.json.org/json-en.html")
+	ErrorUnmarshalDeploymentsFile   = errors.New("Failed to parse the given 'deployments.json' file. Verify if the file format is JSON or fix its content according to the JSON format specification at https://www.json.org/json-en.html")
+	ErrorWritingDeploymentsFile     = errors.New("Failed to write in the given 'deployments.json' file. Verify if the file is writable and/or you have access to it")
+	ErrorInvalidEnvironment         = errors.New("Invalid environment name '%s'. Use only lowercase letters, numbers, '-' and '_'")
 	ErrorTimeoutAPICall             = errors.New("CLI's request has timed out during communication with Azion. Verify if it has completed successfully or wait some time and try the command again")
 	ErrorCreateFile                 = errors.New("Failed to create %s file")
 	ErrorProductNotOwned            = errors.New("This account does not own the following product")
@@ -49,6 +53,8 @@ var (
 	ErrorNameInUse                  = errors.New("The name you've selected is already in use by another resource. Please choose a different name. Run 'azion list [resource]' to see all your resources")
 	ErrorCancelledContextInput      = errors.New("Execution interrupted by the user. All interactions of this flow were lost.")
 	ErrorWriteSettings              = errors.New("Failed to write settings.toml file: %w")
+	ErrorNonInteractive             = errors.New("This command needs an answer it can't ask for in non-interactive mode. Send the %s flag and try again")
+	ErrorNonInteractivePrompt       = errors.New("This command needs an answer it can't ask for in non-interactive mode: '%s'. Send the flag that answers it and try again")
 )
 
 const (
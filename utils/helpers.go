@@ -11,6 +11,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -254,6 +255,120 @@ func WriteAzionJsonContent(conf *contracts.AzionApplicationOptions, confPath str
 	return nil
 }
 
+// GetDeploymentHistory reads the deploy history kept in deployments.json; a missing file means no deploy was recorded yet
+func GetDeploymentHistory(confPath string) (*contracts.DeploymentHistory, error) {
+	wd, err := GetWorkingDir()
+	if err != nil {
+		return nil, err
+	}
+
+	history := &contracts.DeploymentHistory{}
+	file, err := os.ReadFile(path.Join(wd, confPath, "deployments.json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return history, nil
+		}
+		logger.Debug("Error reading deployments.json file", zap.Error(err))
+		return nil, ErrorReadingFile
+	}
+
+	if err := json.Unmarshal(file, history); err != nil {
+		logger.Debug("Error unmarshalling deployments.json file", zap.Error(err))
+		return nil, ErrorUnmarshalDeploymentsFile
+	}
+
+	return history, nil
+}
+
+func WriteDeploymentHistory(history *contracts.DeploymentHistory, confPath string) error {
+	wd, err := GetWorkingDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return ErrorWritingDeploymentsFile
+	}
+
+	err = os.WriteFile(path.Join(wd, confPath, "deployments.json"), data, 0644)
+	if err != nil {
+		return ErrorWritingDeploymentsFile
+	}
+
+	return nil
+}
+
+// ExitCodeNonInteractive is the exit status of a command that stopped instead of prompting in non-interactive mode
+const ExitCodeNonInteractive = 3
+
+// NonInteractive mirrors the NonInteractive flag of the factory for the prompts of this package, which don't receive
+// it: they answer with their defaults, or fail with a NonInteractiveError, instead of waiting for input.
+// The root command sets it before any command runs.
+var NonInteractive bool
+
+// NonInteractiveError is returned in non-interactive mode in place of a prompt; Flag is the flag that answers it.
+// The shared prompts don't know that flag, so they name the question they would have asked in Prompt instead
+type NonInteractiveError struct {
+	Flag   string
+	Prompt string
+}
+
+func (e *NonInteractiveError) Error() string {
+	if e.Flag == "" {
+		return fmt.Sprintf(ErrorNonInteractivePrompt.Error(), strings.TrimSpace(e.Prompt))
+	}
+	return fmt.Sprintf(ErrorNonInteractive.Error(), e.Flag)
+}
+
+func (e *NonInteractiveError) Is(target error) bool {
+	return target == ErrorNonInteractive
+}
+
+func (e *NonInteractiveError) ExitCode() int {
+	return ExitCodeNonInteractive
+}
+
+// StdinIsTerminal tells whether prompts can be answered, which is not the case when stdin is a pipe or a file
+func StdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// DefaultEnvironment is the environment of projects whose azion.json doesn't name one
+const DefaultEnvironment = "production"
+
+var environmentName = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// EnvironmentConfPath returns the configuration directory of an environment of the project. The environment named
+// in the project's azion.json uses the directory itself, while every other one has its own directory under
+// <confPath>/environments, with its own azion.json and deploy history.
+func EnvironmentConfPath(confPath, environment string) (string, error) {
+	if environment == "" {
+		return confPath, nil
+	}
+	if !environmentName.MatchString(environment) {
+		return "", fmt.Errorf(ErrorInvalidEnvironment.Error(), environment)
+	}
+
+	conf, err := GetAzionJsonContent(confPath)
+	if err != nil {
+		return "", err
+	}
+
+	current := conf.Env
+	if current == "" {
+		current = DefaultEnvironment
+	}
+	if environment == current {
+		return confPath, nil
+	}
+
+	return path.Join(confPath, "environments", environment), nil
+}
+
 // Returns the correct error message for each HTTP Status code
 func ErrorPerStatusCode(httpResp *http.Response, err error) error {
 
@@ -282,6 +397,9 @@ func ErrorPerStatusCode(httpResp *http.Response, err error) error {
 	case 409:
 		return ErrorNameInUse
 
+	case 429:
+		return ErrorTooManyRequests429
+
 	default:
 		return err
 
@@ -421,6 +539,9 @@ func IsEmpty(value interface{}) bool {
 
 func GetPackageManager() (string, error) {
 	opts := []string{"npm", "yarn"}
+	if NonInteractive {
+		return opts[0], nil
+	}
 	answer := ""
 	prompt := &survey.Select{
 		Message: "Choose a package manager:",
@@ -434,6 +555,9 @@ func GetPackageManager() (string, error) {
 }
 
 func AskInputEmpty(msg string) (string, error) {
+	if NonInteractive {
+		return "", nil
+	}
 	qs := []*survey.Question{
 		{
 			Name:     "id",
@@ -457,6 +581,9 @@ func AskInputEmpty(msg string) (string, error) {
 }
 
 func AskInput(msg string) (string, error) {
+	if NonInteractive {
+		return "", &NonInteractiveError{Prompt: msg}
+	}
 	qs := []*survey.Question{
 		{
 			Name:     "id",
@@ -480,6 +607,9 @@ func AskInput(msg string) (string, error) {
 }
 
 func AskPassword(msg string) (string, error) {
+	if NonInteractive {
+		return "", &NonInteractiveError{Prompt: msg}
+	}
 	qs := []*survey.Question{
 		{
 			Name:     "id",
@@ -543,6 +673,9 @@ func FlagFileUnmarshalJSON(path string, request interface{}) error {
 }
 
 func Select(label string, items []string) (string, error) {
+	if NonInteractive {
+		return "", &NonInteractiveError{Prompt: label}
+	}
 	prompt := promptui.Select{
 		Label: label,
 		Items: items,
@@ -569,10 +702,14 @@ func Concat(strs ...string) string {
 // - globalFlagAll: a boolean flag to skip the confirmation and return true directly.
 // - msg: the message to display as part of the confirmation prompt.
 // - defaultYes: a boolean flag indicating whether pressing enter should default to 'yes'.
+// In non-interactive mode it returns defaultYes without asking.
 func Confirm(globalFlagAll bool, msg string, defaultYes bool) bool {
 	if globalFlagAll {
 		return true
 	}
+	if NonInteractive {
+		return defaultYes
+	}
 
 	fmt.Printf("🤔 \x1b[32m%s \x1b[0m", msg)
 	scanner := bufio.NewScanner(os.Stdin)
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -158,3 +160,64 @@ func TestIsEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironmentConfPath(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, os.Chdir(dir))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "azion"), 0755))
+	require.NoError(t, WriteAzionJsonContent(&contracts.AzionApplicationOptions{Name: "project"}, "azion"))
+
+	tests := []struct {
+		name        string
+		environment string
+		want        string
+		wantErr     bool
+	}{
+		{name: "no environment", environment: "", want: "azion"},
+		{name: "environment of azion.json", environment: "production", want: "azion"},
+		{name: "other environment", environment: "staging", want: "azion/environments/staging"},
+		{name: "invalid name", environment: "../staging", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EnvironmentConfPath("azion", tt.environment)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNonInteractiveError(t *testing.T) {
+	err := fmt.Errorf("init: %w", &NonInteractiveError{Flag: "--name"})
+	require.ErrorIs(t, err, ErrorNonInteractive)
+	require.Contains(t, err.Error(), "Send the --name flag")
+
+	var coded interface{ ExitCode() int }
+	require.True(t, errors.As(err, &coded))
+	require.Equal(t, ExitCodeNonInteractive, coded.ExitCode())
+
+	t.Run("shared prompts", func(t *testing.T) {
+		NonInteractive = true
+		defer func() { NonInteractive = false }()
+
+		_, err := AskInput("Enter the Edge Application's ID: ")
+		require.ErrorIs(t, err, ErrorNonInteractive)
+		require.Contains(t, err.Error(), "'Enter the Edge Application's ID:'")
+
+		answer, err := AskInputEmpty("Enter the prefix: ")
+		require.NoError(t, err)
+		require.Empty(t, answer)
+
+		require.False(t, Confirm(false, "Delete it? (y/N)", false))
+		require.True(t, Confirm(false, "Add it? (Y/n)", true))
+	})
+}